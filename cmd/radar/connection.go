@@ -0,0 +1,23 @@
+package main
+
+import (
+	"time"
+
+	"github.com/nospy/albion-openradar/internal/logger"
+	"github.com/nospy/albion-openradar/internal/photon"
+)
+
+// onGameConnect logs a new game server connection and marks it in a pcapng
+// recording.
+func (app *App) onGameConnect(c photon.Conn) {
+	logger.PrintInfo("NET", "Connected to game server %s from %s", c.Server, c.Client)
+	app.captureManager.Annotate("connect " + c.Server.String())
+}
+
+// onGameDisconnect logs the end of a connection, which is either a logout or
+// the client moving to another server.
+func (app *App) onGameDisconnect(c photon.Conn) {
+	logger.PrintInfo("NET", "Disconnected from game server %s after %s",
+		c.Server, c.Disconnected.Sub(c.Connected).Round(time.Second))
+	app.captureManager.Annotate("disconnect " + c.Server.String())
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"math"
+	"os"
+	"os/signal"
+	"runtime"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/nospy/albion-openradar/internal/logger"
+)
+
+// Process exit codes, so a service manager can tell a stop from a failure.
+const (
+	exitOK      = 0 // stopped by SIGINT/SIGTERM
+	exitStartup = 1 // configuration, interfaces, TLS or app setup failed
+	exitUsage   = 2 // invalid command-line flags (same code the flag package uses)
+	exitServer  = 3 // headless: the web server could not bind or stopped on its own
+)
+
+// runHeadless is the service-mode counterpart of the TUI: logs go straight to
+// stdout and a stats line replaces the dashboard. It blocks until a signal or
+// a web server failure and returns the exit code.
+func (app *App) runHeadless(statsInterval time.Duration) int {
+	if err := app.startServers(); err != nil {
+		return exitServer
+	}
+
+	if statsInterval > 0 {
+		app.wg.Go(func() { app.logStatsEvery(statsInterval) })
+	}
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sig)
+
+	select {
+	case s := <-sig:
+		logger.PrintInfo("APP", "Received %v", s)
+		return exitOK
+	case err := <-app.serverErr:
+		logger.PrintError("APP", "Web server stopped: %v", err)
+		return exitServer
+	}
+}
+
+// statsSample holds the previous totals so a stats line can report rates.
+type statsSample struct {
+	at      time.Time
+	packets uint64
+	rx      uint64
+	tx      uint64
+}
+
+func (app *App) logStatsEvery(interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	prev := app.sampleStats()
+	for {
+		select {
+		case <-app.ctx.Done():
+			return
+		case <-t.C:
+			prev = app.logStats(prev)
+		}
+	}
+}
+
+func (app *App) sampleStats() statsSample {
+	return statsSample{
+		at:      time.Now(),
+		packets: atomic.LoadUint64(&app.packetsProcessed),
+		rx:      app.captureManager.BytesReceived(),
+		tx:      app.wsHandler.Stats().BytesSent,
+	}
+}
+
+// logStats prints the numbers the TUI footer and Stats tab show, as fields.
+func (app *App) logStats(prev statsSample) statsSample {
+	cur := app.sampleStats()
+	secs := cur.at.Sub(prev.at).Seconds()
+	rate := func(now, before uint64) float64 {
+		if secs <= 0 || now < before {
+			return 0
+		}
+		return float64(now-before) / secs
+	}
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	ws := app.wsHandler.Stats()
+	logs := app.logger.GetStats()
+
+	logger.PrintFields("STATS", "Radar stats",
+		logger.Field{Key: "packets", Value: cur.packets},
+		logger.Field{Key: "packets_per_sec", Value: roundTo(rate(cur.packets, prev.packets), 1)},
+		logger.Field{Key: "errors", Value: atomic.LoadUint64(&app.packetsErrors)},
+		logger.Field{Key: "encrypted", Value: atomic.LoadUint64(&app.packetsEncrypted)},
+		logger.Field{Key: "captures", Value: len(app.captureManager.State().Active)},
+		logger.Field{Key: "rx_bytes", Value: cur.rx},
+		logger.Field{Key: "rx_bytes_per_sec", Value: roundTo(rate(cur.rx, prev.rx), 0)},
+		logger.Field{Key: "ws_clients", Value: app.wsHandler.ClientCount()},
+		logger.Field{Key: "ws_batches", Value: ws.BatchesSent},
+		logger.Field{Key: "ws_messages", Value: ws.MessagesSent},
+		logger.Field{Key: "ws_queue", Value: ws.MessagesQueue},
+		logger.Field{Key: "tx_bytes", Value: cur.tx},
+		logger.Field{Key: "tx_bytes_per_sec", Value: roundTo(rate(cur.tx, prev.tx), 0)},
+		logger.Field{Key: "log_entries", Value: logs.TotalEntries},
+		logger.Field{Key: "heap_mb", Value: roundTo(float64(mem.Alloc)/1024/1024, 1)},
+		logger.Field{Key: "sys_mb", Value: roundTo(float64(mem.Sys)/1024/1024, 1)},
+		logger.Field{Key: "goroutines", Value: runtime.NumGoroutine()},
+	)
+	return cur
+}
+
+func roundTo(v float64, decimals int) float64 {
+	p := math.Pow10(decimals)
+	return math.Round(v*p) / p
+}
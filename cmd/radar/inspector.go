@@ -0,0 +1,77 @@
+package main
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nospy/albion-openradar/internal/photon/codestats"
+	"github.com/nospy/albion-openradar/internal/ui"
+)
+
+const (
+	// inspectorFlushInterval paces InspectorMsg so a busy zone does not flood
+	// the Bubble Tea event loop with one message per packet.
+	inspectorFlushInterval = 250 * time.Millisecond
+	// inspectorQueueSize bounds the messages waiting for a flush; older ones
+	// are dropped and counted.
+	inspectorQueueSize = 500
+)
+
+// inspectorFeed collects decoded messages for the TUI Inspector tab. It stays
+// disabled in headless mode, where nothing drains it.
+type inspectorFeed struct {
+	enabled atomic.Bool
+
+	mu      sync.Mutex
+	queue   []ui.InspectedMessage
+	dropped uint64
+}
+
+func (f *inspectorFeed) add(kind codestats.Kind, code int, params map[byte]any, returnCode int16, debugMessage string) {
+	if !f.enabled.Load() {
+		return
+	}
+	m := ui.InspectedMessage{
+		Time:         time.Now(),
+		Kind:         string(kind),
+		Code:         code,
+		Name:         codestats.Name(kind, code),
+		ReturnCode:   returnCode,
+		DebugMessage: debugMessage,
+		Params:       params,
+	}
+	f.mu.Lock()
+	if len(f.queue) >= inspectorQueueSize {
+		f.queue = f.queue[1:]
+		f.dropped++
+	}
+	f.queue = append(f.queue, m)
+	f.mu.Unlock()
+}
+
+func (f *inspectorFeed) drain() ui.InspectorMsg {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	msg := ui.InspectorMsg{Messages: f.queue, Dropped: f.dropped}
+	f.queue, f.dropped = nil, 0
+	return msg
+}
+
+// feedInspector sends the queued messages to the dashboard until shutdown.
+func (app *App) feedInspector() {
+	app.inspector.enabled.Store(true)
+	ticker := time.NewTicker(inspectorFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-app.ctx.Done():
+			return
+		case <-ticker.C:
+			if msg := app.inspector.drain(); len(msg.Messages) > 0 || msg.Dropped > 0 {
+				app.program.Send(msg)
+			}
+		}
+	}
+}
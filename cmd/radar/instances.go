@@ -0,0 +1,198 @@
+package main
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/nospy/albion-openradar/internal/photon"
+	"github.com/nospy/albion-openradar/internal/photon/entities"
+	"github.com/nospy/albion-openradar/internal/server"
+	"github.com/nospy/albion-openradar/internal/ui"
+)
+
+const (
+	// instanceIdleExpiry is how long a game client that went quiet is kept.
+	instanceIdleExpiry = 10 * time.Minute
+	// instanceActiveWindow is how recently a client must have been heard
+	// from to stay the primary one.
+	instanceActiveWindow = time.Minute
+)
+
+// clientInstance is one game client on this PC, told apart by its local UDP
+// port. Each has its own parser and entity state, so that two clients
+// (multibox) do not merge into one radar view. Port 0 collects the packets
+// whose direction is unknown.
+type clientInstance struct {
+	port uint16
+
+	// mu serializes the parser: every capturer feeds packets from its own
+	// goroutine.
+	mu       sync.Mutex
+	parser   *photon.PhotonParser
+	entities *entities.Tracker
+
+	// firstSeen, lastSeen and packets are guarded by the set's mutex.
+	firstSeen time.Time
+	lastSeen  time.Time
+	packets   uint64
+}
+
+// receive hands one packet to the instance's parser.
+func (c *clientInstance) receive(payload []byte, meta photon.PacketMeta) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.parser.ReceivePacketMeta(payload, meta)
+}
+
+// instanceSet routes packets to the client instance they belong to, creating
+// instances as new local ports show up and forgetting idle ones.
+type instanceSet struct {
+	mu     sync.Mutex
+	byPort map[uint16]*clientInstance
+	// newInstance builds the parser and state of a new instance.
+	newInstance func(port uint16) *clientInstance
+}
+
+func newInstanceSet(newInstance func(port uint16) *clientInstance) *instanceSet {
+	return &instanceSet{byPort: make(map[uint16]*clientInstance), newInstance: newInstance}
+}
+
+// route returns the instance of a packet captured with meta, counting it.
+func (s *instanceSet) route(meta photon.PacketMeta) *clientInstance {
+	port := meta.Client.Port()
+	now := meta.Time
+	if now.IsZero() {
+		now = time.Now()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	inst := s.byPort[port]
+	if inst == nil {
+		s.pruneLocked(now)
+		inst = s.newInstance(port)
+		inst.port, inst.firstSeen = port, now
+		s.byPort[port] = inst
+	}
+	inst.lastSeen = now
+	inst.packets++
+	return inst
+}
+
+func (s *instanceSet) pruneLocked(now time.Time) {
+	for port, inst := range s.byPort {
+		if now.Sub(inst.lastSeen) > instanceIdleExpiry {
+			delete(s.byPort, port)
+		}
+	}
+}
+
+// primary is the instance the TUI follows: the first client started among
+// those heard from in the last minute, else the one heard from last. It is
+// nil before any packet.
+func (s *instanceSet) primary(now time.Time) *clientInstance {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var best *clientInstance
+	bestActive := false
+	for _, inst := range s.byPort {
+		active := now.Sub(inst.lastSeen) <= instanceActiveWindow
+		switch {
+		case best == nil,
+			active && !bestActive,
+			active && inst.firstSeen.Before(best.firstSeen),
+			!active && !bestActive && inst.lastSeen.After(best.lastSeen):
+			best, bestActive = inst, active
+		}
+	}
+	return best
+}
+
+// instanceInfo is a copy of an instance's counters.
+type instanceInfo struct {
+	port      uint16
+	firstSeen time.Time
+	lastSeen  time.Time
+	packets   uint64
+	cluster   string
+}
+
+// list copies the instances with a known port, lowest port first.
+func (s *instanceSet) list() []instanceInfo {
+	s.mu.Lock()
+	out := make([]instanceInfo, 0, len(s.byPort))
+	trackers := make([]*entities.Tracker, 0, len(s.byPort))
+	for port, inst := range s.byPort {
+		if port == 0 {
+			continue
+		}
+		out = append(out, instanceInfo{port: port, firstSeen: inst.firstSeen, lastSeen: inst.lastSeen, packets: inst.packets})
+		trackers = append(trackers, inst.entities)
+	}
+	s.mu.Unlock()
+	for i, t := range trackers {
+		out[i].cluster = t.Cluster()
+	}
+	slices.SortFunc(out, func(a, b instanceInfo) int { return int(a.port) - int(b.port) })
+	return out
+}
+
+// newClientInstance builds the parser of one game client. Its callbacks carry
+// the instance so that entities and cluster notes stay apart.
+func (app *App) newClientInstance(port uint16) *clientInstance {
+	inst := &clientInstance{port: port, entities: entities.New()}
+	inst.parser = photon.NewPhotonParser(
+		func(e *photon.EventData) { app.onPhotonEvent(inst, e) },
+		func(r *photon.OperationRequest) { app.onPhotonRequest(inst, r) },
+		func(r *photon.OperationResponse) { app.onPhotonResponse(inst, r) },
+	)
+	inst.parser.OnEncrypted = app.onPhotonEncrypted
+	inst.parser.OnParseError = app.onPhotonParseError
+	inst.parser.Conns = app.conns
+	return inst
+}
+
+// clientInstances backs /api/instances: the detected game clients, with the
+// game server each one plays on.
+func (app *App) clientInstances() []server.ClientInstance {
+	servers := make(map[uint16]string)
+	for _, c := range app.conns.Conns() {
+		if _, seen := servers[c.Client.Port()]; !seen && c.Active() {
+			servers[c.Client.Port()] = c.Server.String()
+		}
+	}
+	var primary uint16
+	if p := app.instances.primary(time.Now()); p != nil {
+		primary = p.port
+	}
+	infos := app.instances.list()
+	out := make([]server.ClientInstance, len(infos))
+	for i, in := range infos {
+		out[i] = server.ClientInstance{
+			Port:      in.port,
+			FirstSeen: in.firstSeen,
+			LastSeen:  in.lastSeen,
+			Packets:   in.packets,
+			Cluster:   in.cluster,
+			Server:    servers[in.port],
+			Primary:   in.port == primary,
+		}
+	}
+	return out
+}
+
+// instancesForUI is the Config tab's list of game clients.
+func instancesForUI(list []server.ClientInstance) []ui.ClientInstance {
+	out := make([]ui.ClientInstance, len(list))
+	for i, c := range list {
+		out[i] = ui.ClientInstance{
+			Port:     c.Port,
+			Cluster:  c.Cluster,
+			Server:   c.Server,
+			Packets:  c.Packets,
+			LastSeen: c.LastSeen,
+			Primary:  c.Primary,
+		}
+	}
+	return out
+}
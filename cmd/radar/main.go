@@ -5,10 +5,14 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
 	"runtime"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"syscall"
@@ -20,6 +24,8 @@ import (
 	"github.com/nospy/albion-openradar/internal/capture"
 	"github.com/nospy/albion-openradar/internal/logger"
 	"github.com/nospy/albion-openradar/internal/photon"
+	"github.com/nospy/albion-openradar/internal/photon/codestats"
+	"github.com/nospy/albion-openradar/internal/photon/entities"
 	"github.com/nospy/albion-openradar/internal/server"
 	"github.com/nospy/albion-openradar/internal/ui"
 )
@@ -32,9 +38,9 @@ var (
 )
 
 const (
-	serverPort      = 5001
 	shutdownTimeout = 10 * time.Second
 	pcapCaptureDir  = "./logs/captures"
+	tlsCertDir      = "./certs"
 )
 
 type App struct {
@@ -45,16 +51,29 @@ type App struct {
 	httpServer     *server.HTTPServer
 	wsHandler      *server.WebSocketHandler
 	captureManager *capture.Manager
-	photonParser   *photon.PhotonParser
 	program        *tea.Program
 
 	// Packet statistics (atomic for thread safety)
 	packetsProcessed uint64
 	packetsErrors    uint64
 	packetsEncrypted uint64
+	// parseErrorBurst saves the packet buffer on a storm of parse errors
+	parseErrorBurst burstTrigger
+
+	// Messages per (kind, Albion code), for /metrics, /api/debug/codes and the Codes tab
+	codeStats *codestats.Counter
+	// Decoded messages for the TUI Inspector tab
+	inspector inspectorFeed
+	// One parser and entity tracker per game client on this PC (multibox);
+	// the Nearby tab shows the primary one
+	instances *instanceSet
+	// Photon connections to the game servers, for /api/connection and the TUI header
+	conns *photon.ConnTracker
 
 	// Server status (atomic for thread safety)
 	httpRunning int32
+	// serverErr receives the web server's error when it stops on its own
+	serverErr chan error
 }
 
 func main() {
@@ -64,18 +83,35 @@ func main() {
 		return
 	}
 
-	printBanner()
+	slog.SetDefault(logger.Slog())
+
+	if cfg.headless {
+		format, err := logger.ParseConsoleFormat(cfg.logFormat)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(exitUsage)
+		}
+		logger.SetConsoleFormat(format)
+		logger.PrintInfo("APP", "OpenRadar v%s (built: %s), headless", Version, BuildTime)
+	} else {
+		logger.PrintInfo("APP", "OpenRadar v%s (built: %s)", Version, BuildTime)
+	}
 
 	for {
-		shouldRestart := runApp(cfg)
+		shouldRestart, code := runApp(cfg)
 		if !shouldRestart {
+			if code != exitOK {
+				os.Exit(code)
+			}
 			break
 		}
-		fmt.Println("Restarting...")
+		logger.PrintInfo("APP", "Restarting...")
 	}
 }
 
-func runApp(cfg Config) bool {
+// runApp runs one radar session. It reports whether the user asked for a
+// restart from the dashboard, and otherwise the process exit code.
+func runApp(cfg Config) (restart bool, exitCode int) {
 	appDir, err := os.Getwd()
 	if err != nil {
 		exitWithError("Failed to get working directory", err)
@@ -94,6 +130,15 @@ func runApp(cfg Config) bool {
 	}
 
 	cfgPersisted, _ := capture.ReadConfig(appDir)
+	if err := logger.SetLevels(cfgPersisted.Logging.Levels); err != nil {
+		logger.PrintWarn("LOG", "Ignoring log levels in network.json: %v", err)
+	}
+	listen, err := resolveListen(cfg, cfgPersisted.Server)
+	if err != nil {
+		cancel()
+		exitWithError("Invalid listen address", err)
+	}
+
 	target := resolvePersisted(cfgPersisted, allIfaces, cfg.ipAddr)
 	if len(target) == 0 {
 		target = autoPickDefaults(allIfaces)
@@ -102,24 +147,53 @@ func runApp(cfg Config) bool {
 			for _, i := range target {
 				toPersist = append(toPersist, capture.PersistedInterface{Name: i.Name, Description: i.Description})
 			}
-			_ = capture.WriteConfig(appDir, capture.Config{CaptureInterfaces: toPersist})
+			_ = capture.MutateConfig(appDir, func(c *capture.Config) {
+				c.CaptureInterfaces = toPersist
+			})
 			logger.PrintInfo("NET", "Auto-selected %d interface(s). Change in /settings if needed.", len(target))
 		}
 	}
 
 	manager := capture.NewManager(ctx)
+	regions, err := capture.LoadRegionTable(appDir)
+	if err != nil {
+		logger.PrintWarn("NET", "Using the shipped region table: %v", err)
+	}
+	manager.SetRegions(regions)
+	if err := manager.SetFilter(cfgPersisted.CaptureFilter); err != nil {
+		logger.PrintWarn("NET", "Ignoring the capture filter in network.json: %v", err)
+	}
 
-	app, err := newApp(appDir, cfg, ctx, cancel, manager, allIfaces, cfgPersisted.Logging.ServerLogsEnabled)
+	app, err := newApp(appDir, cfg, listen, ctx, cancel, manager, allIfaces, cfgPersisted.Logging.ServerLogsEnabled)
 	if err != nil {
 		cancel()
 		manager.Close(context.Background())
 		exitWithError("Failed to create app", err)
 	}
 
+	if cfg.useTLS || cfgPersisted.Server.TLS {
+		if err := app.enableTLS(listen); err != nil {
+			cancel()
+			manager.Close(context.Background())
+			exitWithError("Failed to set up HTTPS", err)
+		}
+	}
+
 	if err := manager.Reconfigure(target); err != nil {
 		logger.PrintWarn("NET", "Some interfaces failed to open: %v", err)
 	}
 
+	if cfgPersisted.Logging.Retention == nil {
+		logger.PrintInfo("LOG", "No retention policy saved: logs and captures are kept. Set one in /settings.")
+	}
+	app.startRetention(appDir, cfgPersisted.Logging.RetentionOrDefault())
+	app.httpServer.SettingsAPI().OnRetentionChange(app.applyRetention)
+	app.applyRingBuffer(cfgPersisted.Logging.RingBufferOrDefault())
+	app.httpServer.SettingsAPI().OnRingBufferChange(app.applyRingBuffer)
+	manager.SetApplication("OpenRadar v" + Version)
+	_ = manager.SetRecordingFormat(cfgPersisted.Logging.RecordingFormatOrDefault()) // not recording yet
+	app.httpServer.SettingsAPI().OnRecordingFormatChange(app.applyRecordingFormat)
+
 	if cfgPersisted.Logging.PcapRecording {
 		if err := manager.StartRecording(pcapCaptureDir); err != nil {
 			logger.PrintWarn("PKT", "pcap recording could not start: %v", err)
@@ -129,10 +203,22 @@ func runApp(cfg Config) bool {
 		}
 	}
 
-	dashboard := ui.NewDashboard(Version, serverPort, cfg.devMode, capture.LANAddresses(), nil)
+	if cfg.headless {
+		app.startInterfaceWatch(cfg.ipAddr == "")
+		code := app.runHeadless(cfg.statsInterval)
+		app.shutdown()
+		return false, code
+	}
+
+	dashboard := ui.NewDashboard(Version, listen.LocalHost(), listen.Port, cfg.devMode, app.httpServer.LANAddresses(), nil)
+	if fp := app.httpServer.TLSFingerprint(); fp != "" {
+		dashboard = dashboard.WithTLS(fp)
+	}
+	dashboard = dashboard.WithControls(app.dashboardControls())
 	app.program = tea.NewProgram(dashboard, tea.WithAltScreen())
 
 	app.startCaptureStatePoll()
+	app.startInterfaceWatch(cfg.ipAddr == "")
 
 	// Set up log callback to send logs to dashboard
 	logger.SetLogCallback(func(level, tag, message string) {
@@ -148,6 +234,7 @@ func runApp(cfg Config) bool {
 
 	// Start stats updater
 	go app.updateStats()
+	go app.feedInspector()
 
 	// Run dashboard (blocking)
 	restartRequested := runInterface(app.program.Run, waitForInterrupt)
@@ -156,7 +243,7 @@ func runApp(cfg Config) bool {
 	logger.ClearLogCallback()
 	app.shutdown()
 
-	return restartRequested
+	return restartRequested, exitOK
 }
 
 func runInterface(runDashboard func() (tea.Model, error), waitForSignal func()) bool {
@@ -183,9 +270,17 @@ func waitForInterrupt() {
 
 // Config holds command-line configuration
 type Config struct {
-	devMode     bool
-	showVersion bool
-	ipAddr      string
+	devMode      bool
+	showVersion  bool
+	ipAddr       string
+	listenAddr   string
+	port         int
+	loopbackOnly bool
+	useTLS       bool
+
+	headless      bool
+	logFormat     string
+	statsInterval time.Duration
 }
 
 func parseFlags() Config {
@@ -193,23 +288,50 @@ func parseFlags() Config {
 	flag.BoolVar(&cfg.devMode, "dev", false, "Run in development mode (read files from disk)")
 	flag.BoolVar(&cfg.showVersion, "version", false, "Show version information")
 	flag.StringVar(&cfg.ipAddr, "ip", "", "Capture on the interface holding this IP, this run only (network.json is not written)")
+	flag.StringVar(&cfg.listenAddr, "listen", "", "Web server listen address, e.g. 127.0.0.1 or 0.0.0.0 (default: network.json, else all interfaces)")
+	flag.IntVar(&cfg.port, "port", 0, "Web server port (default: network.json, else 5001)")
+	flag.BoolVar(&cfg.loopbackOnly, "loopback", false, "Serve the web UI to this PC only, same as -listen 127.0.0.1")
+	flag.BoolVar(&cfg.useTLS, "tls", false, "Serve HTTPS/WSS with a self-signed certificate stored in ./certs")
+	flag.BoolVar(&cfg.headless, "headless", false, "Run without the console dashboard, logging to stdout (for services and containers)")
+	flag.StringVar(&cfg.logFormat, "log-format", "text", "Headless log format: text (logfmt) or json (one object per line)")
+	flag.DurationVar(&cfg.statsInterval, "stats-interval", time.Minute, "Headless: how often to log a stats line, 0 to disable")
 	flag.Parse()
 	return cfg
 }
 
-func printBanner() {
-	fmt.Printf("OpenRadar v%s\n", Version)
-	fmt.Println("====================")
+// resolveListen layers the CLI flags over network.json over the defaults.
+func resolveListen(cfg Config, persisted capture.ServerConfig) (server.ListenAddr, error) {
+	listen := server.ListenAddr{Host: persisted.ListenAddress, Port: persisted.Port}
+	if listen.Port == 0 {
+		listen.Port = server.DefaultPort
+	}
+	if cfg.listenAddr != "" {
+		listen.Host = cfg.listenAddr
+	}
+	if cfg.loopbackOnly {
+		if cfg.listenAddr != "" && cfg.listenAddr != "127.0.0.1" {
+			return server.ListenAddr{}, fmt.Errorf("-loopback conflicts with -listen %s", cfg.listenAddr)
+		}
+		listen.Host = "127.0.0.1"
+	}
+	if cfg.port != 0 {
+		listen.Port = cfg.port
+	}
+	if err := listen.Validate(); err != nil {
+		return server.ListenAddr{}, err
+	}
+	return listen, nil
 }
 
 func exitWithError(msg string, err error) {
-	fmt.Printf("%s: %v\n", msg, err)
-	os.Exit(1)
+	logger.PrintError("APP", "%s: %v", msg, err)
+	os.Exit(exitStartup)
 }
 
 func newApp(
 	appDir string,
 	cfg Config,
+	listen server.ListenAddr,
 	ctx context.Context,
 	cancel context.CancelFunc,
 	manager *capture.Manager,
@@ -217,9 +339,10 @@ func newApp(
 	serverLogsEnabled bool,
 ) (*App, error) {
 	log := logger.New("./logs", serverLogsEnabled)
+	logger.AttachSession(log)
 	wsHandler := server.NewWebSocketHandler(log)
 
-	httpServer, err := createHTTPServer(cfg.devMode, appDir, wsHandler, log, Version, BuildTime, manager, allIfaces)
+	httpServer, err := createHTTPServer(cfg.devMode, listen, appDir, wsHandler, log, Version, BuildTime, manager, allIfaces)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create HTTP server: %w", err)
 	}
@@ -231,14 +354,21 @@ func newApp(
 		wsHandler:      wsHandler,
 		httpServer:     httpServer,
 		captureManager: manager,
+		codeStats:      codestats.New(),
+		conns:          photon.NewConnTracker(),
+		serverErr:      make(chan error, 1),
+		parseErrorBurst: burstTrigger{
+			window:   capture.ParseErrorWindow,
+			cooldown: ringFlushCooldown,
+		},
 	}
-	app.photonParser = photon.NewPhotonParser(
-		app.onPhotonEvent,
-		app.onPhotonRequest,
-		app.onPhotonResponse,
-	)
-	app.photonParser.OnEncrypted = app.onPhotonEncrypted
-	app.photonParser.OnParseError = app.onPhotonParseError
+	httpServer.SetMetrics(app.writeMetrics)
+	httpServer.SetCodeStats(app.codeStats)
+	httpServer.SetConnections(app.conns)
+	app.conns.OnConnect = app.onGameConnect
+	app.conns.OnDisconnect = app.onGameDisconnect
+	app.instances = newInstanceSet(app.newClientInstance)
+	httpServer.SetInstances(app.clientInstances)
 
 	app.captureManager.OnPacket(app.handlePacket)
 
@@ -247,6 +377,7 @@ func newApp(
 
 func createHTTPServer(
 	devMode bool,
+	listen server.ListenAddr,
 	appDir string,
 	wsHandler *server.WebSocketHandler,
 	log *logger.Logger,
@@ -257,11 +388,11 @@ func createHTTPServer(
 ) (*server.HTTPServer, error) {
 	if devMode {
 		logger.PrintInfo("MODE", "Development mode: reading files from disk")
-		return server.NewHTTPServerDev(serverPort, appDir, wsHandler, log, version, buildTime, mgr, allIfaces, mgr, pcapCaptureDir)
+		return server.NewHTTPServerDev(listen, appDir, wsHandler, log, version, buildTime, mgr, allIfaces, mgr, pcapCaptureDir)
 	}
 	logger.PrintInfo("MODE", "Production mode: using embedded assets")
 	return server.NewHTTPServer(
-		serverPort,
+		listen,
 		assets.Images,
 		assets.Scripts,
 		assets.Data,
@@ -280,33 +411,85 @@ func createHTTPServer(
 	)
 }
 
-func (app *App) startServers() {
+// startServers binds and starts the web server, then reports what is being
+// captured. It returns the bind error; capture keeps running either way.
+func (app *App) startServers() error {
 	app.logger.PrintSessionInfo()
 	logger.PrintInfo("APP", "Starting servers...")
 
-	app.wg.Go(func() {
-		atomic.StoreInt32(&app.httpRunning, 1)
-		if err := app.httpServer.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) &&
-			app.ctx.Err() == nil {
-			logger.PrintError("HTTP", "Error: %v", err)
-		}
-		atomic.StoreInt32(&app.httpRunning, 0)
-	})
-
-	time.Sleep(100 * time.Millisecond)
-
-	logger.PrintSuccess("HTTP", "Server: http://localhost:%d", serverPort)
-	for _, ip := range capture.LANAddresses() {
-		logger.PrintSuccess("HTTP", "Server: http://%s:%d  (LAN)", ip, serverPort)
+	// Bind before advertising URLs: a taken port is reported once, clearly,
+	// and capture keeps running so the TUI still shows packet stats.
+	err := app.httpServer.Listen()
+	if err != nil {
+		logger.PrintError("HTTP", "Web server not started: %v", err)
+	} else {
+		app.wg.Go(func() {
+			atomic.StoreInt32(&app.httpRunning, 1)
+			if err := app.httpServer.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) &&
+				app.ctx.Err() == nil {
+				logger.PrintError("HTTP", "Error: %v", err)
+				select {
+				case app.serverErr <- err:
+				default:
+				}
+			}
+			atomic.StoreInt32(&app.httpRunning, 0)
+		})
+		app.printServerURLs()
 	}
-	logger.PrintSuccess("WS", "WebSocket: ws://localhost:%d/ws", serverPort)
-	for _, ip := range capture.LANAddresses() {
-		logger.PrintSuccess("WS", "WebSocket: ws://%s:%d/ws  (LAN)", ip, serverPort)
+	filter := app.captureManager.Filter()
+	logger.PrintInfo("PKT", "Listening for Albion packets on UDP port %s...", joinPorts(filter.PortsOrDefault()))
+	if filter.ExtraBPF != "" {
+		logger.PrintInfo("PKT", "Also capturing %q for recordings", filter.ExtraBPF)
 	}
-	logger.PrintInfo("PKT", "Listening for Albion packets on UDP port 5056...")
 	for _, s := range app.captureManager.State().Active {
 		logger.PrintInfo("NET", "Capturing on %s [%s]", s.Description, s.Address)
 	}
+	return err
+}
+
+func joinPorts(ports []uint16) string {
+	parts := make([]string, len(ports))
+	for i, p := range ports {
+		parts[i] = strconv.Itoa(int(p))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (app *App) printServerURLs() {
+	listen := app.httpServer.Addr()
+	lan := app.httpServer.LANAddresses()
+	scheme, wsScheme := app.httpServer.Scheme(), app.httpServer.WSScheme()
+	logger.PrintSuccess("HTTP", "Server: %s", listen.LocalURL(scheme))
+	for _, ip := range lan {
+		logger.PrintSuccess("HTTP", "Server: %s  (LAN)", server.HostURL(scheme, ip, listen.Port))
+	}
+	logger.PrintSuccess("WS", "WebSocket: %s/ws", listen.LocalURL(wsScheme))
+	for _, ip := range lan {
+		logger.PrintSuccess("WS", "WebSocket: %s/ws  (LAN)", server.HostURL(wsScheme, ip, listen.Port))
+	}
+	if fp := app.httpServer.TLSFingerprint(); fp != "" {
+		logger.PrintInfo("TLS", "Certificate SHA-256: %s", fp)
+	}
+	if listen.IsLoopback() {
+		logger.PrintInfo("HTTP", "Loopback-only mode: LAN devices cannot reach the radar")
+	}
+}
+
+// enableTLS loads or issues the self-signed certificate for every address the
+// server answers on, so LAN browsers see a matching SAN.
+func (app *App) enableTLS(listen server.ListenAddr) error {
+	hosts := app.httpServer.LANAddresses()
+	if h := listen.LocalHost(); h != "localhost" {
+		hosts = append(hosts, h)
+	}
+	cert, err := server.LoadOrCreateCert(tlsCertDir, hosts)
+	if err != nil {
+		return err
+	}
+	app.httpServer.UseTLS(cert)
+	logger.PrintInfo("TLS", "HTTPS enabled, certificate valid until %s", cert.NotAfter.Format("2006-01-02"))
+	return nil
 }
 
 func (app *App) updateStats() {
@@ -342,6 +525,11 @@ func (app *App) updateStats() {
 					LogBufferSize: logStats.BufferSize,
 				})
 
+				app.program.Send(ui.CodesMsg{Codes: codeStatsForUI(app.codeStats.Snapshot())})
+				if inst := app.instances.primary(time.Now()); inst != nil {
+					app.program.Send(entitiesForUI(inst.entities.Snapshot()))
+				}
+
 				captureActive := len(app.captureManager.State().Active) > 0
 				app.program.Send(ui.StatusMsg{
 					HTTPRunning:    atomic.LoadInt32(&app.httpRunning) == 1,
@@ -353,8 +541,45 @@ func (app *App) updateStats() {
 	}
 }
 
-func (app *App) handlePacket(payload []byte) {
-	if app.photonParser.ReceivePacket(payload) {
+func codeStatsForUI(stats []codestats.Stat) []ui.CodeStat {
+	out := make([]ui.CodeStat, len(stats))
+	for i, st := range stats {
+		out[i] = ui.CodeStat{
+			Kind:     string(st.Kind),
+			Code:     st.Code,
+			Name:     st.Name,
+			Count:    st.Count,
+			Rate:     st.Rate,
+			LastSeen: st.LastSeen,
+		}
+	}
+	return out
+}
+
+func entitiesForUI(s entities.Snapshot) ui.EntitiesMsg {
+	out := ui.EntitiesMsg{Cluster: s.Cluster, X: s.X, Y: s.Y, Entities: make([]ui.Entity, len(s.Entities))}
+	for i, e := range s.Entities {
+		out.Entities[i] = ui.Entity{
+			ID:       e.ID,
+			Kind:     string(e.Kind),
+			Name:     e.Name,
+			TypeID:   e.TypeID,
+			Tier:     e.Tier,
+			Enchant:  e.Enchant,
+			Size:     e.Size,
+			Guild:    e.Guild,
+			Alliance: e.Alliance,
+			X:        e.X,
+			Y:        e.Y,
+			HasPos:   e.HasPos,
+			LastSeen: e.LastSeen,
+		}
+	}
+	return out
+}
+
+func (app *App) handlePacket(payload []byte, meta photon.PacketMeta) {
+	if app.instances.route(meta).receive(payload, meta) {
 		atomic.AddUint64(&app.packetsProcessed, 1)
 	}
 }
@@ -365,11 +590,18 @@ func (app *App) onPhotonParseError(reason string, payloadLen int) {
 		logger.PrintWarn("PKT", "Parsing errors: %d (last reason: %s, payload len: %d)",
 			n, reason, payloadLen)
 	}
+	if app.parseErrorBurst.hit(time.Now()) {
+		app.wg.Go(func() { _, _ = app.saveRingBuffer("parse error burst: " + reason) })
+	}
 }
 
-func (app *App) onPhotonEvent(event *photon.EventData) {
+func (app *App) onPhotonEvent(inst *clientInstance, event *photon.EventData) {
 	photon.PostProcessEvent(event)
 	realCode := event.Parameters[252]
+	code := photon.AlbionCode(event.Parameters, 252)
+	app.codeStats.Add(codestats.Event, code)
+	app.inspector.add(codestats.Event, code, event.Parameters, 0, "")
+	inst.entities.HandleEvent(event.Parameters)
 	app.logger.Debug("EVENT_CAPTURE", fmt.Sprintf("Event_%v", realCode), map[string]interface{}{
 		"code":       realCode,
 		"paramCount": len(event.Parameters),
@@ -377,13 +609,23 @@ func (app *App) onPhotonEvent(event *photon.EventData) {
 	app.wsHandler.BroadcastEvent(event)
 }
 
-func (app *App) onPhotonRequest(req *photon.OperationRequest) {
+func (app *App) onPhotonRequest(inst *clientInstance, req *photon.OperationRequest) {
 	photon.PostProcessRequest(req)
+	code := photon.AlbionCode(req.Parameters, 253)
+	app.codeStats.Add(codestats.Request, code)
+	app.inspector.add(codestats.Request, code, req.Parameters, 0, "")
+	inst.entities.HandleRequest(req.Parameters)
 	app.wsHandler.BroadcastRequest(req)
 }
 
-func (app *App) onPhotonResponse(resp *photon.OperationResponse) {
+func (app *App) onPhotonResponse(inst *clientInstance, resp *photon.OperationResponse) {
 	photon.PostProcessResponse(resp)
+	code := photon.AlbionCode(resp.Parameters, 253)
+	app.codeStats.Add(codestats.Response, code)
+	app.inspector.add(codestats.Response, code, resp.Parameters, resp.ReturnCode, resp.DebugMessage)
+	cluster := inst.entities.Cluster()
+	inst.entities.HandleResponse(resp.Parameters)
+	app.annotateCluster(inst, cluster)
 	app.wsHandler.BroadcastResponse(resp)
 }
 
@@ -402,6 +644,7 @@ func (app *App) shutdown() {
 
 	app.cancel()
 	app.captureManager.Close(ctx)
+	logger.AttachSession(nil)
 	app.logger.Stop()
 
 	if err := app.httpServer.Shutdown(ctx); err != nil {
@@ -428,7 +671,7 @@ func (app *App) shutdown() {
 func resolvePersisted(cfg capture.Config, all []capture.NetworkInterface, ipOverride string) []capture.NetworkInterface {
 	if ipOverride != "" {
 		for _, i := range all {
-			if i.Address == ipOverride {
+			if i.HasAddress(ipOverride) {
 				return []capture.NetworkInterface{i}
 			}
 		}
@@ -451,13 +694,30 @@ func autoPickDefaults(all []capture.NetworkInterface) []capture.NetworkInterface
 	out := make([]capture.NetworkInterface, 0)
 	for _, i := range capture.RankCandidates(all) {
 		c := capture.Categorize(i.Name, i.Description)
-		if (c == capture.CategoryEthernet || c == capture.CategoryWiFi || c == capture.CategoryExitLag) && capture.IsRFC1918(i.Address) {
+		if (c == capture.CategoryEthernet || c == capture.CategoryWiFi || c == capture.CategoryExitLag) && slices.ContainsFunc(i.AllAddresses(), capture.IsPrivate) {
 			out = append(out, i)
 		}
 	}
 	return out
 }
 
+// startInterfaceWatch re-enumerates the interfaces in the background and
+// brings the saved ones back when they reappear; see NetworkAPI.Watch. Each
+// change goes to the stream clients and the TUI.
+func (app *App) startInterfaceWatch(reopen bool) {
+	api := app.httpServer.NetworkAPI()
+	if api == nil {
+		return
+	}
+	api.OnInterfacesChange(func(c server.InterfaceChange) {
+		app.wsHandler.BroadcastInterfaces(c)
+		if app.program != nil {
+			app.program.Send(app.captureStateMsg())
+		}
+	})
+	app.wg.Go(func() { api.Watch(app.ctx, server.InterfaceWatchInterval, reopen) })
+}
+
 // startCaptureStatePoll pushes a CaptureStateMsg to the TUI every 2s so
 // header and Config tab reflect live Manager state without coupling ui to capture.
 func (app *App) startCaptureStatePoll() {
@@ -472,21 +732,72 @@ func (app *App) startCaptureStatePoll() {
 				if app.program == nil {
 					continue
 				}
-				s := app.captureManager.State()
-				summaries := make([]ui.CaptureSummary, 0, len(s.Active))
-				for _, a := range s.Active {
-					summaries = append(summaries, ui.CaptureSummary{
-						Description: a.Description,
-						Address:     a.Address,
-						Category:    string(a.Category),
-					})
-				}
-				app.program.Send(ui.CaptureStateMsg{
-					Active:       summaries,
-					LanAddresses: capture.LANAddresses(),
-					Status:       string(s.Status),
-				})
+				app.program.Send(app.captureStateMsg())
 			}
 		}
 	})
 }
+
+func (app *App) captureStateMsg() ui.CaptureStateMsg {
+	s := app.captureManager.State()
+	summaries := make([]ui.CaptureSummary, 0, len(s.Active))
+	active := make(map[string]bool, len(s.Active))
+	endpoints := make(map[string][]ui.GameEndpoint, len(s.Active))
+	for _, a := range s.Active {
+		summaries = append(summaries, ui.CaptureSummary{
+			Description: a.Description,
+			Address:     a.Address,
+			Category:    string(a.Category),
+		})
+		active[a.Name] = true
+		for _, e := range a.Endpoints {
+			endpoints[a.Name] = append(endpoints[a.Name], ui.GameEndpoint{Address: e.Address, Region: e.Region})
+		}
+	}
+	msg := ui.CaptureStateMsg{
+		Active:       summaries,
+		LanAddresses: app.httpServer.LANAddresses(),
+		Status:       string(s.Status),
+		Recording:    app.captureManager.IsRecording(),
+		Instances:    instancesForUI(app.clientInstances()),
+	}
+	if c, ok := app.conns.Current(); ok {
+		msg.GameServer = &ui.GameServer{Address: c.Server.String(), Ping: c.RTT, LastPacket: c.LastPacket}
+	}
+	if api := app.httpServer.NetworkAPI(); api != nil {
+		for _, row := range api.Interfaces() {
+			msg.Interfaces = append(msg.Interfaces, ui.InterfaceOption{
+				Name:        row.Name,
+				Description: row.Description,
+				Address:     row.Address,
+				Category:    row.Category,
+				Active:      active[row.Name],
+				Error:       s.LastErrors[row.Name],
+				Endpoints:   endpoints[row.Name],
+			})
+		}
+	}
+	return msg
+}
+
+// dashboardControls wires the TUI interface picker, recording and buffer keys
+// to the same code paths as Settings -> Network and /api/settings/logging.
+// Each action pushes a fresh state so the dashboard does not wait for the poll.
+func (app *App) dashboardControls() ui.Controls {
+	var c ui.Controls
+	if api := app.httpServer.NetworkAPI(); api != nil {
+		c.SelectInterfaces = func(names []string) error {
+			err := api.Select(names)
+			app.program.Send(app.captureStateMsg())
+			return err
+		}
+	}
+	settings := app.httpServer.SettingsAPI()
+	c.SetRecording = func(on bool) error {
+		err := settings.SetRecording(on)
+		app.program.Send(app.captureStateMsg())
+		return err
+	}
+	c.SaveBuffer = app.saveRingBufferControl
+	return c
+}
@@ -2,11 +2,18 @@ package main
 
 import (
 	"errors"
+	"net/netip"
+	"slices"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/nospy/albion-openradar/internal/capture"
+	"github.com/nospy/albion-openradar/internal/photon"
+	"github.com/nospy/albion-openradar/internal/photon/codestats"
+	"github.com/nospy/albion-openradar/internal/photon/entities"
+	"github.com/nospy/albion-openradar/internal/server"
 	"github.com/nospy/albion-openradar/internal/ui"
 )
 
@@ -73,6 +80,18 @@ func TestAutoPickDefaultsExcludesNonRFC1918(t *testing.T) {
 	}
 }
 
+func TestAutoPickDefaultsAcceptsPrivateIPv6(t *testing.T) {
+	all := []capture.NetworkInterface{
+		{Name: "eth6", Description: "Intel Ethernet", Address: "fd00::10", Addresses: []string{"fd00::10", "fe80::10"}},
+		{Name: "dual", Description: "Wi-Fi", Address: "8.8.4.4", Addresses: []string{"8.8.4.4", "fd00::11"}},
+		{Name: "global6", Description: "Realtek Ethernet", Address: "2001:db8::1", Addresses: []string{"2001:db8::1"}},
+	}
+	got := autoPickDefaults(all)
+	if len(got) != 2 || got[0].Name != "eth6" || got[1].Name != "dual" {
+		t.Errorf("got %+v, want eth6 and dual (a unique local address counts as private)", got)
+	}
+}
+
 func TestAutoPickDefaultsExcludesVirtualAndVPN(t *testing.T) {
 	all := []capture.NetworkInterface{
 		{Name: "tun0", Description: "WireGuard", Address: "10.8.0.5"},
@@ -115,3 +134,129 @@ func TestRunInterfaceDoesNotWaitWhenTheDashboardExitsNormally(t *testing.T) {
 		t.Error("a dashboard that did not ask for a restart must report false")
 	}
 }
+
+func TestResolveListenPrecedence(t *testing.T) {
+	got, err := resolveListen(Config{}, capture.ServerConfig{})
+	if err != nil || got != (server.ListenAddr{Port: server.DefaultPort}) {
+		t.Errorf("defaults: got %+v, %v", got, err)
+	}
+
+	persisted := capture.ServerConfig{ListenAddress: "192.168.1.5", Port: 6000}
+	got, err = resolveListen(Config{}, persisted)
+	if err != nil || got != (server.ListenAddr{Host: "192.168.1.5", Port: 6000}) {
+		t.Errorf("network.json: got %+v, %v", got, err)
+	}
+
+	got, err = resolveListen(Config{listenAddr: "0.0.0.0", port: 7000}, persisted)
+	if err != nil || got != (server.ListenAddr{Host: "0.0.0.0", Port: 7000}) {
+		t.Errorf("flags must override network.json: got %+v, %v", got, err)
+	}
+
+	got, err = resolveListen(Config{loopbackOnly: true}, persisted)
+	if err != nil || got != (server.ListenAddr{Host: "127.0.0.1", Port: 6000}) {
+		t.Errorf("-loopback: got %+v, %v", got, err)
+	}
+}
+
+func TestResolveListenRejectsInvalid(t *testing.T) {
+	if _, err := resolveListen(Config{port: 70000}, capture.ServerConfig{}); err == nil {
+		t.Error("port out of range must be rejected")
+	}
+	if _, err := resolveListen(Config{loopbackOnly: true, listenAddr: "0.0.0.0"}, capture.ServerConfig{}); err == nil {
+		t.Error("-loopback with a non-loopback -listen must be rejected")
+	}
+}
+
+func TestInspectorFeedBoundsQueue(t *testing.T) {
+	var f inspectorFeed
+	f.add(codestats.Event, 3, nil, 0, "")
+	if msg := f.drain(); len(msg.Messages) != 0 {
+		t.Fatalf("disabled feed queued %d messages", len(msg.Messages))
+	}
+
+	f.enabled.Store(true)
+	for i := range inspectorQueueSize + 5 {
+		f.add(codestats.Event, i, nil, 0, "")
+	}
+	msg := f.drain()
+	if len(msg.Messages) != inspectorQueueSize || msg.Dropped != 5 {
+		t.Fatalf("got %d messages, %d dropped; want %d and 5", len(msg.Messages), msg.Dropped, inspectorQueueSize)
+	}
+	if msg.Messages[0].Code != 5 || msg.Messages[0].Kind != "event" {
+		t.Errorf("oldest kept message = %+v, want code 5", msg.Messages[0])
+	}
+	if again := f.drain(); len(again.Messages) != 0 || again.Dropped != 0 {
+		t.Errorf("drain did not reset the queue: %+v", again)
+	}
+}
+
+func TestBurstTriggerFiresOncePerCooldown(t *testing.T) {
+	b := burstTrigger{window: 10 * time.Second, cooldown: time.Minute}
+	b.setThreshold(3)
+	now := time.Now()
+	fired := 0
+	for i := range 6 {
+		if b.hit(now.Add(time.Duration(i) * time.Second)) {
+			fired++
+		}
+	}
+	if fired != 1 {
+		t.Errorf("a burst inside the cooldown fired %d times, want 1", fired)
+	}
+	if b.hit(now.Add(20*time.Second)) || b.hit(now.Add(40*time.Second)) {
+		t.Error("hits spread over several windows must not fire")
+	}
+	later := now.Add(2 * time.Minute)
+	if b.hit(later) || b.hit(later) || !b.hit(later) {
+		t.Error("a burst after the cooldown should fire again")
+	}
+
+	b.setThreshold(0)
+	for range 10 {
+		if b.hit(later.Add(5 * time.Minute)) {
+			t.Fatal("a zero threshold disables the trigger")
+		}
+	}
+}
+
+func TestInstanceSetSplitsByLocalPort(t *testing.T) {
+	set := newInstanceSet(func(uint16) *clientInstance {
+		return &clientInstance{entities: entities.New()}
+	})
+	game := netip.MustParseAddrPort("5.188.125.10:5056")
+	first := netip.MustParseAddrPort("192.168.1.2:50000")
+	second := netip.MustParseAddrPort("192.168.1.2:50001")
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	at := func(s int) time.Time { return start.Add(time.Duration(s) * time.Second) }
+
+	if set.primary(start) != nil {
+		t.Fatal("primary before any packet")
+	}
+	a := set.route(photon.FlowMeta(at(0), first, game, photon.ServerPort))
+	b := set.route(photon.FlowMeta(at(1), game, second, photon.ServerPort))
+	if a == b || a.port != 50000 || b.port != 50001 {
+		t.Fatalf("instances %d and %d, want 50000 and 50001 apart", a.port, b.port)
+	}
+	if again := set.route(photon.FlowMeta(at(2), game, first, photon.ServerPort)); again != a || a.packets != 2 {
+		t.Fatalf("the reply to :50000 went to :%d (%d packets)", again.port, a.packets)
+	}
+	if got := set.primary(at(2)); got != a {
+		t.Errorf("primary is :%d, want the first client", got.port)
+	}
+
+	// The first client goes quiet: the second one takes over the TUI.
+	set.route(photon.FlowMeta(at(120), second, game, photon.ServerPort))
+	if got := set.primary(at(120)); got != b {
+		t.Errorf("primary is :%d, want the client still playing", got.port)
+	}
+
+	// After the idle expiry, a new client prunes the silent one.
+	set.route(photon.FlowMeta(at(700), netip.MustParseAddrPort("192.168.1.2:50002"), game, photon.ServerPort))
+	var ports []uint16
+	for _, in := range set.list() {
+		ports = append(ports, in.port)
+	}
+	if !slices.Equal(ports, []uint16{50001, 50002}) {
+		t.Errorf("instances %v, want [50001 50002]", ports)
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"runtime"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/nospy/albion-openradar/internal/photon/codestats"
+	"github.com/nospy/albion-openradar/internal/server"
+)
+
+// writeMetrics is the /metrics collector: the counters the TUI plots, plus
+// per-interface capture stats and per-event-code counts.
+func (app *App) writeMetrics(m *server.MetricsWriter) {
+	m.Gauge("openradar_build_info", "OpenRadar version, always 1.",
+		server.Sample{Labels: []server.Label{{Name: "version", Value: Version}}, Value: 1})
+
+	m.Counter("openradar_packets_processed_total", "Photon packets parsed successfully.",
+		server.Value(atomic.LoadUint64(&app.packetsProcessed)))
+	m.Counter("openradar_packet_errors_total", "Photon packets that failed to parse.",
+		server.Value(atomic.LoadUint64(&app.packetsErrors)))
+	m.Counter("openradar_packets_encrypted_total", "Encrypted Photon packets ignored.",
+		server.Value(atomic.LoadUint64(&app.packetsEncrypted)))
+
+	var events []server.Sample
+	for _, st := range app.codeStats.Snapshot() {
+		if st.Kind != codestats.Event {
+			continue
+		}
+		events = append(events, server.Sample{
+			Labels: []server.Label{{Name: "code", Value: strconv.Itoa(st.Code)}, {Name: "name", Value: st.Name}},
+			Value:  float64(st.Count),
+		})
+	}
+	m.Counter("openradar_events_total", "Decoded events by Albion event code.", events...)
+
+	m.Counter("openradar_capture_bytes_received_total", "UDP payload bytes received across active interfaces.",
+		server.Value(app.captureManager.BytesReceived()))
+	ifaces := app.captureManager.InterfaceStats()
+	m.Gauge("openradar_capture_interfaces", "Interfaces currently capturing.", server.Value(len(ifaces)))
+	perIface := func(get func(i int) uint64) []server.Sample {
+		out := make([]server.Sample, len(ifaces))
+		for i, st := range ifaces {
+			out[i] = server.Sample{
+				Labels: []server.Label{{Name: "interface", Value: st.Name}, {Name: "description", Value: st.Description}},
+				Value:  float64(get(i)),
+			}
+		}
+		return out
+	}
+	m.Counter("openradar_interface_bytes_received_total", "UDP payload bytes received on the interface.",
+		perIface(func(i int) uint64 { return ifaces[i].BytesReceived })...)
+	m.Counter("openradar_interface_packets_received_total", "UDP packets handed to the parser from the interface.",
+		perIface(func(i int) uint64 { return ifaces[i].PacketsReceived })...)
+	m.Counter("openradar_interface_pcap_received_total", "Packets received by the capture filter (pcap_stats).",
+		perIface(func(i int) uint64 { return ifaces[i].KernelReceived })...)
+	m.Counter("openradar_interface_pcap_dropped_total", "Packets dropped because the capture buffer was full (pcap_stats).",
+		perIface(func(i int) uint64 { return ifaces[i].KernelDropped })...)
+	m.Counter("openradar_interface_pcap_if_dropped_total", "Packets dropped by the network interface (pcap_stats).",
+		perIface(func(i int) uint64 { return ifaces[i].InterfaceDropped })...)
+	m.Counter("openradar_interface_record_write_errors_total", "Packets the pcap recorder failed to write.",
+		perIface(func(i int) uint64 { return ifaces[i].RecordWriteErrors })...)
+	m.Counter("openradar_interface_ip_reassembled_total", "Fragmented IPv4 datagrams reassembled on the interface.",
+		perIface(func(i int) uint64 { return ifaces[i].FragmentsReassembled })...)
+	m.Counter("openradar_interface_ip_fragments_expired_total", "Fragmented IPv4 datagrams dropped incomplete on the interface.",
+		perIface(func(i int) uint64 { return ifaces[i].FragmentsExpired })...)
+
+	ws := app.wsHandler.Stats()
+	m.Gauge("openradar_ws_clients", "Connected WebSocket and event-stream clients.", server.Value(app.wsHandler.ClientCount()))
+	m.Counter("openradar_ws_batches_sent_total", "Message batches flushed to clients.", server.Value(ws.BatchesSent))
+	m.Counter("openradar_ws_messages_sent_total", "Messages flushed to clients.", server.Value(ws.MessagesSent))
+	m.Counter("openradar_ws_bytes_sent_total", "Bytes written to clients.", server.Value(ws.BytesSent))
+	m.Gauge("openradar_ws_queue_messages", "Messages waiting for the next batch.", server.Value(ws.MessagesQueue))
+
+	logs := app.logger.GetStats()
+	m.Counter("openradar_log_entries_total", "Log entries written.", server.Value(logs.TotalEntries))
+	m.Counter("openradar_log_batches_total", "Log batches flushed to disk.", server.Value(logs.TotalBatches))
+	m.Counter("openradar_log_client_entries_total", "Log entries sent by browsers.", server.Value(logs.ClientEntries))
+	m.Counter("openradar_log_server_entries_total", "Log entries from the server.", server.Value(logs.ServerEntries))
+	m.Gauge("openradar_log_buffer_entries", "Log entries waiting to be flushed.", server.Value(logs.BufferSize))
+	clientLogs := app.wsHandler.ClientLogStats()
+	rejected := func(reason string, n uint64) server.Sample {
+		return server.Sample{Labels: []server.Label{{Name: "reason", Value: reason}}, Value: float64(n)}
+	}
+	m.Counter("openradar_log_client_rejected_total", "Browser log entries rejected before reaching the disk.",
+		rejected("invalid", clientLogs.Invalid),
+		rejected("too_large", clientLogs.TooLarge),
+		rejected("rate_limited", clientLogs.RateLimited),
+		rejected("over_batch", clientLogs.OverBatch),
+	)
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	m.Gauge("go_goroutines", "Number of goroutines that currently exist.", server.Value(runtime.NumGoroutine()))
+	m.Gauge("go_memstats_alloc_bytes", "Number of bytes allocated and still in use.", server.Value(mem.Alloc))
+	m.Gauge("go_memstats_sys_bytes", "Number of bytes obtained from system.", server.Value(mem.Sys))
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/nospy/albion-openradar/internal/capture"
+	"github.com/nospy/albion-openradar/internal/logger"
+)
+
+// applyRecordingFormat switches the recording format, restarting a running
+// recording in it.
+func (app *App) applyRecordingFormat(f capture.RecordingFormat) {
+	if err := app.captureManager.SetRecordingFormat(f); err != nil {
+		logger.PrintWarn("PKT", "pcap recording could not restart as %s: %v", f, err)
+		return
+	}
+	logger.PrintInfo("PKT", "Recording format set to %s", f)
+}
+
+// annotateCluster marks a cluster change in a pcapng recording, so a capture
+// can be cut at zone boundaries. The note names the client's local port,
+// which tells multibox clients apart.
+func (app *App) annotateCluster(inst *clientInstance, before string) {
+	if after := inst.entities.Cluster(); after != "" && after != before {
+		note := "cluster " + after
+		if inst.port != 0 {
+			note += fmt.Sprintf(" (client :%d)", inst.port)
+		}
+		app.captureManager.Annotate(note)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/nospy/albion-openradar/internal/capture"
+	"github.com/nospy/albion-openradar/internal/logger"
+)
+
+// retentionSweepInterval is how often closed logs and recordings are
+// compressed and pruned.
+const retentionSweepInterval = 10 * time.Minute
+
+// sweepMu keeps a sweep triggered from the settings page from racing the
+// periodic one over the same files.
+var sweepMu sync.Mutex
+
+// startRetention applies the saved retention, then sweeps again every
+// retentionSweepInterval with whatever network.json holds by then.
+func (app *App) startRetention(appDir string, initial capture.RetentionConfig) {
+	app.applyRetention(initial)
+	app.wg.Go(func() {
+		t := time.NewTicker(retentionSweepInterval)
+		defer t.Stop()
+		for {
+			select {
+			case <-app.ctx.Done():
+				return
+			case <-t.C:
+				cfg, err := capture.ReadConfig(appDir)
+				if err != nil {
+					logger.PrintWarn("LOG", "Retention skipped: %v", err)
+					continue
+				}
+				app.sweep(cfg.Logging.RetentionOrDefault())
+			}
+		}
+	})
+}
+
+// applyRetention sets the rotation sizes at once and sweeps in the
+// background, as compressing a large recording takes a while.
+func (app *App) applyRetention(r capture.RetentionConfig) {
+	app.logger.SetMaxFileSize(r.Logs.Policy().MaxFileBytes)
+	app.captureManager.SetRecordingMaxBytes(r.Captures.Policy().MaxFileBytes)
+	app.wg.Go(func() { app.sweep(r) })
+}
+
+func (app *App) sweep(r capture.RetentionConfig) {
+	sweepMu.Lock()
+	defer sweepMu.Unlock()
+
+	res, err := app.logger.Sweep(r.Logs.Policy())
+	reportSweep("logs", res, err)
+	res, err = logger.Sweep(r.Captures.Policy(), app.captureManager.RecordingFiles(), pcapCaptureDir)
+	reportSweep("captures", res, err)
+}
+
+func reportSweep(what string, res logger.SweepResult, err error) {
+	if err != nil {
+		logger.PrintWarn("LOG", "Retention on %s: %v", what, err)
+	}
+	if res.Compressed > 0 || res.Deleted > 0 {
+		logger.PrintInfo("LOG", "Retention on %s: %d compressed, %d deleted (%.1f MB freed)",
+			what, res.Compressed, res.Deleted, float64(res.FreedBytes)/(1<<20))
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nospy/albion-openradar/internal/capture"
+	"github.com/nospy/albion-openradar/internal/logger"
+)
+
+// ringFlushCooldown keeps a lasting parse error storm from saving the buffer
+// over and over.
+const ringFlushCooldown = 5 * time.Minute
+
+// burstTrigger fires when threshold hits land within window, then stays quiet
+// for cooldown.
+type burstTrigger struct {
+	mu        sync.Mutex
+	threshold int
+	window    time.Duration
+	cooldown  time.Duration
+	start     time.Time
+	count     int
+	fired     time.Time
+}
+
+func (b *burstTrigger) setThreshold(n int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.threshold, b.count = n, 0
+}
+
+// hit records one event at now and reports whether the burst fires.
+func (b *burstTrigger) hit(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.threshold <= 0 {
+		return false
+	}
+	if now.Sub(b.start) > b.window {
+		b.start, b.count = now, 0
+	}
+	b.count++
+	if b.count < b.threshold || (!b.fired.IsZero() && now.Sub(b.fired) < b.cooldown) {
+		return false
+	}
+	b.fired, b.count = now, 0
+	return true
+}
+
+// applyRingBuffer resizes the capturers' packet buffers and arms the parse
+// error trigger.
+func (app *App) applyRingBuffer(r capture.RingBufferConfig) {
+	app.captureManager.SetRingBuffer(r.MaxAge(), r.MaxBytes())
+	burst := r.ParseErrorBurst
+	if r.Seconds == 0 && r.MaxMB == 0 {
+		burst = 0
+	}
+	app.parseErrorBurst.setThreshold(burst)
+}
+
+// saveRingBuffer writes the packet buffers to the capture directory and logs
+// why.
+func (app *App) saveRingBuffer(reason string) ([]string, error) {
+	paths, err := app.captureManager.FlushRing(pcapCaptureDir)
+	switch {
+	case errors.Is(err, capture.ErrRingEmpty), errors.Is(err, capture.ErrRingDisabled):
+		return nil, err
+	case err != nil && len(paths) == 0:
+		logger.PrintWarn("PCAP", "Packet buffer not saved (%s): %v", reason, err)
+		return nil, err
+	case err != nil:
+		logger.PrintWarn("PCAP", "Packet buffer partly saved (%s): %v", reason, err)
+	}
+	logger.PrintSuccess("PCAP", "Saved the packet buffer (%s) to %v", reason, paths)
+	return paths, err
+}
+
+// saveRingBufferControl is the TUI key's action.
+func (app *App) saveRingBufferControl() (string, error) {
+	paths, err := app.saveRingBuffer("key")
+	if len(paths) == 0 {
+		return "", err
+	}
+	return fmt.Sprintf("Packet buffer saved to %d file(s)", len(paths)), err
+}
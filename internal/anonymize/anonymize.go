@@ -0,0 +1,222 @@
+// Package anonymize rewrites a pcap so it can be shared: MACs and IPs are
+// replaced, timestamps start at the epoch, and strings identifying a player
+// or a machine are overwritten in the UDP payloads.
+package anonymize
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/google/gopacket/pcapgo"
+
+	"github.com/nospy/albion-openradar/internal/pcapiter"
+)
+
+// Options selects what is scrubbed from the payloads.
+type Options struct {
+	// Scrubs are extra ASCII strings replaced wherever they appear.
+	Scrubs []string
+	// Identity are values replaced where they appear as Photon strings,
+	// usually what IdentityValues found in the same capture.
+	Identity []string
+}
+
+// Result counts what File did.
+type Result struct {
+	Read    int
+	Written int
+	// Truncated is set when the input ended mid-record; what came before
+	// was still anonymized.
+	Truncated bool
+	// Counts holds the replacements per scrubbed value, zero for a value that
+	// was never found.
+	Counts map[string]int
+}
+
+var (
+	fakeClientMAC = net.HardwareAddr{0x02, 0x00, 0x00, 0x00, 0x00, 0x01}
+	fakeServerMAC = net.HardwareAddr{0x02, 0x00, 0x00, 0x00, 0x00, 0x02}
+	fakeClientIP  = net.IPv4(10, 0, 0, 1)
+	fakeServerIP  = net.IPv4(10, 0, 0, 2)
+)
+
+// outSnaplen is the snapshot length of the copy, enough for any UDP packet.
+const outSnaplen = 65536
+
+// File writes an anonymized copy of in to out. Packets that are not UDP over
+// IPv4 on Ethernet are dropped. A gzipped or pcapng input is read as it is;
+// the copy is always a classic pcap.
+func File(in, out string, opts Options) (Result, error) {
+	res := Result{Counts: make(map[string]int, len(opts.Scrubs)+len(opts.Identity))}
+
+	macMap := map[string]net.HardwareAddr{}
+	ipMap := map[string]net.IP{}
+	var nextMAC byte = 1
+	var nextIP byte = 1
+
+	pickMAC := func(real net.HardwareAddr) net.HardwareAddr {
+		key := real.String()
+		if fake, ok := macMap[key]; ok {
+			return fake
+		}
+		nextMAC++
+		fake := net.HardwareAddr{0x02, 0x00, 0x00, 0x00, 0x00, nextMAC}
+		macMap[key] = fake
+		return fake
+	}
+	pickIP := func(real net.IP) net.IP {
+		key := real.String()
+		if fake, ok := ipMap[key]; ok {
+			return fake
+		}
+		nextIP++
+		fake := net.IPv4(10, 0, 0, nextIP)
+		ipMap[key] = fake
+		return fake
+	}
+
+	macMap["seed-client"] = fakeClientMAC
+	macMap["seed-server"] = fakeServerMAC
+	ipMap["seed-client"] = fakeClientIP
+	ipMap["seed-server"] = fakeServerIP
+
+	for _, n := range opts.Scrubs {
+		res.Counts[n] = 0
+	}
+	for _, n := range opts.Identity {
+		res.Counts[n] = 0
+	}
+
+	var baseTime time.Time
+
+	type decoded struct {
+		eth *layers.Ethernet
+		ip4 *layers.IPv4
+		udp *layers.UDP
+		ts  time.Time
+	}
+	var packets []decoded
+
+	st, err := pcapiter.File(in, func(p pcapiter.Packet) error {
+		eth, _ := p.Frame.Layer(layers.LayerTypeEthernet).(*layers.Ethernet)
+		ip4, _ := p.Frame.Layer(layers.LayerTypeIPv4).(*layers.IPv4)
+		udp, _ := p.Frame.Layer(layers.LayerTypeUDP).(*layers.UDP)
+		if eth == nil || ip4 == nil || udp == nil {
+			return nil
+		}
+
+		eth.SrcMAC = pickMAC(eth.SrcMAC)
+		eth.DstMAC = pickMAC(eth.DstMAC)
+		ip4.SrcIP = pickIP(ip4.SrcIP)
+		ip4.DstIP = pickIP(ip4.DstIP)
+
+		if err := udp.SetNetworkLayerForChecksum(ip4); err != nil {
+			return fmt.Errorf("checksum wiring: %w", err)
+		}
+
+		if len(opts.Identity) > 0 {
+			udp.Payload = scrubPrefixedValues(udp.Payload, opts.Identity, res.Counts)
+		}
+		if len(opts.Scrubs) > 0 {
+			udp.Payload = scrubPayload(udp.Payload, opts.Scrubs, res.Counts)
+		}
+
+		packets = append(packets, decoded{eth: eth, ip4: ip4, udp: udp, ts: p.Timestamp})
+		return nil
+	})
+	res.Read, res.Truncated = st.Packets, st.Truncated
+	if err != nil {
+		return res, err
+	}
+
+	if len(opts.Identity) > 0 {
+		payloads := make([][]byte, len(packets))
+		for i, p := range packets {
+			payloads[i] = p.udp.Payload
+		}
+		scrubSplitValues(payloads, opts.Identity, res.Counts)
+	}
+
+	dst, err := os.Create(out)
+	if err != nil {
+		return res, err
+	}
+	defer dst.Close()
+
+	writer := pcapgo.NewWriter(dst)
+	if err := writer.WriteFileHeader(outSnaplen, layers.LinkTypeEthernet); err != nil {
+		return res, err
+	}
+
+	for _, p := range packets {
+		eth, ip4, udp := p.eth, p.ip4, p.udp
+
+		buf := gopacket.NewSerializeBuffer()
+		sopts := gopacket.SerializeOptions{FixLengths: true, ComputeChecksums: true}
+		// SerializeLayers picks up the mutated udp.Payload; SerializePacket would reuse the original parsed layer.
+		if err := gopacket.SerializeLayers(buf, sopts, eth, ip4, udp, gopacket.Payload(udp.Payload)); err != nil {
+			return res, fmt.Errorf("serialize: %w", err)
+		}
+		outBytes := buf.Bytes()
+
+		if baseTime.IsZero() {
+			baseTime = p.ts
+		}
+		newCI := gopacket.CaptureInfo{
+			Timestamp:     time.Unix(0, 0).Add(p.ts.Sub(baseTime)),
+			CaptureLength: len(outBytes),
+			Length:        len(outBytes),
+		}
+		if err := writer.WritePacket(newCI, outBytes); err != nil {
+			return res, err
+		}
+		res.Written++
+	}
+	return res, dst.Close()
+}
+
+// WriteCounts prints the replacement count of each value, sorted.
+func WriteCounts(w io.Writer, counts map[string]int) {
+	if len(counts) == 0 {
+		return
+	}
+	needles := make([]string, 0, len(counts))
+	for n := range counts {
+		needles = append(needles, n)
+	}
+	sort.Strings(needles)
+
+	for _, n := range needles {
+		fmt.Fprintf(w, "  %s: %d replacements\n", n, counts[n])
+	}
+}
+
+const scrubByte = 'X'
+
+// scrubPayload applies needles in order; overlapping matches resolve by first match wins. ASCII only.
+func scrubPayload(payload []byte, needles []string, counts map[string]int) []byte {
+	if len(needles) == 0 {
+		return payload
+	}
+	out := append([]byte(nil), payload...)
+	for _, n := range needles {
+		if n == "" {
+			continue
+		}
+		hits := bytes.Count(out, []byte(n))
+		if hits == 0 {
+			continue
+		}
+		counts[n] += hits
+		pad := bytes.Repeat([]byte{scrubByte}, len(n))
+		out = bytes.ReplaceAll(out, []byte(n), pad)
+	}
+	return out
+}
@@ -1,4 +1,4 @@
-package main
+package anonymize
 
 import (
 	"bytes"
@@ -46,9 +46,9 @@ var identityFields = []fieldRef{
 	{kindRequest, 300, 2}, // operating system
 }
 
-// collectIdentityValues decodes the whole capture and returns every distinct
+// IdentityValues decodes the whole capture and returns every distinct
 // string sitting in an identity field.
-func collectIdentityValues(path string) ([]string, error) {
+func IdentityValues(path string) ([]string, error) {
 	seen := map[string]struct{}{}
 	err := photonscan.Scan(path, func(m photonscan.Message) {
 		for _, field := range identityFields {
@@ -1,6 +1,7 @@
 package capture
 
 import (
+	"net/netip"
 	"regexp"
 	"sort"
 	"strings"
@@ -60,7 +61,18 @@ func RankCandidates(in []NetworkInterface) []NetworkInterface {
 		if ci != cj {
 			return categoryRank[ci] < categoryRank[cj]
 		}
+		ai, aj := primaryRank(out[i]), primaryRank(out[j])
+		if ai != aj {
+			return ai < aj
+		}
 		return out[i].Description != "" && out[j].Description == ""
 	})
 	return out
 }
+
+// primaryRank is the addrRank of the interface's primary address, so that
+// within a category an IPv4 interface comes before an IPv6-only one.
+func primaryRank(i NetworkInterface) int {
+	a, _ := netip.ParseAddr(i.Address)
+	return addrRank(a)
+}
@@ -107,3 +107,18 @@ func TestRankCandidatesStableWithinCategory(t *testing.T) {
 		}
 	}
 }
+
+func TestRankCandidatesIPv4BeforeIPv6WithinCategory(t *testing.T) {
+	in := []NetworkInterface{
+		{Name: "tun6", Description: "WireGuard Tunnel", Address: "fd00:10::2"},
+		{Name: "tunLL", Description: "WireGuard Tunnel", Address: "fe80::5"},
+		{Name: "tun4", Description: "WireGuard Tunnel", Address: "10.8.0.2"},
+	}
+	got := RankCandidates(in)
+	wantNames := []string{"tun4", "tun6", "tunLL"}
+	for i, want := range wantNames {
+		if got[i].Name != want {
+			t.Errorf("position %d: name %q, want %q", i, got[i].Name, want)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package capture
+
+import (
+	"net/netip"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/gopacket/layers"
+)
+
+const (
+	// defragMaxDatagrams bounds the datagrams waiting for fragments on one
+	// capturer. To make room the oldest is dropped and counted as expired.
+	defragMaxDatagrams = 64
+	// defragMaxFragments bounds the fragments of one datagram; past it the
+	// datagram is dropped.
+	defragMaxFragments = 64
+	// defragMaxBytes bounds the fragment bytes held on one capturer; past it
+	// datagrams are evicted as for defragMaxDatagrams.
+	defragMaxBytes = 1 << 20
+	// defragTimeout is how long a datagram waits for its missing fragments.
+	defragTimeout = 5 * time.Second
+	// maxIPv4Payload is the largest payload an IPv4 datagram can carry.
+	maxIPv4Payload = 65535 - 20
+)
+
+type fragKey struct {
+	src, dst netip.Addr
+	id       uint16
+	proto    layers.IPProtocol
+}
+
+type fragment struct {
+	offset int
+	data   []byte
+}
+
+type pendingDatagram struct {
+	first time.Time
+	frags []fragment
+	// headed is set once the first fragment, the one with the UDP header,
+	// arrived. Without it the datagram may be any UDP traffic let in by the
+	// filter's fragment clause.
+	headed bool
+	bytes  int
+	// total is the payload length, known once the last fragment (MF clear)
+	// arrived; zero before.
+	total int
+}
+
+// defragmenter reassembles the IPv4 datagrams the OS split at the IP layer,
+// as happens on VPN and ExitLag adapters with a small MTU: only the first
+// fragment carries the UDP header. It is fed from the capturer's read loop
+// only; the counters may be read from anywhere.
+//
+// The capture filter lets in every later fragment, whatever its port, so the
+// table also collects fragments of unrelated traffic whose first fragment
+// never comes. Those orphans are evicted first and dropped without being
+// counted as expired.
+type defragmenter struct {
+	pending map[fragKey]*pendingDatagram
+	bytes   int
+
+	reassembled uint64
+	expired     uint64
+}
+
+// isFragment reports whether ip is one piece of a larger datagram.
+func isFragment(ip *layers.IPv4) bool {
+	return ip.Flags&layers.IPv4MoreFragments != 0 || ip.FragOffset != 0
+}
+
+// add takes one fragment seen at t and returns the datagram's payload once
+// every piece of it is in.
+func (d *defragmenter) add(ip *layers.IPv4, t time.Time) ([]byte, bool) {
+	d.expire(t)
+	src, _ := netip.AddrFromSlice(ip.SrcIP)
+	dst, _ := netip.AddrFromSlice(ip.DstIP)
+	key := fragKey{src: src.Unmap(), dst: dst.Unmap(), id: ip.Id, proto: ip.Protocol}
+	offset := int(ip.FragOffset) * 8
+	if offset+len(ip.Payload) > maxIPv4Payload {
+		d.drop(key)
+		return nil, false
+	}
+
+	dg := d.pending[key]
+	if dg == nil {
+		if d.pending == nil {
+			d.pending = make(map[fragKey]*pendingDatagram)
+		}
+		if len(d.pending) >= defragMaxDatagrams {
+			d.evict()
+		}
+		dg = &pendingDatagram{first: t}
+		d.pending[key] = dg
+	}
+	if ip.Flags&layers.IPv4MoreFragments == 0 {
+		dg.total = offset + len(ip.Payload)
+	}
+	for _, f := range dg.frags {
+		if f.offset == offset && len(f.data) == len(ip.Payload) {
+			return nil, false // retransmitted fragment
+		}
+	}
+	if len(dg.frags) >= defragMaxFragments {
+		d.drop(key)
+		return nil, false
+	}
+	if offset == 0 {
+		dg.headed = true
+	}
+	for d.bytes+len(ip.Payload) > defragMaxBytes && len(d.pending) > 1 {
+		d.evict()
+		if d.pending[key] == nil {
+			return nil, false // this datagram was the one to go
+		}
+	}
+	// A copy, rather than holding on to the whole captured packet.
+	dg.frags = append(dg.frags, fragment{offset: offset, data: slices.Clone(ip.Payload)})
+	dg.bytes += len(ip.Payload)
+	d.bytes += len(ip.Payload)
+
+	payload, ok := dg.assemble()
+	if ok {
+		d.drop(key)
+		atomic.AddUint64(&d.reassembled, 1)
+	}
+	return payload, ok
+}
+
+// assemble joins the fragments once they cover the whole datagram.
+func (dg *pendingDatagram) assemble() ([]byte, bool) {
+	if dg.total == 0 {
+		return nil, false
+	}
+	slices.SortFunc(dg.frags, func(a, b fragment) int { return a.offset - b.offset })
+	end := 0
+	for _, f := range dg.frags {
+		if f.offset > end {
+			return nil, false // a hole
+		}
+		end = max(end, f.offset+len(f.data))
+	}
+	if end < dg.total {
+		return nil, false
+	}
+	out := make([]byte, dg.total)
+	for _, f := range dg.frags {
+		if f.offset < dg.total {
+			copy(out[f.offset:], f.data)
+		}
+	}
+	return out, true
+}
+
+// expire drops the datagrams still incomplete after defragTimeout.
+func (d *defragmenter) expire(now time.Time) {
+	for k, dg := range d.pending {
+		if now.Sub(dg.first) > defragTimeout {
+			d.giveUp(k, dg)
+		}
+	}
+}
+
+// evict makes room by giving up on the oldest orphan, or the oldest datagram
+// when there is none.
+func (d *defragmenter) evict() {
+	var oldest fragKey
+	var pick *pendingDatagram
+	for k, dg := range d.pending {
+		if pick == nil || (pick.headed && !dg.headed) ||
+			(pick.headed == dg.headed && dg.first.Before(pick.first)) {
+			oldest, pick = k, dg
+		}
+	}
+	if pick != nil {
+		d.giveUp(oldest, pick)
+	}
+}
+
+// giveUp drops an incomplete datagram, counting it as expired unless it is
+// an orphan.
+func (d *defragmenter) giveUp(k fragKey, dg *pendingDatagram) {
+	d.drop(k)
+	if dg.headed {
+		atomic.AddUint64(&d.expired, 1)
+	}
+}
+
+func (d *defragmenter) drop(k fragKey) {
+	if dg := d.pending[k]; dg != nil {
+		d.bytes -= dg.bytes
+		delete(d.pending, k)
+	}
+}
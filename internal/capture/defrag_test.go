@@ -0,0 +1,159 @@
+package capture
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+
+	"github.com/nospy/albion-openradar/internal/photon"
+)
+
+// buildFragments splits a UDP datagram from src to dst into IPv4 fragments
+// of at most size payload bytes (a multiple of 8), as a small-MTU adapter
+// would.
+func buildFragments(t *testing.T, src, dst netip.AddrPort, id uint16, payload []byte, size int) []gopacket.Packet {
+	t.Helper()
+	datagram := make([]byte, 8+len(payload))
+	binary.BigEndian.PutUint16(datagram[0:], src.Port())
+	binary.BigEndian.PutUint16(datagram[2:], dst.Port())
+	binary.BigEndian.PutUint16(datagram[4:], uint16(len(datagram)))
+	copy(datagram[8:], payload)
+
+	var out []gopacket.Packet
+	for off := 0; off < len(datagram); off += size {
+		end := min(off+size, len(datagram))
+		ip := &layers.IPv4{
+			Version:    4,
+			TTL:        64,
+			Id:         id,
+			Protocol:   layers.IPProtocolUDP,
+			SrcIP:      src.Addr().AsSlice(),
+			DstIP:      dst.Addr().AsSlice(),
+			FragOffset: uint16(off / 8),
+		}
+		if end < len(datagram) {
+			ip.Flags = layers.IPv4MoreFragments
+		}
+		eth := &layers.Ethernet{
+			SrcMAC:       []byte{0, 0, 0, 0, 0, 1},
+			DstMAC:       []byte{0, 0, 0, 0, 0, 2},
+			EthernetType: layers.EthernetTypeIPv4,
+		}
+		buf := gopacket.NewSerializeBuffer()
+		if err := gopacket.SerializeLayers(buf, gopacket.SerializeOptions{FixLengths: true}, eth, ip, gopacket.Payload(datagram[off:end])); err != nil {
+			t.Fatalf("SerializeLayers: %v", err)
+		}
+		pkt := gopacket.NewPacket(buf.Bytes(), layers.LayerTypeEthernet, gopacket.Default)
+		pkt.Metadata().Timestamp = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+		pkt.Metadata().CaptureLength = len(buf.Bytes())
+		pkt.Metadata().Length = len(buf.Bytes())
+		out = append(out, pkt)
+	}
+	return out
+}
+
+var (
+	fragServer = netip.MustParseAddrPort("5.188.125.10:5056")
+	fragClient = netip.MustParseAddrPort("10.8.0.2:50000")
+)
+
+// synthetic: packets are constructed in-process; no live Albion traffic needed.
+func TestCapturer_ReassemblesFragmentedDatagrams(t *testing.T) {
+	c := newStubCapturer(NetworkInterface{Name: "tun0"})
+	var got [][]byte
+	var metas []photon.PacketMeta
+	c.OnPacket(func(payload []byte, meta photon.PacketMeta) {
+		got = append(got, bytes.Clone(payload))
+		metas = append(metas, meta)
+	})
+
+	payload := bytes.Repeat([]byte("photon"), 400) // 2400 bytes, past a 1280 MTU
+	frags := buildFragments(t, fragServer, fragClient, 7, payload, 1200)
+	if len(frags) != 3 {
+		t.Fatalf("%d fragments, want 3", len(frags))
+	}
+	// Out of order, with the middle one repeated.
+	for _, i := range []int{2, 0, 2, 1} {
+		c.processPacket(frags[i])
+	}
+
+	if len(got) != 1 || !bytes.Equal(got[0], payload) {
+		t.Fatalf("got %d payloads, want the %d byte datagram once", len(got), len(payload))
+	}
+	if metas[0].Direction != photon.DirToClient || metas[0].Server != fragServer || metas[0].Client != fragClient {
+		t.Errorf("meta %+v, want server->client between %s and %s", metas[0], fragServer, fragClient)
+	}
+	if c.FragmentsReassembled() != 1 || c.FragmentsExpired() != 0 {
+		t.Errorf("reassembled %d, expired %d; want 1 and 0", c.FragmentsReassembled(), c.FragmentsExpired())
+	}
+	if len(c.defrag.pending) != 0 || c.defrag.bytes != 0 {
+		t.Errorf("%d datagrams, %d bytes still pending", len(c.defrag.pending), c.defrag.bytes)
+	}
+}
+
+// synthetic: packets are constructed in-process; no live Albion traffic needed.
+func TestCapturer_DefragmenterIsBounded(t *testing.T) {
+	c := newStubCapturer(NetworkInterface{Name: "tun0"})
+	c.OnPacket(func([]byte, photon.PacketMeta) { t.Error("an incomplete datagram reached the parser") })
+
+	// First fragments only: every datagram stays incomplete.
+	for id := range defragMaxDatagrams + 10 {
+		c.processPacket(buildFragments(t, fragServer, fragClient, uint16(id), make([]byte, 2000), 1000)[0])
+	}
+	if n := len(c.defrag.pending); n != defragMaxDatagrams {
+		t.Fatalf("%d datagrams pending, want the cap of %d", n, defragMaxDatagrams)
+	}
+	if c.FragmentsExpired() != 10 {
+		t.Errorf("expired %d, want the 10 evicted", c.FragmentsExpired())
+	}
+
+	late := buildFragments(t, fragServer, fragClient, 9999, make([]byte, 2000), 1000)[0]
+	late.Metadata().Timestamp = late.Metadata().Timestamp.Add(defragTimeout + time.Second)
+	c.processPacket(late)
+	if len(c.defrag.pending) != 1 || c.FragmentsExpired() != 10+defragMaxDatagrams {
+		t.Errorf("after the timeout: %d pending, %d expired", len(c.defrag.pending), c.FragmentsExpired())
+	}
+}
+
+// synthetic: packets are constructed in-process; no live Albion traffic needed.
+func TestCapturer_DefragmenterEvictsOrphansFirst(t *testing.T) {
+	c := newStubCapturer(NetworkInterface{Name: "tun0"})
+	var got int
+	c.OnPacket(func([]byte, photon.PacketMeta) { got++ })
+	dnsServer := netip.MustParseAddrPort("1.1.1.1:53")
+
+	game := buildFragments(t, fragServer, fragClient, 7, make([]byte, 2000), 1000)
+	c.processPacket(game[0])
+
+	// Later fragments of DNS replies: their first fragment is filtered out,
+	// so they never complete.
+	for id := range 2 * defragMaxDatagrams {
+		c.processPacket(buildFragments(t, dnsServer, fragClient, uint16(100+id), make([]byte, 2000), 1000)[1])
+	}
+	if n := len(c.defrag.pending); n > defragMaxDatagrams {
+		t.Fatalf("%d datagrams pending, want at most %d", n, defragMaxDatagrams)
+	}
+	if c.FragmentsExpired() != 0 {
+		t.Errorf("expired %d, want orphans left out of the count", c.FragmentsExpired())
+	}
+
+	// Large orphans hit the byte cap before the datagram cap.
+	for id := range defragMaxDatagrams {
+		c.processPacket(buildFragments(t, dnsServer, fragClient, uint16(1000+id), make([]byte, 60000), 30000)[1])
+	}
+	if c.defrag.bytes > defragMaxBytes {
+		t.Errorf("%d bytes held, want at most %d", c.defrag.bytes, defragMaxBytes)
+	}
+
+	for _, f := range game[1:] {
+		c.processPacket(f)
+	}
+	if got != 1 || c.FragmentsReassembled() != 1 {
+		t.Errorf("the game datagram was evicted: %d delivered, %d reassembled", got, c.FragmentsReassembled())
+	}
+}
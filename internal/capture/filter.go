@@ -0,0 +1,92 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/gopacket/layers"
+	"github.com/google/gopacket/pcap"
+)
+
+// maxFilterPorts and maxExtraBPF bound what network.json and the settings
+// API accept.
+const (
+	maxFilterPorts = 16
+	maxExtraBPF    = 1024
+)
+
+// ErrInvalidFilter wraps every FilterConfig.Validate failure.
+var ErrInvalidFilter = errors.New("invalid capture filter")
+
+// FilterConfig is what the capturers take in. Ports are the game server UDP
+// ports, 5056 when empty; some tunnelling tools move the game to others.
+// ExtraBPF is a BPF expression OR-ed onto the port filter to capture more,
+// such as the login or chat servers, for research. Only packets on one of
+// Ports reach the parser; the extra ones end up in recordings and the packet
+// buffer.
+type FilterConfig struct {
+	Ports    []uint16 `json:"ports,omitempty"`
+	ExtraBPF string   `json:"extraBPF,omitempty"`
+}
+
+// PortsOrDefault returns the saved ports, or AlbionPort.
+func (f FilterConfig) PortsOrDefault() []uint16 {
+	if len(f.Ports) == 0 {
+		return []uint16{AlbionPort}
+	}
+	return f.Ports
+}
+
+// udpFragments matches the IPv4 fragments of UDP datagrams past the first.
+// They carry no UDP header, so a port filter alone would drop them and the
+// datagram could not be reassembled. It over-matches on purpose: later
+// fragments of any UDP traffic get in, and the defragmenter drops those whose
+// first fragment never comes (see defragmenter).
+const udpFragments = "(ip proto 17 and ip[6:2] & 0x1fff != 0)"
+
+// Expression is the BPF filter the capturers are opened with.
+func (f FilterConfig) Expression() string {
+	ports := f.PortsOrDefault()
+	parts := make([]string, len(ports))
+	for i, p := range ports {
+		parts[i] = "port " + strconv.Itoa(int(p))
+	}
+	expr := "(udp and (" + strings.Join(parts, " or ") + ")) or " + udpFragments
+	if extra := strings.TrimSpace(f.ExtraBPF); extra != "" {
+		expr = "(" + expr + ") or (" + extra + ")"
+	}
+	return expr
+}
+
+// Validate checks the ports and compiles Expression, so a typo is reported
+// before any capturer is reopened with it.
+func (f FilterConfig) Validate() error {
+	if len(f.Ports) > maxFilterPorts {
+		return fmt.Errorf("%w: %d ports, at most %d", ErrInvalidFilter, len(f.Ports), maxFilterPorts)
+	}
+	for i, p := range f.Ports {
+		if p == 0 {
+			return fmt.Errorf("%w: port 0", ErrInvalidFilter)
+		}
+		if slices.Contains(f.Ports[:i], p) {
+			return fmt.Errorf("%w: port %d listed twice", ErrInvalidFilter, p)
+		}
+	}
+	if len(f.ExtraBPF) > maxExtraBPF {
+		return fmt.Errorf("%w: extra BPF longer than %d bytes", ErrInvalidFilter, maxExtraBPF)
+	}
+	if err := compileBPF(f.Expression()); err != nil {
+		return fmt.Errorf("%w: %q: %v", ErrInvalidFilter, f.Expression(), err)
+	}
+	return nil
+}
+
+// compileBPF checks an expression without opening a device; overridable in
+// tests, restore via t.Cleanup.
+var compileBPF = func(expr string) error {
+	_, err := pcap.CompileBPFFilter(layers.LinkTypeEthernet, SnapLen, expr)
+	return err
+}
@@ -0,0 +1,121 @@
+package capture
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/nospy/albion-openradar/internal/photon"
+)
+
+// withFakeCompiler stands in for libpcap's compiler: it rejects expressions
+// containing "bogus".
+func withFakeCompiler(t *testing.T) {
+	t.Helper()
+	prev := compileBPF
+	compileBPF = func(expr string) error {
+		if strings.Contains(expr, "bogus") {
+			return errors.New("syntax error")
+		}
+		return nil
+	}
+	t.Cleanup(func() { compileBPF = prev })
+}
+
+func TestFilterConfig_Expression(t *testing.T) {
+	cases := []struct {
+		f    FilterConfig
+		want string
+	}{
+		{FilterConfig{}, "(udp and (port 5056)) or " + udpFragments},
+		{FilterConfig{Ports: []uint16{5056, 5055}}, "(udp and (port 5056 or port 5055)) or " + udpFragments},
+		{FilterConfig{ExtraBPF: "  tcp port 443 "}, "((udp and (port 5056)) or " + udpFragments + ") or (tcp port 443)"},
+	}
+	for _, c := range cases {
+		if got := c.f.Expression(); got != c.want {
+			t.Errorf("%+v: got %q, want %q", c.f, got, c.want)
+		}
+	}
+}
+
+func TestFilterConfig_Validate(t *testing.T) {
+	withFakeCompiler(t)
+	bad := []FilterConfig{
+		{Ports: []uint16{0}},
+		{Ports: []uint16{5056, 5056}},
+		{Ports: make([]uint16, maxFilterPorts+1)},
+		{ExtraBPF: "bogus and"},
+		{ExtraBPF: strings.Repeat("x", maxExtraBPF+1)},
+	}
+	for _, f := range bad {
+		if err := f.Validate(); !errors.Is(err, ErrInvalidFilter) {
+			t.Errorf("%+v: got %v, want ErrInvalidFilter", f, err)
+		}
+	}
+	if err := (FilterConfig{Ports: []uint16{5056, 5055}, ExtraBPF: "udp port 4535"}).Validate(); err != nil {
+		t.Errorf("valid filter rejected: %v", err)
+	}
+}
+
+func TestManager_SetFilter_ReopensTheCapturers(t *testing.T) {
+	defer withStubFactory(t, nil)()
+	withFakeCompiler(t)
+
+	m := NewManager(context.Background())
+	m.OnPacket(func([]byte, photon.PacketMeta) {})
+	if err := m.Reconfigure([]NetworkInterface{{Name: "a"}}); err != nil {
+		t.Fatal(err)
+	}
+	m.mu.Lock()
+	before := m.active["a"].cap
+	m.mu.Unlock()
+
+	if err := m.SetFilter(FilterConfig{ExtraBPF: "bogus"}); !errors.Is(err, ErrInvalidFilter) {
+		t.Fatalf("got %v, want ErrInvalidFilter", err)
+	}
+	if err := m.SetFilter(FilterConfig{Ports: []uint16{5056, 5055}}); err != nil {
+		t.Fatalf("SetFilter: %v", err)
+	}
+	m.mu.Lock()
+	after := m.active["a"]
+	m.mu.Unlock()
+	if after == nil || after.cap == before {
+		t.Fatal("the capturer was not reopened with the new filter")
+	}
+	if !strings.Contains(after.filter, "port 5055") || len(after.cap.ports) != 2 {
+		t.Errorf("reopened with %q, ports %v", after.filter, after.cap.ports)
+	}
+	if before.ctx.Err() == nil {
+		t.Error("the old capturer is still running")
+	}
+
+	// Same filter again: nothing to reopen.
+	if err := m.Reconfigure([]NetworkInterface{{Name: "a"}}); err != nil {
+		t.Fatal(err)
+	}
+	m.mu.Lock()
+	same := m.active["a"].cap == after.cap
+	m.mu.Unlock()
+	if !same {
+		t.Error("Reconfigure reopened a capturer whose filter did not change")
+	}
+	m.Close(context.Background())
+}
+
+// synthetic: packets are constructed in-process; no live Albion traffic needed.
+func TestCapturer_OnlyGamePortsReachTheParser(t *testing.T) {
+	c := newStubCapturer(NetworkInterface{Name: "eth0"})
+	c.ports = []uint16{5055}
+	var got []photon.PacketMeta
+	c.OnPacket(func(_ []byte, meta photon.PacketMeta) { got = append(got, meta) })
+
+	client := netip.MustParseAddrPort("192.168.1.2:50000")
+	c.processPacket(buildFlowPacket(t, client, netip.MustParseAddrPort("5.188.125.10:5055"), []byte("game")))
+	c.processPacket(buildFlowPacket(t, client, netip.MustParseAddrPort("5.188.125.10:4535"), []byte("extra")))
+
+	if len(got) != 1 || got[0].Direction != photon.DirToServer || got[0].Server.Port() != 5055 {
+		t.Fatalf("got %+v, want only the packet to :5055, client->server", got)
+	}
+}
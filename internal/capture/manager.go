@@ -7,6 +7,7 @@ import (
 	"maps"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/nospy/albion-openradar/internal/logger"
@@ -25,6 +26,36 @@ type CaptureSummary struct {
 	Address     string    `json:"address"`
 	Category    Category  `json:"category"`
 	StartedAt   time.Time `json:"startedAt"`
+	// Endpoints are the game servers seen on the interface, most recent
+	// first.
+	Endpoints []GameEndpoint `json:"endpoints"`
+}
+
+// GameEndpoint is a game server an interface exchanged packets with. Region
+// is "" when the region table does not cover the address, as happens behind
+// a proxy such as ExitLag.
+type GameEndpoint struct {
+	Address  string    `json:"address"`
+	Region   string    `json:"region"`
+	Packets  uint64    `json:"packets"`
+	LastSeen time.Time `json:"lastSeen"`
+}
+
+// InterfaceStats is one active capturer's counters. The Kernel* fields come
+// from pcap_stats and stay zero when the handle cannot report them.
+type InterfaceStats struct {
+	Name              string
+	Description       string
+	BytesReceived     uint64
+	PacketsReceived   uint64
+	KernelReceived    uint64
+	KernelDropped     uint64
+	InterfaceDropped  uint64
+	RecordWriteErrors uint64
+	// FragmentsReassembled and FragmentsExpired count the fragmented IPv4
+	// datagrams put back together and given up on.
+	FragmentsReassembled uint64
+	FragmentsExpired     uint64
 }
 
 type State struct {
@@ -38,20 +69,33 @@ var managerStartWorker = startWorker
 type Manager struct {
 	parentCtx context.Context
 
-	mu               sync.Mutex
-	active           map[string]*managedCapturer
-	wg               sync.WaitGroup
-	onPacket         PacketHandler
-	lastErrors       map[string]string
-	closed           bool
-	recordingEnabled bool
-	recordingDir     string
+	mu                sync.Mutex
+	active            map[string]*managedCapturer
+	wg                sync.WaitGroup
+	onPacket          PacketHandler
+	lastErrors        map[string]string
+	closed            bool
+	recordingEnabled  bool
+	recordingDir      string
+	recordingMaxBytes int64
+	recordingFormat   RecordingFormat
+	application       string
+	// ng is the shared recording while recording in FormatPcapng.
+	ng           *ngRecording
+	ringMaxAge   time.Duration
+	ringMaxBytes int64
+	// regions names the region of the game servers in State.
+	regions *RegionTable
+	// filter is what capturers are opened with; see SetFilter.
+	filter FilterConfig
 }
 
 type managedCapturer struct {
 	cap       *Capturer
 	startedAt time.Time
 	cancel    context.CancelFunc
+	// filter is the expression the capturer was opened with.
+	filter string
 }
 
 func NewManager(parentCtx context.Context) *Manager {
@@ -84,32 +128,25 @@ func (m *Manager) Reconfigure(target []NetworkInterface) error {
 		desired[i.Name] = i
 	}
 
+	// A capturer opened with an older filter is closed here and reopened
+	// below, as if it were new.
+	expr := m.filter.Expression()
+	for name, mc := range m.active {
+		if _, keep := desired[name]; keep && mc.filter != expr {
+			mc.cancel()
+			mc.cap.Close()
+			delete(m.active, name)
+		}
+	}
+
 	var openErrs []string
 	for name, iface := range desired {
 		if _, exists := m.active[name]; exists {
 			continue
 		}
-		c, err := captureFactory(m.parentCtx, iface)
-		if err != nil {
-			m.lastErrors[name] = err.Error()
+		if err := m.openLocked(iface); err != nil {
 			openErrs = append(openErrs, fmt.Sprintf("%s: %v", name, err))
-			continue
 		}
-		c.OnPacket(m.onPacket)
-		mc := &managedCapturer{cap: c, startedAt: time.Now(), cancel: c.cancel}
-		m.active[name] = mc
-		delete(m.lastErrors, name)
-		if m.recordingEnabled {
-			if rErr := c.StartRecording(m.recordingDir); rErr != nil {
-				m.lastErrors[name] = rErr.Error()
-			}
-		}
-		managerStartWorker(c, &m.wg, func(n string, e error) {
-			m.mu.Lock()
-			m.lastErrors[n] = e.Error()
-			delete(m.active, n)
-			m.mu.Unlock()
-		})
 	}
 
 	for name, mc := range m.active {
@@ -130,17 +167,81 @@ func (m *Manager) Reconfigure(target []NetworkInterface) error {
 	return nil
 }
 
+// Open starts capturing on one more interface, leaving the others as they
+// are. It does nothing when the interface is already captured. The
+// interface watcher uses it to bring back an interface that reappeared.
+func (m *Manager) Open(iface NetworkInterface) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return errors.New("manager closed")
+	}
+	if m.onPacket == nil {
+		return errors.New("OnPacket must be called before Open")
+	}
+	if _, exists := m.active[iface.Name]; exists {
+		return nil
+	}
+	return m.openLocked(iface)
+}
+
+// openLocked opens a capturer on iface with the current filter and starts
+// its read loop. A failure is kept in lastErrors.
+func (m *Manager) openLocked(iface NetworkInterface) error {
+	name := iface.Name
+	c, err := captureFactory(m.parentCtx, iface, m.filter)
+	if err != nil {
+		m.lastErrors[name] = err.Error()
+		return err
+	}
+	c.OnPacket(m.onPacket)
+	c.SetRecordingMaxBytes(m.recordingMaxBytes)
+	c.SetRingBuffer(m.ringMaxAge, m.ringMaxBytes)
+	mc := &managedCapturer{cap: c, startedAt: time.Now(), cancel: c.cancel, filter: m.filter.Expression()}
+	m.active[name] = mc
+	delete(m.lastErrors, name)
+	if m.recordingEnabled {
+		if rErr := m.startRecordingLocked(c); rErr != nil {
+			m.lastErrors[name] = rErr.Error()
+		}
+	}
+	managerStartWorker(c, &m.wg, func(n string, e error) {
+		m.mu.Lock()
+		// A capturer replaced in the meantime is not this one's to drop.
+		if m.active[n] == mc {
+			m.lastErrors[n] = e.Error()
+			delete(m.active, n)
+		}
+		m.mu.Unlock()
+		// Dropped or replaced, a dead capturer is nobody else's to close:
+		// release its handle and finish its recording file.
+		c.Close()
+	})
+	return nil
+}
+
 // StartRecording enables recording on all active capturers and on any future
 // ones added via Reconfigure. If a capturer fails to start, the error is
-// logged as a warning and the others continue.
+// logged as a warning and the others continue. In FormatPcapng they all
+// share one file, and failing to create it fails the whole start.
 func (m *Manager) StartRecording(dir string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	if m.recordingFormat == FormatPcapng {
+		if m.ng != nil {
+			return errors.New("recording already in progress")
+		}
+		ng, err := newNgRecording(dir, m.application, m.recordingMaxBytes)
+		if err != nil {
+			return err
+		}
+		m.ng = ng
+	}
 	m.recordingEnabled = true
 	m.recordingDir = dir
 	var firstErr error
 	for name, mc := range m.active {
-		if err := mc.cap.StartRecording(dir); err != nil {
+		if err := m.startRecordingLocked(mc.cap); err != nil {
 			logger.PrintWarn("PKT", "pcap recording could not start on %s: %v", name, err)
 			if firstErr == nil {
 				firstErr = fmt.Errorf("%s: %w", name, err)
@@ -150,6 +251,15 @@ func (m *Manager) StartRecording(dir string) error {
 	return firstErr
 }
 
+// startRecordingLocked starts c on the shared pcapng recording, or on a
+// pcap file of its own.
+func (m *Manager) startRecordingLocked(c *Capturer) error {
+	if m.ng != nil {
+		return c.recordInto(m.ng)
+	}
+	return c.StartRecording(m.recordingDir)
+}
+
 // StopRecording disables recording on all active capturers.
 func (m *Manager) StopRecording() error {
 	m.mu.Lock()
@@ -165,9 +275,191 @@ func (m *Manager) StopRecording() error {
 			}
 		}
 	}
+	if err := m.closeNgLocked(); err != nil && firstErr == nil {
+		firstErr = err
+	}
 	return firstErr
 }
 
+func (m *Manager) closeNgLocked() error {
+	if m.ng == nil {
+		return nil
+	}
+	err := m.ng.close()
+	m.ng = nil
+	if err != nil {
+		logger.PrintWarn("PKT", "pcapng recording could not close: %v", err)
+	}
+	return err
+}
+
+// SetRecordingFormat picks the format of the next recordings. A running
+// recording is stopped and started again in the new format.
+func (m *Manager) SetRecordingFormat(f RecordingFormat) error {
+	if f == "" {
+		f = FormatPcap
+	}
+	m.mu.Lock()
+	current := m.recordingFormat
+	if current == "" {
+		current = FormatPcap
+	}
+	m.recordingFormat = f
+	running, dir := m.recordingEnabled, m.recordingDir
+	m.mu.Unlock()
+	if f == current || !running {
+		return nil
+	}
+	if err := m.StopRecording(); err != nil {
+		return err
+	}
+	return m.StartRecording(dir)
+}
+
+// SetApplication names the radar, with its version, in the header of pcapng
+// recordings started from now on.
+func (m *Manager) SetApplication(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.application = name
+}
+
+// SetFilter validates f and makes it the capture filter. The open capturers
+// are reopened with it through Reconfigure; the error is that of either
+// step, and wraps ErrInvalidFilter when f was rejected.
+func (m *Manager) SetFilter(f FilterConfig) error {
+	if err := f.Validate(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	m.filter = f
+	target := make([]NetworkInterface, 0, len(m.active))
+	for _, mc := range m.active {
+		target = append(target, mc.cap.iface)
+	}
+	m.mu.Unlock()
+	if len(target) == 0 {
+		return nil
+	}
+	return m.Reconfigure(target)
+}
+
+// Filter returns the capture filter in force.
+func (m *Manager) Filter() FilterConfig {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.filter
+}
+
+// SetRegions sets the table State maps game server addresses with.
+func (m *Manager) SetRegions(t *RegionTable) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.regions = t
+}
+
+// Annotate attaches comment to the next packet of a pcapng recording. It is
+// a no-op when not recording in FormatPcapng.
+func (m *Manager) Annotate(comment string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.ng != nil {
+		m.ng.annotate(comment)
+	}
+}
+
+// SetRecordingMaxBytes sets the rotation size of every recording, current
+// and future. Zero disables rotation.
+func (m *Manager) SetRecordingMaxBytes(n int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.recordingMaxBytes = n
+	for _, mc := range m.active {
+		mc.cap.SetRecordingMaxBytes(n)
+	}
+	if m.ng != nil {
+		m.ng.setMaxBytes(n)
+	}
+}
+
+// RecordingFiles lists the pcap and pcapng files being written.
+func (m *Manager) RecordingFiles() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []string
+	if m.ng != nil {
+		if p := m.ng.path(); p != "" {
+			out = append(out, p)
+		}
+	}
+	for _, mc := range m.active {
+		if p := mc.cap.RecordingPath(); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// SetRingBuffer sizes the in-memory packet buffer of every capturer, current
+// and future. Each capturer keeps its own maxAge and maxBytes. Zero for both
+// turns the buffers off.
+func (m *Manager) SetRingBuffer(maxAge time.Duration, maxBytes int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ringMaxAge, m.ringMaxBytes = maxAge, maxBytes
+	for _, mc := range m.active {
+		mc.cap.SetRingBuffer(maxAge, maxBytes)
+	}
+}
+
+// RingStats sums the buffered packets and bytes across capturers.
+func (m *Manager) RingStats() (packets int, bytes int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, mc := range m.active {
+		n, b := mc.cap.RingStats()
+		packets += n
+		bytes += b
+	}
+	return packets, bytes
+}
+
+// FlushRing saves every capturer's buffer to dir, one file per interface,
+// and returns the files written. Empty buffers are skipped; ErrRingEmpty is
+// returned when all of them were, and ErrRingDisabled when the buffer is
+// turned off.
+func (m *Manager) FlushRing(dir string) ([]string, error) {
+	m.mu.Lock()
+	if m.ringMaxAge <= 0 && m.ringMaxBytes <= 0 {
+		m.mu.Unlock()
+		return nil, ErrRingDisabled
+	}
+	captures := make([]*Capturer, 0, len(m.active))
+	for _, mc := range m.active {
+		captures = append(captures, mc.cap)
+	}
+	m.mu.Unlock()
+
+	var paths []string
+	var errs []error
+	for _, c := range captures {
+		path, err := c.FlushRing(dir)
+		if errors.Is(err, ErrRingEmpty) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", c.iface.Name, err))
+			continue
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	if len(paths) == 0 && len(errs) == 0 {
+		return nil, ErrRingEmpty
+	}
+	return paths, errors.Join(errs...)
+}
+
 // IsRecording reports whether the Manager has recording enabled.
 func (m *Manager) IsRecording() bool {
 	m.mu.Lock()
@@ -187,6 +479,32 @@ func (m *Manager) BytesReceived() uint64 {
 	return sum
 }
 
+// InterfaceStats reports per-capturer counters, sorted by interface name.
+func (m *Manager) InterfaceStats() []InterfaceStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]InterfaceStats, 0, len(m.active))
+	for _, mc := range m.active {
+		st := InterfaceStats{
+			Name:                 mc.cap.iface.Name,
+			Description:          mc.cap.iface.Description,
+			BytesReceived:        mc.cap.BytesReceived(),
+			PacketsReceived:      mc.cap.PacketsReceived(),
+			RecordWriteErrors:    atomic.LoadUint64(&mc.cap.recordWriteErrors),
+			FragmentsReassembled: mc.cap.FragmentsReassembled(),
+			FragmentsExpired:     mc.cap.FragmentsExpired(),
+		}
+		if ps, err := mc.cap.Stats(); err == nil && ps != nil {
+			st.KernelReceived = uint64(ps.PacketsReceived)
+			st.KernelDropped = uint64(ps.PacketsDropped)
+			st.InterfaceDropped = uint64(ps.PacketsIfDropped)
+		}
+		out = append(out, st)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
 func (m *Manager) State() State {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -196,13 +514,23 @@ func (m *Manager) State() State {
 	maps.Copy(out.LastErrors, m.lastErrors)
 	for _, mc := range m.active {
 		i := mc.cap.iface
-		out.Active = append(out.Active, CaptureSummary{
+		summary := CaptureSummary{
 			Name:        i.Name,
 			Description: i.Description,
 			Address:     i.Address,
 			Category:    Categorize(i.Name, i.Description),
 			StartedAt:   mc.startedAt,
-		})
+			Endpoints:   make([]GameEndpoint, 0),
+		}
+		for _, e := range mc.cap.gameEndpoints() {
+			summary.Endpoints = append(summary.Endpoints, GameEndpoint{
+				Address:  e.addr.String(),
+				Region:   m.regions.Lookup(e.addr.Addr()),
+				Packets:  e.packets,
+				LastSeen: e.lastSeen,
+			})
+		}
+		out.Active = append(out.Active, summary)
 	}
 	sort.Slice(out.Active, func(i, j int) bool { return out.Active[i].Name < out.Active[j].Name })
 	if len(out.Active) == 0 {
@@ -245,6 +573,9 @@ func (m *Manager) Close(closeCtx context.Context) {
 	for _, c := range captures {
 		c.Close()
 	}
+	m.mu.Lock()
+	_ = m.closeNgLocked() // logged; nothing else to do during shutdown
+	m.mu.Unlock()
 }
 
 func startWorker(c *Capturer, wg *sync.WaitGroup, onError func(string, error)) {
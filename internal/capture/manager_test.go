@@ -14,6 +14,8 @@ import (
 	"github.com/google/gopacket"
 	"github.com/google/gopacket/layers"
 	"github.com/google/gopacket/pcapgo"
+
+	"github.com/nospy/albion-openradar/internal/photon"
 )
 
 func filepathGlob(t *testing.T, dir, pattern string) ([]string, error) {
@@ -64,12 +66,13 @@ func newStubCapturer(iface NetworkInterface) *Capturer {
 func withStubFactory(t *testing.T, opens map[string]error) func() {
 	t.Helper()
 	prev := captureFactory
-	captureFactory = func(ctx context.Context, iface NetworkInterface) (*Capturer, error) {
+	captureFactory = func(ctx context.Context, iface NetworkInterface, filter FilterConfig) (*Capturer, error) {
 		if err, ok := opens[iface.Name]; ok && err != nil {
 			return nil, err
 		}
 		c := newStubCapturer(iface)
 		c.ctx, c.cancel = context.WithCancel(ctx)
+		c.ports = filter.PortsOrDefault()
 		return c, nil
 	}
 	return func() { captureFactory = prev }
@@ -79,7 +82,7 @@ func TestManagerReconfigureAddsRemoves(t *testing.T) {
 	defer withStubFactory(t, nil)()
 
 	m := NewManager(context.Background())
-	m.OnPacket(func([]byte) {})
+	m.OnPacket(func([]byte, photon.PacketMeta) {})
 
 	if err := m.Reconfigure([]NetworkInterface{{Name: "a", Device: "a"}, {Name: "b", Device: "b"}}); err != nil {
 		t.Fatalf("Reconfigure add: %v", err)
@@ -119,7 +122,7 @@ func TestManagerOpenFailureIsolatesOthers(t *testing.T) {
 	defer withStubFactory(t, map[string]error{"bad": errors.New("boom")})()
 
 	m := NewManager(context.Background())
-	m.OnPacket(func([]byte) {})
+	m.OnPacket(func([]byte, photon.PacketMeta) {})
 	err := m.Reconfigure([]NetworkInterface{
 		{Name: "good", Device: "good"},
 		{Name: "bad", Device: "bad"},
@@ -138,10 +141,51 @@ func TestManagerOpenFailureIsolatesOthers(t *testing.T) {
 	m.Close(context.Background())
 }
 
+func TestManagerOpenAddsOneInterface(t *testing.T) {
+	defer withStubFactory(t, map[string]error{"bad": errors.New("boom")})()
+
+	m := NewManager(context.Background())
+	m.OnPacket(func([]byte, photon.PacketMeta) {})
+	if err := m.Reconfigure([]NetworkInterface{{Name: "a", Device: "a"}}); err != nil {
+		t.Fatal(err)
+	}
+	m.mu.Lock()
+	before := m.active["a"].cap
+	m.mu.Unlock()
+
+	if err := m.Open(NetworkInterface{Name: "b", Device: "b"}); err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if err := m.Open(NetworkInterface{Name: "a", Device: "a"}); err != nil {
+		t.Fatalf("Open on an active interface: %v", err)
+	}
+	if err := m.Open(NetworkInterface{Name: "bad", Device: "bad"}); err == nil {
+		t.Fatal("expected the open error")
+	}
+	state := m.State()
+	if len(state.Active) != 2 || state.Active[0].Name != "a" || state.Active[1].Name != "b" {
+		t.Errorf("want {a,b}, got %+v", state.Active)
+	}
+	if state.LastErrors["bad"] != "boom" {
+		t.Errorf("lastErrors = %+v, want bad: boom", state.LastErrors)
+	}
+	m.mu.Lock()
+	same := m.active["a"].cap == before
+	m.mu.Unlock()
+	if !same {
+		t.Error("Open reopened an interface that was already captured")
+	}
+
+	m.Close(context.Background())
+	if err := m.Open(NetworkInterface{Name: "c"}); err == nil {
+		t.Error("Open after Close should fail")
+	}
+}
+
 func TestManagerCloseTwiceSafe(t *testing.T) {
 	defer withStubFactory(t, nil)()
 	m := NewManager(context.Background())
-	m.OnPacket(func([]byte) {})
+	m.OnPacket(func([]byte, photon.PacketMeta) {})
 	_ = m.Reconfigure([]NetworkInterface{{Name: "a", Device: "a"}})
 	m.Close(context.Background())
 	m.Close(context.Background())
@@ -150,7 +194,7 @@ func TestManagerCloseTwiceSafe(t *testing.T) {
 func TestManagerBytesReceivedAggregates(t *testing.T) {
 	defer withStubFactory(t, nil)()
 	m := NewManager(context.Background())
-	m.OnPacket(func([]byte) {})
+	m.OnPacket(func([]byte, photon.PacketMeta) {})
 	if err := m.Reconfigure([]NetworkInterface{{Name: "a", Device: "a"}, {Name: "b", Device: "b"}}); err != nil {
 		t.Fatal(err)
 	}
@@ -160,6 +204,32 @@ func TestManagerBytesReceivedAggregates(t *testing.T) {
 	m.Close(context.Background())
 }
 
+func TestManagerInterfaceStats(t *testing.T) {
+	defer withStubFactory(t, nil)()
+	m := NewManager(context.Background())
+	m.OnPacket(func([]byte, photon.PacketMeta) {})
+	if err := m.Reconfigure([]NetworkInterface{{Name: "b", Device: "b"}, {Name: "a", Description: "Wi-Fi", Device: "a"}}); err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close(context.Background())
+
+	m.mu.Lock()
+	atomic.AddUint64(&m.active["a"].cap.bytesReceived, 120)
+	atomic.AddUint64(&m.active["a"].cap.packetsReceived, 2)
+	m.mu.Unlock()
+
+	got := m.InterfaceStats()
+	if len(got) != 2 || got[0].Name != "a" || got[1].Name != "b" {
+		t.Fatalf("got %+v, want a then b", got)
+	}
+	if got[0].Description != "Wi-Fi" || got[0].BytesReceived != 120 || got[0].PacketsReceived != 2 {
+		t.Errorf("a: got %+v", got[0])
+	}
+	if got[1].KernelReceived != 0 || got[1].KernelDropped != 0 {
+		t.Errorf("stub handles have no pcap stats, got %+v", got[1])
+	}
+}
+
 func TestManagerNoGoroutineLeak(t *testing.T) {
 	defer withStubFactory(t, nil)()
 
@@ -175,7 +245,7 @@ func TestManagerNoGoroutineLeak(t *testing.T) {
 	defer func() { managerStartWorker = prev }()
 
 	m := NewManager(context.Background())
-	m.OnPacket(func([]byte) {})
+	m.OnPacket(func([]byte, photon.PacketMeta) {})
 	_ = m.Reconfigure([]NetworkInterface{{Name: "a", Device: "a"}, {Name: "b", Device: "b"}})
 
 	closeCtx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
@@ -190,11 +260,48 @@ func TestManagerNoGoroutineLeak(t *testing.T) {
 	}
 }
 
+func TestManager_DeadWorkerIsClosed(t *testing.T) {
+	defer withStubFactory(t, nil)()
+
+	var onError func(string, error)
+	prev := managerStartWorker
+	managerStartWorker = func(_ *Capturer, _ *sync.WaitGroup, fn func(string, error)) { onError = fn }
+	defer func() { managerStartWorker = prev }()
+
+	m := NewManager(context.Background())
+	m.OnPacket(func([]byte, photon.PacketMeta) {})
+	if err := m.StartRecording(t.TempDir()); err != nil {
+		t.Fatalf("StartRecording: %v", err)
+	}
+	if err := m.Reconfigure([]NetworkInterface{{Name: "a", Device: "a"}}); err != nil {
+		t.Fatalf("Reconfigure: %v", err)
+	}
+	m.mu.Lock()
+	c := m.active["a"].cap
+	m.mu.Unlock()
+	if !c.IsRecording() {
+		t.Fatal("capturer 'a' is not recording")
+	}
+
+	onError("a", errors.New("adapter removed"))
+
+	if len(m.State().Active) != 0 {
+		t.Error("the dead capturer is still active")
+	}
+	if c.ctx.Err() == nil {
+		t.Error("the dead capturer was not closed")
+	}
+	if c.IsRecording() {
+		t.Error("the dead capturer's recording was not stopped")
+	}
+	m.Close(context.Background())
+}
+
 func TestManager_StartRecording_PropagatesToActive(t *testing.T) {
 	defer withStubFactory(t, nil)()
 
 	m := NewManager(context.Background())
-	m.OnPacket(func([]byte) {})
+	m.OnPacket(func([]byte, photon.PacketMeta) {})
 	if err := m.Reconfigure([]NetworkInterface{{Name: "a", Device: "a"}}); err != nil {
 		t.Fatalf("Reconfigure: %v", err)
 	}
@@ -221,7 +328,7 @@ func TestManager_StartRecording_AppliesToFutureCapturers(t *testing.T) {
 	defer withStubFactory(t, nil)()
 
 	m := NewManager(context.Background())
-	m.OnPacket(func([]byte) {})
+	m.OnPacket(func([]byte, photon.PacketMeta) {})
 
 	dir := t.TempDir()
 	if err := m.StartRecording(dir); err != nil {
@@ -255,7 +362,7 @@ func TestManager_StartRecording_MultiInterface_PerCapturerFiles(t *testing.T) {
 	defer withStubFactory(t, nil)()
 
 	m := NewManager(context.Background())
-	m.OnPacket(func([]byte) {})
+	m.OnPacket(func([]byte, photon.PacketMeta) {})
 	if err := m.Reconfigure([]NetworkInterface{
 		{Name: "alpha", Device: "alpha"},
 		{Name: "beta", Device: "beta"},
@@ -302,7 +409,7 @@ func TestManager_StopRecording_StopsAllActive(t *testing.T) {
 	defer withStubFactory(t, nil)()
 
 	m := NewManager(context.Background())
-	m.OnPacket(func([]byte) {})
+	m.OnPacket(func([]byte, photon.PacketMeta) {})
 	if err := m.Reconfigure([]NetworkInterface{
 		{Name: "c", Device: "c"},
 		{Name: "d", Device: "d"},
@@ -2,6 +2,7 @@ package capture
 
 import (
 	"net"
+	"net/netip"
 	"sort"
 )
 
@@ -34,6 +35,18 @@ func IsRFC1918(addr string) bool {
 	return false
 }
 
+var ulaNet = netip.MustParsePrefix("fc00::/7")
+
+// IsPrivate reports whether addr is an RFC 1918 address or an IPv6 unique
+// local one (fc00::/7), the IPv6 counterpart tunnel adapters use.
+func IsPrivate(addr string) bool {
+	if IsRFC1918(addr) {
+		return true
+	}
+	a, err := netip.ParseAddr(addr)
+	return err == nil && ulaNet.Contains(a)
+}
+
 type lanCandidate struct {
 	name string
 	ip   string
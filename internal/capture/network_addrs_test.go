@@ -17,6 +17,19 @@ func TestIsRFC1918(t *testing.T) {
 	}
 }
 
+func TestIsPrivate(t *testing.T) {
+	for _, a := range []string{"10.0.0.1", "fd00:10::2", "fc01::1"} {
+		if !IsPrivate(a) {
+			t.Errorf("%s should be private", a)
+		}
+	}
+	for _, a := range []string{"8.8.8.8", "2001:db8::1", "fe80::1", ""} {
+		if IsPrivate(a) {
+			t.Errorf("%s should not be private", a)
+		}
+	}
+}
+
 func TestLANAddressesReturnsRFC1918OnlyOrEmpty(t *testing.T) {
 	got := LANAddresses()
 	for _, a := range got {
@@ -6,6 +6,9 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
+
+	"github.com/nospy/albion-openradar/internal/logger"
 )
 
 const (
@@ -18,14 +21,150 @@ type PersistedInterface struct {
 	Description string `json:"description"`
 }
 
+// LoggingConfig holds the logging settings. Levels maps a log tag ("PKT",
+// "NET", ...) or "default" to debug, info, success, warn or error.
 type LoggingConfig struct {
-	ServerLogsEnabled bool `json:"serverLogsEnabled"`
-	PcapRecording     bool `json:"pcapRecording"`
+	ServerLogsEnabled bool              `json:"serverLogsEnabled"`
+	PcapRecording     bool              `json:"pcapRecording"`
+	Levels            map[string]string `json:"levels,omitempty"`
+	// Retention is nil until first saved; RetentionOrDefault fills it in.
+	Retention *RetentionConfig `json:"retention,omitempty"`
+	// RingBuffer is nil until first saved; RingBufferOrDefault fills it in.
+	RingBuffer *RingBufferConfig `json:"ringBuffer,omitempty"`
+	// RecordingFormat is empty until first saved, which means FormatPcap.
+	RecordingFormat RecordingFormat `json:"recordingFormat,omitempty"`
+}
+
+// RecordingFormat picks how pcap recordings are written.
+type RecordingFormat string
+
+const (
+	// FormatPcap writes one classic pcap per interface.
+	FormatPcap RecordingFormat = "pcap"
+	// FormatPcapng writes a single pcapng for all interfaces, with one
+	// interface block each and comments for the radar version and cluster
+	// changes.
+	FormatPcapng RecordingFormat = "pcapng"
+)
+
+// RecordingFormatOrDefault returns the saved format, or FormatPcap.
+func (c LoggingConfig) RecordingFormatOrDefault() RecordingFormat {
+	if c.RecordingFormat == "" {
+		return FormatPcap
+	}
+	return c.RecordingFormat
+}
+
+// Validate rejects unknown formats.
+func (f RecordingFormat) Validate() error {
+	switch f {
+	case FormatPcap, FormatPcapng:
+		return nil
+	}
+	return fmt.Errorf("recording format %q: want %q or %q", string(f), FormatPcap, FormatPcapng)
+}
+
+// RetentionPolicy bounds the files of one directory tree. Zero fields
+// disable the matching limit.
+type RetentionPolicy struct {
+	MaxFileMB  int  `json:"maxFileMB"`
+	MaxAgeDays int  `json:"maxAgeDays"`
+	MaxTotalMB int  `json:"maxTotalMB"`
+	Compress   bool `json:"compress"`
+}
+
+// RetentionConfig has one policy for the session logs (sessions, debug and
+// errors) and one for the pcap recordings.
+type RetentionConfig struct {
+	Logs     RetentionPolicy `json:"logs"`
+	Captures RetentionPolicy `json:"captures"`
+}
+
+// DefaultRetention applies when network.json has no retention section. It
+// only rotates: nothing is compressed or deleted until a policy is saved.
+var DefaultRetention = RetentionConfig{
+	Logs:     RetentionPolicy{MaxFileMB: 50},
+	Captures: RetentionPolicy{MaxFileMB: 200},
+}
+
+// RetentionOrDefault returns the saved retention, or DefaultRetention.
+func (c LoggingConfig) RetentionOrDefault() RetentionConfig {
+	if c.Retention == nil {
+		return DefaultRetention
+	}
+	return *c.Retention
+}
+
+// Validate rejects negative limits.
+func (p RetentionPolicy) Validate() error {
+	if p.MaxFileMB < 0 || p.MaxAgeDays < 0 || p.MaxTotalMB < 0 {
+		return fmt.Errorf("retention limits cannot be negative")
+	}
+	return nil
+}
+
+// Policy converts to the logger's units.
+func (p RetentionPolicy) Policy() logger.Policy {
+	return logger.Policy{
+		MaxFileBytes:  int64(p.MaxFileMB) << 20,
+		MaxAge:        time.Duration(p.MaxAgeDays) * 24 * time.Hour,
+		MaxTotalBytes: int64(p.MaxTotalMB) << 20,
+		Compress:      p.Compress,
+	}
+}
+
+// RingBufferConfig sizes the in-memory buffer of recent packets that can be
+// saved after the fact. Zero Seconds and MaxMB turn it off. ParseErrorBurst
+// saves it on its own when that many parse errors arrive within
+// ParseErrorWindow; zero disables the trigger.
+type RingBufferConfig struct {
+	Seconds         int `json:"seconds"`
+	MaxMB           int `json:"maxMB"`
+	ParseErrorBurst int `json:"parseErrorBurst"`
+}
+
+// ParseErrorWindow is the span ParseErrorBurst is counted over.
+const ParseErrorWindow = 10 * time.Second
+
+// DefaultRingBuffer applies when network.json has no ringBuffer section:
+// the buffer is off until sized from the settings page, and the parse error
+// trigger only takes effect once it is on.
+var DefaultRingBuffer = RingBufferConfig{ParseErrorBurst: 50}
+
+// RingBufferOrDefault returns the saved buffer settings, or DefaultRingBuffer.
+func (c LoggingConfig) RingBufferOrDefault() RingBufferConfig {
+	if c.RingBuffer == nil {
+		return DefaultRingBuffer
+	}
+	return *c.RingBuffer
+}
+
+// Validate rejects negative values.
+func (r RingBufferConfig) Validate() error {
+	if r.Seconds < 0 || r.MaxMB < 0 || r.ParseErrorBurst < 0 {
+		return fmt.Errorf("ring buffer settings cannot be negative")
+	}
+	return nil
+}
+
+// MaxAge and MaxBytes convert to the units of Manager.SetRingBuffer.
+func (r RingBufferConfig) MaxAge() time.Duration { return time.Duration(r.Seconds) * time.Second }
+func (r RingBufferConfig) MaxBytes() int64       { return int64(r.MaxMB) << 20 }
+
+// ServerConfig is where the web UI listens. Zero values mean "all interfaces"
+// and the default port; the -listen and -port flags override both. TLS serves
+// HTTPS with a self-signed certificate, and the -tls flag can only turn it on.
+type ServerConfig struct {
+	ListenAddress string `json:"listenAddress,omitempty"`
+	Port          int    `json:"port,omitempty"`
+	TLS           bool   `json:"tls,omitempty"`
 }
 
 type Config struct {
 	CaptureInterfaces []PersistedInterface `json:"captureInterfaces"`
+	CaptureFilter     FilterConfig         `json:"captureFilter"`
 	Logging           LoggingConfig        `json:"logging"`
+	Server            ServerConfig         `json:"server"`
 }
 
 func ReadConfig(appDir string) (Config, error) {
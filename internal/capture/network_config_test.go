@@ -285,6 +285,20 @@ func TestConfigLoggingExplicitFalseRoundTrip(t *testing.T) {
 	}
 }
 
+func TestConfigServerRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	if err := WriteConfig(dir, Config{Server: ServerConfig{ListenAddress: "127.0.0.1", Port: 5080}}); err != nil {
+		t.Fatalf("WriteConfig: %v", err)
+	}
+	got, err := ReadConfig(dir)
+	if err != nil {
+		t.Fatalf("ReadConfig: %v", err)
+	}
+	if got.Server.ListenAddress != "127.0.0.1" || got.Server.Port != 5080 {
+		t.Errorf("Server = %+v, want 127.0.0.1:5080", got.Server)
+	}
+}
+
 func TestMutateConfig_PreservesUntouchedFields(t *testing.T) {
 	dir := t.TempDir()
 	seed := Config{
@@ -342,3 +356,13 @@ func TestWriteConfigOverwritesAtomically(t *testing.T) {
 		t.Errorf("network.json not valid JSON: %v", err)
 	}
 }
+
+func TestRetentionWithoutASavedPolicyDeletesNothing(t *testing.T) {
+	r := LoggingConfig{}.RetentionOrDefault()
+	for name, p := range map[string]RetentionPolicy{"logs": r.Logs, "captures": r.Captures} {
+		pol := p.Policy()
+		if pol.MaxAge != 0 || pol.MaxTotalBytes != 0 || pol.Compress {
+			t.Errorf("%s: default policy %+v compresses or deletes", name, pol)
+		}
+	}
+}
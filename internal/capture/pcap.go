@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/netip"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -17,12 +19,13 @@ import (
 	"github.com/google/gopacket/pcapgo"
 
 	"github.com/nospy/albion-openradar/internal/logger"
+	"github.com/nospy/albion-openradar/internal/photon"
 )
 
 var reUnsafeFilename = regexp.MustCompile(`[^A-Za-z0-9_\-]`)
 
 const (
-	AlbionPort  = 5056
+	AlbionPort  = photon.ServerPort
 	SnapLen     = 65536
 	Promiscuous = false
 	ReadTimeout = 100 * time.Millisecond
@@ -31,11 +34,71 @@ const (
 type NetworkInterface struct {
 	Name        string
 	Description string
-	Address     string
-	Device      string
+	// Address is the primary address: the first IPv4 one, else the first
+	// global IPv6 one, else a link-local one.
+	Address string
+	// Addresses lists every address of the interface in that order.
+	Addresses []string
+	Device    string
 }
 
-type PacketHandler func(payload []byte)
+// AllAddresses returns Addresses, or just Address for an interface built
+// without the full list.
+func (i NetworkInterface) AllAddresses() []string {
+	if len(i.Addresses) == 0 && i.Address != "" {
+		return []string{i.Address}
+	}
+	return i.Addresses
+}
+
+// HasAddress reports whether ip, IPv4 or IPv6, is one of the interface's.
+func (i NetworkInterface) HasAddress(ip string) bool {
+	want, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	for _, a := range i.AllAddresses() {
+		if got, err := netip.ParseAddr(a); err == nil && got.Unmap() == want.Unmap() {
+			return true
+		}
+	}
+	return false
+}
+
+// addrRank orders the addresses of an interface: IPv4, then global IPv6,
+// then the rest (link-local, loopback).
+func addrRank(a netip.Addr) int {
+	switch {
+	case !a.IsValid():
+		return 3
+	case a.Is4():
+		return 0
+	case a.IsGlobalUnicast():
+		return 1
+	}
+	return 2
+}
+
+// interfaceAddrs lists the usable addresses pcap reports, best first.
+func interfaceAddrs(in []pcap.InterfaceAddress) []netip.Addr {
+	var out []netip.Addr
+	for _, ia := range in {
+		a, ok := netip.AddrFromSlice(ia.IP)
+		if !ok {
+			continue
+		}
+		a = a.Unmap()
+		if a.IsUnspecified() || slices.Contains(out, a) {
+			continue
+		}
+		out = append(out, a)
+	}
+	slices.SortStableFunc(out, func(a, b netip.Addr) int { return addrRank(a) - addrRank(b) })
+	return out
+}
+
+// PacketHandler receives each UDP payload with its capture time and flow.
+type PacketHandler func(payload []byte, meta photon.PacketMeta)
 
 type Capturer struct {
 	handle    *pcap.Handle
@@ -45,12 +108,46 @@ type Capturer struct {
 	cancel    context.CancelFunc
 	closeOnce sync.Once
 
-	bytesReceived uint64
+	bytesReceived   uint64
+	packetsReceived uint64
 
 	recordMu          sync.Mutex
 	recordFile        *os.File
 	recordWriter      *pcapgo.Writer
 	recordWriteErrors uint64
+	// recordDir, recordBytes and recordMaxBytes drive size-based rotation:
+	// past recordMaxBytes the file is closed and a new one started in
+	// recordDir. Zero disables rotation.
+	recordDir      string
+	recordBytes    int64
+	recordMaxBytes int64
+	// ng is the Manager's shared pcapng recording, written to as interface
+	// ngID, when the recording format is FormatPcapng.
+	ng   *ngRecording
+	ngID int
+	// ring keeps the last packets in memory when set; see SetRingBuffer.
+	ring *ringBuffer
+
+	// defrag puts fragmented IPv4 datagrams back together.
+	defrag defragmenter
+
+	// ports are the game server ports; packets on none of them are recorded
+	// but not handed to onPacket. Empty means AlbionPort.
+	ports []uint16
+
+	// endpoints are the game servers this interface talked to.
+	endpointsMu sync.Mutex
+	endpoints   map[netip.AddrPort]*endpointSeen
+}
+
+// maxEndpoints bounds the game servers remembered per interface; the one
+// heard from least recently goes first.
+const maxEndpoints = 16
+
+type endpointSeen struct {
+	addr     netip.AddrPort
+	packets  uint64
+	lastSeen time.Time
 }
 
 // captureFactory is overridable in tests; restore via t.Cleanup.
@@ -59,13 +156,12 @@ var captureFactory = openLiveCapture
 // findAllDevs is overridable in tests; restore via t.Cleanup.
 var findAllDevs = pcap.FindAllDevs
 
-func openLiveCapture(ctx context.Context, iface NetworkInterface) (*Capturer, error) {
+func openLiveCapture(ctx context.Context, iface NetworkInterface, filter FilterConfig) (*Capturer, error) {
 	handle, err := pcap.OpenLive(iface.Device, SnapLen, Promiscuous, ReadTimeout)
 	if err != nil {
 		return nil, fmt.Errorf("open device %q: %w", iface.Device, err)
 	}
-	filter := fmt.Sprintf("udp and (dst port %d or src port %d)", AlbionPort, AlbionPort)
-	if err := handle.SetBPFFilter(filter); err != nil {
+	if err := handle.SetBPFFilter(filter.Expression()); err != nil {
 		handle.Close()
 		return nil, fmt.Errorf("set BPF filter on %q: %w", iface.Device, err)
 	}
@@ -74,6 +170,7 @@ func openLiveCapture(ctx context.Context, iface NetworkInterface) (*Capturer, er
 	return &Capturer{
 		handle: handle,
 		iface:  iface,
+		ports:  filter.PortsOrDefault(),
 		ctx:    cctx,
 		cancel: cancel,
 	}, nil
@@ -119,6 +216,18 @@ func (c *Capturer) Iface() NetworkInterface { return c.iface }
 
 func (c *Capturer) BytesReceived() uint64 { return atomic.LoadUint64(&c.bytesReceived) }
 
+func (c *Capturer) PacketsReceived() uint64 { return atomic.LoadUint64(&c.packetsReceived) }
+
+// FragmentsReassembled counts the IPv4 datagrams put back together from
+// fragments.
+func (c *Capturer) FragmentsReassembled() uint64 { return atomic.LoadUint64(&c.defrag.reassembled) }
+
+// FragmentsExpired counts the fragmented datagrams given up on: incomplete
+// after a few seconds, or dropped to bound the memory they hold. Orphan
+// fragments of other traffic, whose first fragment never came, are not
+// counted.
+func (c *Capturer) FragmentsExpired() uint64 { return atomic.LoadUint64(&c.defrag.expired) }
+
 func (c *Capturer) Stats() (*pcap.Stats, error) {
 	if c.handle == nil {
 		return nil, nil
@@ -142,16 +251,28 @@ func (c *Capturer) StartRecording(dir string) error {
 	c.recordMu.Lock()
 	defer c.recordMu.Unlock()
 
-	if c.recordWriter != nil {
+	if c.recordWriter != nil || c.ng != nil {
 		return errors.New("recording already in progress")
 	}
 	if err := os.MkdirAll(dir, 0o755); err != nil {
 		return fmt.Errorf("create recording dir: %w", err)
 	}
+	c.recordDir = dir
+	return c.openRecordingLocked()
+}
 
+// openRecordingLocked creates the next recording file in recordDir. A name
+// already taken in the same second gets a _<n> suffix.
+func (c *Capturer) openRecordingLocked() error {
 	ts := time.Now().Format("2006-01-02T15-04-05")
 	iface := sanitizeIfaceName(c.iface.Name)
-	path := filepath.Join(dir, fmt.Sprintf("capture_%s_%s.pcap", ts, iface))
+	path := filepath.Join(c.recordDir, fmt.Sprintf("capture_%s_%s.pcap", ts, iface))
+	for n := 2; ; n++ {
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			break
+		}
+		path = filepath.Join(c.recordDir, fmt.Sprintf("capture_%s_%s_%d.pcap", ts, iface, n))
+	}
 
 	f, err := os.Create(path)
 	if err != nil {
@@ -159,27 +280,68 @@ func (c *Capturer) StartRecording(dir string) error {
 	}
 
 	w := pcapgo.NewWriter(f)
-	var linkType layers.LinkType
-	if c.handle != nil {
-		linkType = c.handle.LinkType()
-	} else {
-		linkType = layers.LinkTypeEthernet
-	}
-	if err := w.WriteFileHeader(SnapLen, linkType); err != nil {
+	if err := w.WriteFileHeader(SnapLen, c.linkType()); err != nil {
 		f.Close()
 		return fmt.Errorf("write pcap header: %w", err)
 	}
 
 	c.recordFile = f
 	c.recordWriter = w
+	c.recordBytes = 24 // file header
 	return nil
 }
 
-// StopRecording flushes and closes the current recording. Returns nil if not recording.
+// linkType is the handle's link type; Ethernet without one.
+func (c *Capturer) linkType() layers.LinkType {
+	if c.handle != nil {
+		return c.handle.LinkType()
+	}
+	return layers.LinkTypeEthernet
+}
+
+// recordInto starts writing all packets to the shared pcapng recording ng,
+// under an interface block of their own.
+func (c *Capturer) recordInto(ng *ngRecording) error {
+	c.recordMu.Lock()
+	defer c.recordMu.Unlock()
+
+	if c.recordWriter != nil || c.ng != nil {
+		return errors.New("recording already in progress")
+	}
+	id, err := ng.addInterface(c.iface, c.linkType())
+	if err != nil {
+		return err
+	}
+	c.ng, c.ngID = ng, id
+	return nil
+}
+
+// SetRecordingMaxBytes sets the size past which the recording rotates to a
+// new file. Zero disables rotation.
+func (c *Capturer) SetRecordingMaxBytes(n int64) {
+	c.recordMu.Lock()
+	defer c.recordMu.Unlock()
+	c.recordMaxBytes = n
+}
+
+// RecordingPath is the capturer's own pcap file, or "" when not recording
+// one. A pcapng recording belongs to the Manager.
+func (c *Capturer) RecordingPath() string {
+	c.recordMu.Lock()
+	defer c.recordMu.Unlock()
+	if c.recordFile == nil {
+		return ""
+	}
+	return c.recordFile.Name()
+}
+
+// StopRecording flushes and closes the current recording, or leaves the
+// shared pcapng one. Returns nil if not recording.
 func (c *Capturer) StopRecording() error {
 	c.recordMu.Lock()
 	defer c.recordMu.Unlock()
 
+	c.ng = nil
 	if c.recordWriter == nil {
 		return nil
 	}
@@ -193,33 +355,133 @@ func (c *Capturer) StopRecording() error {
 func (c *Capturer) IsRecording() bool {
 	c.recordMu.Lock()
 	defer c.recordMu.Unlock()
-	return c.recordWriter != nil
+	return c.recordWriter != nil || c.ng != nil
+}
+
+// rotateRecordingLocked closes the full recording and opens the next one.
+// On failure recording stops, as the next file could not be created.
+func (c *Capturer) rotateRecordingLocked() {
+	if err := c.recordFile.Close(); err != nil {
+		logger.PrintWarn("PKT", "pcap recording could not close %s: %v", c.recordFile.Name(), err)
+	}
+	c.recordFile, c.recordWriter = nil, nil
+	if err := c.openRecordingLocked(); err != nil {
+		logger.PrintError("PKT", "pcap recording stopped on %s, rotation failed: %v", c.iface.Name, err)
+	}
+}
+
+// recordWriteFailed counts a failed recording write, warning every 100.
+func (c *Capturer) recordWriteFailed(err error) {
+	n := atomic.AddUint64(&c.recordWriteErrors, 1)
+	if n%100 == 1 {
+		logger.PrintWarn("PKT", "pcap recorder write error: %v", err)
+	}
 }
 
 func (c *Capturer) processPacket(p gopacket.Packet) {
 	c.recordMu.Lock()
 	if c.recordWriter != nil {
 		if err := c.recordWriter.WritePacket(p.Metadata().CaptureInfo, p.Data()); err != nil {
-			n := atomic.AddUint64(&c.recordWriteErrors, 1)
-			if n%100 == 1 {
-				logger.PrintWarn("PKT", "pcap recorder write error: %v", err)
-			}
+			c.recordWriteFailed(err)
+		}
+		c.recordBytes += 16 + int64(len(p.Data())) // record header + data
+		if c.recordMaxBytes > 0 && c.recordBytes >= c.recordMaxBytes {
+			c.rotateRecordingLocked()
+		}
+	}
+	if c.ng != nil {
+		if err := c.ng.writePacket(c.ngID, p.Metadata().CaptureInfo, p.Data()); err != nil {
+			c.recordWriteFailed(err)
 		}
 	}
+	if c.ring != nil {
+		c.ring.add(p.Metadata().CaptureInfo, p.Data())
+	}
 	c.recordMu.Unlock()
 
-	udpLayer := p.Layer(layers.LayerTypeUDP)
-	if udpLayer == nil {
-		return
+	udp, _ := p.Layer(layers.LayerTypeUDP).(*layers.UDP)
+	if ip, _ := p.Layer(layers.LayerTypeIPv4).(*layers.IPv4); ip != nil && isFragment(ip) {
+		// Only the first fragment carries the UDP header; gopacket decodes
+		// none of them as UDP.
+		data, complete := c.defrag.add(ip, p.Metadata().Timestamp)
+		if !complete || ip.Protocol != layers.IPProtocolUDP {
+			return
+		}
+		udp = &layers.UDP{}
+		if err := udp.DecodeFromBytes(data, gopacket.NilDecodeFeedback); err != nil {
+			return
+		}
 	}
-	udp, ok := udpLayer.(*layers.UDP)
-	if !ok || len(udp.Payload) == 0 || c.onPacket == nil {
+	if udp == nil || len(udp.Payload) == 0 || c.onPacket == nil {
 		return
 	}
+	ports := FilterConfig{Ports: c.ports}.PortsOrDefault()
+	if !slices.Contains(ports, uint16(udp.SrcPort)) && !slices.Contains(ports, uint16(udp.DstPort)) {
+		return // caught by the extra BPF expression
+	}
 	atomic.AddUint64(&c.bytesReceived, uint64(len(udp.Payload)))
-	c.onPacket(udp.Payload)
+	atomic.AddUint64(&c.packetsReceived, 1)
+	meta := packetMeta(p, udp, ports)
+	if meta.Server.IsValid() {
+		c.sawEndpoint(meta.Server, meta.Time)
+	}
+	c.onPacket(udp.Payload, meta)
 }
 
+func (c *Capturer) sawEndpoint(addr netip.AddrPort, at time.Time) {
+	c.endpointsMu.Lock()
+	defer c.endpointsMu.Unlock()
+	e := c.endpoints[addr]
+	if e == nil {
+		if c.endpoints == nil {
+			c.endpoints = make(map[netip.AddrPort]*endpointSeen)
+		}
+		if len(c.endpoints) >= maxEndpoints {
+			var oldest *endpointSeen
+			for _, o := range c.endpoints {
+				if oldest == nil || o.lastSeen.Before(oldest.lastSeen) {
+					oldest = o
+				}
+			}
+			delete(c.endpoints, oldest.addr)
+		}
+		e = &endpointSeen{addr: addr}
+		c.endpoints[addr] = e
+	}
+	e.packets++
+	e.lastSeen = at
+}
+
+// gameEndpoints lists the game servers seen, most recent first.
+func (c *Capturer) gameEndpoints() []endpointSeen {
+	c.endpointsMu.Lock()
+	out := make([]endpointSeen, 0, len(c.endpoints))
+	for _, e := range c.endpoints {
+		out = append(out, *e)
+	}
+	c.endpointsMu.Unlock()
+	slices.SortFunc(out, func(a, b endpointSeen) int { return b.lastSeen.Compare(a.lastSeen) })
+	return out
+}
+
+// packetMeta reads the capture time and the UDP endpoints of p; the end on
+// one of ports is the game server.
+func packetMeta(p gopacket.Packet, udp *layers.UDP, ports []uint16) photon.PacketMeta {
+	var src, dst netip.Addr
+	if nl := p.NetworkLayer(); nl != nil {
+		src, _ = netip.AddrFromSlice(nl.NetworkFlow().Src().Raw())
+		dst, _ = netip.AddrFromSlice(nl.NetworkFlow().Dst().Raw())
+	}
+	return photon.FlowMeta(p.Metadata().Timestamp,
+		netip.AddrPortFrom(src.Unmap(), uint16(udp.SrcPort)),
+		netip.AddrPortFrom(dst.Unmap(), uint16(udp.DstPort)),
+		ports...)
+}
+
+// EnumerateInterfaces lists the capture devices that have an address the
+// game can use. A device whose only addresses are link-local (fe80::/10,
+// 169.254.0.0/16), such as an idle adapter or a disconnected tunnel, is left
+// out.
 func EnumerateInterfaces() ([]NetworkInterface, error) {
 	devs, err := findAllDevs()
 	if err != nil {
@@ -227,19 +489,21 @@ func EnumerateInterfaces() ([]NetworkInterface, error) {
 	}
 	var out []NetworkInterface
 	for _, d := range devs {
-		for _, addr := range d.Addresses {
-			ip4 := addr.IP.To4()
-			if ip4 == nil {
-				continue
-			}
-			out = append(out, NetworkInterface{
-				Name:        d.Name,
-				Description: d.Description,
-				Address:     ip4.String(),
-				Device:      d.Name,
-			})
-			break
+		addrs := interfaceAddrs(d.Addresses)
+		if !slices.ContainsFunc(addrs, func(a netip.Addr) bool { return !a.IsLinkLocalUnicast() }) {
+			continue
+		}
+		all := make([]string, len(addrs))
+		for i, a := range addrs {
+			all[i] = a.String()
 		}
+		out = append(out, NetworkInterface{
+			Name:        d.Name,
+			Description: d.Description,
+			Address:     all[0],
+			Addresses:   all,
+			Device:      d.Name,
+		})
 	}
 	return out, nil
 }
@@ -250,7 +514,7 @@ func ResolveByIP(ip string) (PersistedInterface, error) {
 		return PersistedInterface{}, err
 	}
 	for _, i := range ifaces {
-		if i.Address == ip {
+		if i.HasAddress(ip) {
 			return PersistedInterface{Name: i.Name, Description: i.Description}, nil
 		}
 	}
@@ -3,6 +3,7 @@ package capture
 import (
 	"bytes"
 	"context"
+	"net/netip"
 	"os"
 	"path/filepath"
 	"testing"
@@ -281,6 +282,13 @@ func TestClose_StopsRecording(t *testing.T) {
 // on UDP src/dst port AlbionPort so processPacket's BPF-free path picks it up.
 func buildUDPPacket(t *testing.T, payload []byte) gopacket.Packet {
 	t.Helper()
+	loop := netip.AddrPortFrom(netip.MustParseAddr("127.0.0.1"), AlbionPort)
+	return buildFlowPacket(t, loop, loop, payload)
+}
+
+// buildFlowPacket is buildUDPPacket from src to dst, IPv4 only.
+func buildFlowPacket(t *testing.T, src, dst netip.AddrPort, payload []byte) gopacket.Packet {
+	t.Helper()
 
 	buf := gopacket.NewSerializeBuffer()
 	opts := gopacket.SerializeOptions{FixLengths: true, ComputeChecksums: false}
@@ -294,12 +302,12 @@ func buildUDPPacket(t *testing.T, payload []byte) gopacket.Packet {
 		Version:  4,
 		TTL:      64,
 		Protocol: layers.IPProtocolUDP,
-		SrcIP:    []byte{127, 0, 0, 1},
-		DstIP:    []byte{127, 0, 0, 1},
+		SrcIP:    src.Addr().AsSlice(),
+		DstIP:    dst.Addr().AsSlice(),
 	}
 	udp := &layers.UDP{
-		SrcPort: AlbionPort,
-		DstPort: AlbionPort,
+		SrcPort: layers.UDPPort(src.Port()),
+		DstPort: layers.UDPPort(dst.Port()),
 	}
 	udp.SetNetworkLayerForChecksum(ip)
 
@@ -316,3 +324,36 @@ func buildUDPPacket(t *testing.T, payload []byte) gopacket.Packet {
 	pkt.Metadata().CaptureInfo = ci
 	return pkt
 }
+
+// synthetic: packets are constructed in-process; no live Albion traffic needed.
+func TestProcessPacket_RotatesPastMaxBytes(t *testing.T) {
+	// No handle: recordings default to Ethernet, which buildUDPPacket emits.
+	c := &Capturer{}
+
+	dir := t.TempDir()
+	if err := c.StartRecording(dir); err != nil {
+		t.Fatalf("StartRecording: %v", err)
+	}
+	// Each packet is well over 100 bytes with its headers, so every one
+	// fills a file and the next goes to a new one.
+	c.SetRecordingMaxBytes(100)
+	for _, pl := range []string{"one", "two", "three"} {
+		c.processPacket(buildUDPPacket(t, bytes.Repeat([]byte(pl), 30)))
+	}
+	active := c.RecordingPath()
+	if err := c.StopRecording(); err != nil {
+		t.Fatalf("StopRecording: %v", err)
+	}
+
+	matches, err := filepath.Glob(filepath.Join(dir, "capture_*.pcap"))
+	if err != nil {
+		t.Fatalf("Glob: %v", err)
+	}
+	// Three full files plus the fresh one opened after the last packet.
+	if len(matches) != 4 {
+		t.Fatalf("want 4 capture files, got %d: %v", len(matches), matches)
+	}
+	if info, err := os.Stat(active); err != nil || info.Size() != 24 {
+		t.Errorf("the active file should only hold the header, got %v err=%v", info, err)
+	}
+}
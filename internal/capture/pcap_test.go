@@ -3,6 +3,7 @@ package capture
 import (
 	"errors"
 	"net"
+	"slices"
 	"testing"
 
 	"github.com/google/gopacket/pcap"
@@ -15,7 +16,7 @@ func withStubFindAllDevs(t *testing.T, devs []pcap.Interface, err error) {
 	t.Cleanup(func() { findAllDevs = prev })
 }
 
-func TestEnumerateInterfacesPicksFirstIPv4(t *testing.T) {
+func TestEnumerateInterfacesPrefersIPv4(t *testing.T) {
 	withStubFindAllDevs(t, []pcap.Interface{
 		{
 			Name:        `\Device\NPF_{1}`,
@@ -42,17 +43,61 @@ func TestEnumerateInterfacesPicksFirstIPv4(t *testing.T) {
 	if err != nil {
 		t.Fatalf("EnumerateInterfaces: %v", err)
 	}
-	if len(out) != 2 {
-		t.Fatalf("got %d interfaces, want 2 (IPv6-only skipped)", len(out))
+	if len(out) != 3 {
+		t.Fatalf("got %d interfaces, want 3 (IPv6-only kept)", len(out))
 	}
 	if out[0].Address != "192.168.1.10" {
 		t.Errorf("first iface addr = %q, want %q (first IPv4 wins)", out[0].Address, "192.168.1.10")
 	}
+	if want := []string{"192.168.1.10", "192.168.1.99", "fe80::1"}; !slices.Equal(out[0].Addresses, want) {
+		t.Errorf("first iface addresses = %v, want %v", out[0].Addresses, want)
+	}
 	if out[0].Device != out[0].Name {
 		t.Errorf("Device=%q Name=%q, want equal", out[0].Device, out[0].Name)
 	}
-	if out[1].Address != "10.0.0.5" {
-		t.Errorf("second iface addr = %q, want %q", out[1].Address, "10.0.0.5")
+	if out[1].Address != "::1" {
+		t.Errorf("second iface addr = %q, want %q", out[1].Address, "::1")
+	}
+	if out[2].Address != "10.0.0.5" {
+		t.Errorf("third iface addr = %q, want %q", out[2].Address, "10.0.0.5")
+	}
+}
+
+func TestEnumerateInterfacesIPv6Tunnel(t *testing.T) {
+	withStubFindAllDevs(t, []pcap.Interface{
+		{
+			Name:        `\Device\NPF_{4}`,
+			Description: "WireGuard Tunnel",
+			Addresses: []pcap.InterfaceAddress{
+				{IP: net.ParseIP("fe80::5")},
+				{IP: net.ParseIP("fd00:10::2")},
+				{IP: net.ParseIP("fd00:10::2")},
+				{IP: net.IPv6unspecified},
+			},
+		},
+		{Name: "empty", Description: "No addresses"},
+		{
+			Name:        "idle",
+			Description: "Link-local only",
+			Addresses: []pcap.InterfaceAddress{
+				{IP: net.ParseIP("fe80::9")},
+				{IP: net.ParseIP("169.254.10.20")},
+			},
+		},
+	}, nil)
+
+	out, err := EnumerateInterfaces()
+	if err != nil {
+		t.Fatalf("EnumerateInterfaces: %v", err)
+	}
+	if len(out) != 1 {
+		t.Fatalf("got %d interfaces, want 1 (link-local only skipped)", len(out))
+	}
+	if out[0].Address != "fd00:10::2" || !slices.Equal(out[0].Addresses, []string{"fd00:10::2", "fe80::5"}) {
+		t.Errorf("got %q %v, want the ULA address first, once", out[0].Address, out[0].Addresses)
+	}
+	if !out[0].HasAddress("fe80::5") || out[0].HasAddress("10.0.0.5") {
+		t.Error("HasAddress does not match the interface's addresses")
 	}
 }
 
@@ -0,0 +1,263 @@
+package capture
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+
+	"github.com/nospy/albion-openradar/internal/logger"
+)
+
+// pcapng block types and option codes, from the pcapng specification. pcapgo
+// has a writer, but it cannot attach comments to packets.
+const (
+	ngBlockSection       = 0x0A0D0D0A
+	ngBlockInterface     = 0x00000001
+	ngBlockEnhancedPkt   = 0x00000006
+	ngByteOrderMagic     = 0x1A2B3C4D
+	ngOptEnd             = 0
+	ngOptComment         = 1
+	ngOptSHBOS           = 3
+	ngOptSHBApplication  = 4
+	ngOptIfName          = 2
+	ngOptIfDescription   = 3
+	ngOptIfTSResol       = 9
+	ngTimestampNanosBase = 9 // if_tsresol: 10^-9 s
+)
+
+// ngInterface is one capturer's Interface Description Block.
+type ngInterface struct {
+	name        string
+	description string
+	linkType    layers.LinkType
+}
+
+// ngRecording is the single pcapng file every capturer writes to when the
+// recording format is FormatPcapng. Each capturer gets its own interface
+// block; comments queued by annotate ride on the next packet written.
+type ngRecording struct {
+	mu          sync.Mutex
+	dir         string
+	application string
+	maxBytes    int64
+	f           *os.File
+	bytes       int64
+	ifaces      []ngInterface
+	comments    []string
+}
+
+// newNgRecording creates dir if needed and opens the first file. application
+// names the writer in the section header, e.g. "OpenRadar v1.2.3".
+func newNgRecording(dir, application string, maxBytes int64) (*ngRecording, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create recording dir: %w", err)
+	}
+	r := &ngRecording{dir: dir, application: application, maxBytes: maxBytes}
+	if err := r.openLocked(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// openLocked creates the next capture_<TS>_all.pcapng and writes the section
+// header and every known interface into it, so that after a rotation the
+// interface ids stay the same.
+func (r *ngRecording) openLocked() error {
+	ts := time.Now().Format("2006-01-02T15-04-05")
+	path := filepath.Join(r.dir, fmt.Sprintf("capture_%s_all.pcapng", ts))
+	for n := 2; ; n++ {
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			break
+		}
+		path = filepath.Join(r.dir, fmt.Sprintf("capture_%s_all_%d.pcapng", ts, n))
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create recording file: %w", err)
+	}
+	r.f, r.bytes = f, 0
+	if err := r.writeLocked(r.sectionHeader()); err != nil {
+		r.f = nil
+		f.Close()
+		return fmt.Errorf("write pcapng header: %w", err)
+	}
+	for _, i := range r.ifaces {
+		if err := r.writeLocked(interfaceBlock(i)); err != nil {
+			r.f = nil
+			f.Close()
+			return fmt.Errorf("write pcapng interface: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *ngRecording) writeLocked(block []byte) error {
+	n, err := r.f.Write(block)
+	r.bytes += int64(n)
+	return err
+}
+
+// addInterface returns the interface id of iface, writing its block the
+// first time it is seen.
+func (r *ngRecording) addInterface(iface NetworkInterface, linkType layers.LinkType) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, i := range r.ifaces {
+		if i.name == iface.Name {
+			return id, nil
+		}
+	}
+	i := ngInterface{name: iface.Name, description: iface.Description, linkType: linkType}
+	if r.f != nil {
+		if err := r.writeLocked(interfaceBlock(i)); err != nil {
+			return 0, fmt.Errorf("write pcapng interface: %w", err)
+		}
+	}
+	r.ifaces = append(r.ifaces, i)
+	return len(r.ifaces) - 1, nil
+}
+
+// writePacket appends a packet of interface id, with the pending comments.
+// It is a no-op once the recording is closed.
+func (r *ngRecording) writePacket(id int, ci gopacket.CaptureInfo, data []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.f == nil {
+		return nil
+	}
+	err := r.writeLocked(packetBlock(id, ci, data, r.comments))
+	r.comments = nil
+	if r.maxBytes > 0 && r.bytes >= r.maxBytes {
+		r.rotateLocked()
+	}
+	return err
+}
+
+// rotateLocked closes the full file and opens the next one. On failure the
+// recording stops, as the next file could not be created.
+func (r *ngRecording) rotateLocked() {
+	if err := r.f.Close(); err != nil {
+		logger.PrintWarn("PKT", "pcapng recording could not close %s: %v", r.f.Name(), err)
+	}
+	r.f = nil
+	if err := r.openLocked(); err != nil {
+		logger.PrintError("PKT", "pcapng recording stopped, rotation failed: %v", err)
+	}
+}
+
+// annotate queues comment for the next packet.
+func (r *ngRecording) annotate(comment string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.f != nil {
+		r.comments = append(r.comments, comment)
+	}
+}
+
+func (r *ngRecording) setMaxBytes(n int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.maxBytes = n
+}
+
+// path is the file being written, or "" once closed.
+func (r *ngRecording) path() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.f == nil {
+		return ""
+	}
+	return r.f.Name()
+}
+
+func (r *ngRecording) close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.f == nil {
+		return nil
+	}
+	err := r.f.Close()
+	r.f = nil
+	return err
+}
+
+func (r *ngRecording) sectionHeader() []byte {
+	body := binary.LittleEndian.AppendUint32(nil, ngByteOrderMagic)
+	body = binary.LittleEndian.AppendUint16(body, 1) // major version
+	body = binary.LittleEndian.AppendUint16(body, 0) // minor version
+	body = binary.LittleEndian.AppendUint64(body, ^uint64(0))
+	opts := appendNgOption(nil, ngOptSHBOS, []byte(runtime.GOOS+"/"+runtime.GOARCH))
+	if r.application != "" {
+		opts = appendNgOption(opts, ngOptSHBApplication, []byte(r.application))
+		opts = appendNgOption(opts, ngOptComment, []byte("Recorded by "+r.application))
+	}
+	return ngBlock(ngBlockSection, append(body, endNgOptions(opts)...))
+}
+
+func interfaceBlock(i ngInterface) []byte {
+	body := binary.LittleEndian.AppendUint16(nil, uint16(i.linkType))
+	body = binary.LittleEndian.AppendUint16(body, 0) // reserved
+	body = binary.LittleEndian.AppendUint32(body, SnapLen)
+	opts := appendNgOption(nil, ngOptIfName, []byte(i.name))
+	if i.description != "" {
+		opts = appendNgOption(opts, ngOptIfDescription, []byte(i.description))
+	}
+	opts = appendNgOption(opts, ngOptIfTSResol, []byte{ngTimestampNanosBase})
+	return ngBlock(ngBlockInterface, append(body, endNgOptions(opts)...))
+}
+
+func packetBlock(id int, ci gopacket.CaptureInfo, data []byte, comments []string) []byte {
+	ts := uint64(ci.Timestamp.UnixNano())
+	length := ci.Length
+	if length < len(data) {
+		length = len(data)
+	}
+	body := binary.LittleEndian.AppendUint32(nil, uint32(id))
+	body = binary.LittleEndian.AppendUint32(body, uint32(ts>>32))
+	body = binary.LittleEndian.AppendUint32(body, uint32(ts))
+	body = binary.LittleEndian.AppendUint32(body, uint32(len(data)))
+	body = binary.LittleEndian.AppendUint32(body, uint32(length))
+	body = appendNgPadded(body, data)
+	var opts []byte
+	for _, c := range comments {
+		opts = appendNgOption(opts, ngOptComment, []byte(c))
+	}
+	return ngBlock(ngBlockEnhancedPkt, append(body, endNgOptions(opts)...))
+}
+
+// ngBlock frames body, already padded to 32 bits, as a block of typ.
+func ngBlock(typ uint32, body []byte) []byte {
+	total := uint32(12 + len(body))
+	b := make([]byte, 0, total)
+	b = binary.LittleEndian.AppendUint32(b, typ)
+	b = binary.LittleEndian.AppendUint32(b, total)
+	b = append(b, body...)
+	return binary.LittleEndian.AppendUint32(b, total)
+}
+
+func appendNgOption(b []byte, code uint16, value []byte) []byte {
+	b = binary.LittleEndian.AppendUint16(b, code)
+	b = binary.LittleEndian.AppendUint16(b, uint16(len(value)))
+	return appendNgPadded(b, value)
+}
+
+// endNgOptions terminates a non-empty option list.
+func endNgOptions(opts []byte) []byte {
+	if len(opts) == 0 {
+		return nil
+	}
+	return append(opts, ngOptEnd, 0, 0, 0)
+}
+
+func appendNgPadded(b, data []byte) []byte {
+	b = append(b, data...)
+	return append(b, make([]byte, (4-len(data)%4)%4)...)
+}
@@ -0,0 +1,156 @@
+package capture
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/google/gopacket/pcapgo"
+
+	"github.com/nospy/albion-openradar/internal/photon"
+)
+
+// synthetic: packets are constructed in-process; no live Albion traffic needed.
+func TestManager_Pcapng_OneFileWithAnInterfaceBlockPerCapturer(t *testing.T) {
+	defer withStubFactory(t, nil)()
+
+	m := NewManager(context.Background())
+	m.OnPacket(func([]byte, photon.PacketMeta) {})
+	m.SetApplication("OpenRadar vtest")
+	if err := m.SetRecordingFormat(FormatPcapng); err != nil {
+		t.Fatalf("SetRecordingFormat: %v", err)
+	}
+	if err := m.Reconfigure([]NetworkInterface{{Name: "alpha", Description: "Wired"}}); err != nil {
+		t.Fatalf("Reconfigure: %v", err)
+	}
+	dir := t.TempDir()
+	if err := m.StartRecording(dir); err != nil {
+		t.Fatalf("StartRecording: %v", err)
+	}
+	// beta joins mid-recording and gets its block then.
+	if err := m.Reconfigure([]NetworkInterface{{Name: "alpha", Description: "Wired"}, {Name: "beta"}}); err != nil {
+		t.Fatalf("Reconfigure: %v", err)
+	}
+
+	files := m.RecordingFiles()
+	if len(files) != 1 || !strings.HasSuffix(files[0], "_all.pcapng") {
+		t.Fatalf("RecordingFiles = %v, want one _all.pcapng", files)
+	}
+
+	m.mu.Lock()
+	alpha, beta := m.active["alpha"].cap, m.active["beta"].cap
+	m.mu.Unlock()
+	alpha.processPacket(buildUDPPacket(t, []byte("from-alpha")))
+	m.Annotate("cluster 4000")
+	beta.processPacket(buildUDPPacket(t, []byte("from-beta")))
+
+	if err := m.StopRecording(); err != nil {
+		t.Fatalf("StopRecording: %v", err)
+	}
+	m.Close(context.Background())
+
+	raw, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"OpenRadar vtest", "cluster 4000", "Wired"} {
+		if !bytes.Contains(raw, []byte(want)) {
+			t.Errorf("file does not carry %q", want)
+		}
+	}
+
+	r, err := pcapgo.NewNgReader(bytes.NewReader(raw), pcapgo.NgReaderOptions{WantMixedLinkType: true})
+	if err != nil {
+		t.Fatalf("NewNgReader: %v", err)
+	}
+	want := map[int]string{0: "from-alpha", 1: "from-beta"}
+	for range want {
+		data, ci, err := r.ReadPacketData()
+		if err != nil {
+			t.Fatalf("ReadPacketData: %v", err)
+		}
+		udp, _ := gopacket.NewPacket(data, layers.LinkTypeEthernet, gopacket.Default).Layer(layers.LayerTypeUDP).(*layers.UDP)
+		if udp == nil || string(udp.Payload) != want[ci.InterfaceIndex] {
+			t.Errorf("interface %d: got %v, want payload %q", ci.InterfaceIndex, udp, want[ci.InterfaceIndex])
+		}
+	}
+	if r.NInterfaces() != 2 {
+		t.Fatalf("%d interfaces, want 2", r.NInterfaces())
+	}
+	for i, name := range []string{"alpha", "beta"} {
+		if intf, _ := r.Interface(i); intf.Name != name {
+			t.Errorf("interface %d is %q, want %q", i, intf.Name, name)
+		}
+	}
+}
+
+func TestManager_SetRecordingFormat_RestartsARunningRecording(t *testing.T) {
+	defer withStubFactory(t, nil)()
+
+	m := NewManager(context.Background())
+	m.OnPacket(func([]byte, photon.PacketMeta) {})
+	if err := m.Reconfigure([]NetworkInterface{{Name: "alpha"}}); err != nil {
+		t.Fatalf("Reconfigure: %v", err)
+	}
+	if err := m.StartRecording(t.TempDir()); err != nil {
+		t.Fatalf("StartRecording: %v", err)
+	}
+	if files := m.RecordingFiles(); len(files) != 1 || !strings.HasSuffix(files[0], "_alpha.pcap") {
+		t.Fatalf("pcap RecordingFiles = %v", files)
+	}
+
+	if err := m.SetRecordingFormat(FormatPcapng); err != nil {
+		t.Fatalf("SetRecordingFormat: %v", err)
+	}
+	if files := m.RecordingFiles(); len(files) != 1 || !strings.HasSuffix(files[0], ".pcapng") {
+		t.Fatalf("pcapng RecordingFiles = %v", files)
+	}
+	if !m.IsRecording() {
+		t.Error("switching format must keep recording")
+	}
+	m.Close(context.Background())
+}
+
+func TestNgRecording_RotationRepeatsTheInterfaceBlocks(t *testing.T) {
+	dir := t.TempDir()
+	ng, err := newNgRecording(dir, "", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := ng.addInterface(NetworkInterface{Name: "eth0"}, layers.LinkTypeEthernet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := ng.path()
+	pkt := buildUDPPacket(t, []byte("payload"))
+	if err := ng.writePacket(id, pkt.Metadata().CaptureInfo, pkt.Data()); err != nil {
+		t.Fatal(err)
+	}
+	second := ng.path()
+	if second == first || second == "" {
+		t.Fatalf("no rotation: %q then %q", first, second)
+	}
+	if err := ng.writePacket(id, pkt.Metadata().CaptureInfo, pkt.Data()); err != nil {
+		t.Fatal(err)
+	}
+	if err := ng.close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	r, err := pcapgo.NewNgReader(f, pcapgo.DefaultNgReaderOptions)
+	if err != nil {
+		t.Fatalf("rotated file unreadable: %v", err)
+	}
+	if _, _, err := r.ReadPacketData(); err != nil {
+		t.Fatalf("rotated file packet: %v", err)
+	}
+}
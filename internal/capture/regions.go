@@ -0,0 +1,104 @@
+package capture
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/netip"
+	"os"
+	"path/filepath"
+)
+
+// regionsFilename, in the app directory, replaces the region table shipped
+// with the app.
+const regionsFilename = "regions.json"
+
+//go:embed regions.json
+var shippedRegions []byte
+
+// RegionTable maps game server addresses to the region they serve. The
+// table is a list of named regions, each with its CIDR ranges:
+//
+//	{"regions": [{"name": "Europe", "ranges": ["193.169.238.0/24"]}]}
+type RegionTable struct {
+	ranges []regionRange
+}
+
+type regionRange struct {
+	prefix netip.Prefix
+	region string
+}
+
+type regionsFile struct {
+	Regions []struct {
+		Name   string   `json:"name"`
+		Ranges []string `json:"ranges"`
+	} `json:"regions"`
+}
+
+// ParseRegionTable reads a table in the regions.json format.
+func ParseRegionTable(data []byte) (*RegionTable, error) {
+	var f regionsFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	t := &RegionTable{}
+	for _, r := range f.Regions {
+		if r.Name == "" {
+			return nil, errors.New("region without a name")
+		}
+		for _, s := range r.Ranges {
+			p, err := netip.ParsePrefix(s)
+			if err != nil {
+				return nil, fmt.Errorf("region %s: %w", r.Name, err)
+			}
+			t.ranges = append(t.ranges, regionRange{prefix: p.Masked(), region: r.Name})
+		}
+	}
+	return t, nil
+}
+
+// ShippedRegionTable is the table embedded in the binary.
+func ShippedRegionTable() *RegionTable {
+	t, err := ParseRegionTable(shippedRegions)
+	if err != nil {
+		panic("capture: shipped regions.json: " + err.Error())
+	}
+	return t
+}
+
+// LoadRegionTable reads regions.json from appDir, or returns the shipped
+// table when there is none. A file that does not parse is an error, along
+// with the shipped table so that the caller can carry on.
+func LoadRegionTable(appDir string) (*RegionTable, error) {
+	path := filepath.Join(appDir, regionsFilename)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ShippedRegionTable(), nil
+	}
+	if err != nil {
+		return ShippedRegionTable(), err
+	}
+	t, err := ParseRegionTable(data)
+	if err != nil {
+		return ShippedRegionTable(), fmt.Errorf("%s: %w", path, err)
+	}
+	return t, nil
+}
+
+// Lookup returns the region of addr, by longest matching range, or "" when
+// no range covers it: an unlisted server, or a proxy such as ExitLag.
+func (t *RegionTable) Lookup(addr netip.Addr) string {
+	if t == nil {
+		return ""
+	}
+	addr = addr.Unmap()
+	region, bits := "", -1
+	for _, r := range t.ranges {
+		if r.prefix.Bits() > bits && r.prefix.Contains(addr) {
+			region, bits = r.region, r.prefix.Bits()
+		}
+	}
+	return region
+}
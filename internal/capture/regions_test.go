@@ -0,0 +1,115 @@
+package capture
+
+import (
+	"context"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nospy/albion-openradar/internal/photon"
+)
+
+func TestRegionTable_LongestRangeWins(t *testing.T) {
+	table, err := ParseRegionTable([]byte(`{"regions": [
+		{"name": "Europe", "ranges": ["193.169.0.0/16"]},
+		{"name": "Europe-Test", "ranges": ["193.169.238.0/24"]}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := map[string]string{
+		"193.169.238.10":        "Europe-Test",
+		"193.169.1.1":           "Europe",
+		"::ffff:193.169.238.10": "Europe-Test",
+		"45.9.1.2":              "",
+		"2001:db8::1":           "",
+	}
+	for addr, want := range cases {
+		if got := table.Lookup(netip.MustParseAddr(addr)); got != want {
+			t.Errorf("Lookup(%s) = %q, want %q", addr, got, want)
+		}
+	}
+	if got := (*RegionTable)(nil).Lookup(netip.MustParseAddr("193.169.1.1")); got != "" {
+		t.Errorf("nil table: got %q", got)
+	}
+}
+
+func TestParseRegionTable_RejectsBadRanges(t *testing.T) {
+	for _, data := range []string{
+		`{"regions": [{"name": "X", "ranges": ["not-a-cidr"]}]}`,
+		`{"regions": [{"ranges": ["10.0.0.0/8"]}]}`,
+		`{`,
+	} {
+		if _, err := ParseRegionTable([]byte(data)); err == nil {
+			t.Errorf("%s: no error", data)
+		}
+	}
+}
+
+func TestLoadRegionTable(t *testing.T) {
+	dir := t.TempDir()
+	table, err := LoadRegionTable(dir)
+	if err != nil || table.Lookup(netip.MustParseAddr("193.169.238.10")) != "Europe" {
+		t.Fatalf("shipped table: err %v", err)
+	}
+
+	path := filepath.Join(dir, regionsFilename)
+	if err := os.WriteFile(path, []byte(`{"regions": [{"name": "Lab", "ranges": ["10.0.0.0/8"]}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	table, err = LoadRegionTable(dir)
+	if err != nil || table.Lookup(netip.MustParseAddr("10.1.2.3")) != "Lab" {
+		t.Fatalf("override: err %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	table, err = LoadRegionTable(dir)
+	if err == nil || table == nil {
+		t.Fatalf("broken file: want an error and the shipped table, got %v, %v", table, err)
+	}
+}
+
+// synthetic: packets are constructed in-process; no live Albion traffic needed.
+func TestManager_State_ListsGameEndpointsWithRegion(t *testing.T) {
+	defer withStubFactory(t, nil)()
+
+	m := NewManager(context.Background())
+	defer m.Close(context.Background())
+	m.OnPacket(func([]byte, photon.PacketMeta) {})
+	m.SetRegions(ShippedRegionTable())
+	if err := m.Reconfigure([]NetworkInterface{{Name: "alpha"}}); err != nil {
+		t.Fatalf("Reconfigure: %v", err)
+	}
+	m.mu.Lock()
+	alpha := m.active["alpha"].cap
+	m.mu.Unlock()
+
+	client := netip.MustParseAddrPort("192.168.1.2:50000")
+	eu := netip.MustParseAddrPort("193.169.238.10:5056")
+	proxy := netip.MustParseAddrPort("45.9.1.2:5056")
+	alpha.processPacket(buildFlowPacket(t, client, eu, []byte("up")))
+	alpha.processPacket(buildFlowPacket(t, eu, client, []byte("down")))
+	alpha.processPacket(buildFlowPacket(t, proxy, client, []byte("via proxy")))
+
+	active := m.State().Active
+	if len(active) != 1 {
+		t.Fatalf("%d active, want 1", len(active))
+	}
+	got := active[0].Endpoints
+	if len(got) != 2 {
+		t.Fatalf("endpoints = %+v, want 2", got)
+	}
+	byAddr := map[string]GameEndpoint{}
+	for _, e := range got {
+		byAddr[e.Address] = e
+	}
+	if e := byAddr[eu.String()]; e.Region != "Europe" || e.Packets != 2 {
+		t.Errorf("Europe endpoint = %+v", e)
+	}
+	if e := byAddr[proxy.String()]; e.Region != "" || e.Packets != 1 {
+		t.Errorf("proxy endpoint = %+v", e)
+	}
+}
@@ -0,0 +1,163 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/google/gopacket/pcapgo"
+)
+
+// ErrRingEmpty is returned by a flush with nothing buffered.
+var ErrRingEmpty = errors.New("packet buffer is empty")
+
+// ErrRingDisabled is returned by a flush while the buffer is turned off.
+var ErrRingDisabled = errors.New("packet buffer is disabled")
+
+// ringPacket is one buffered packet; data is a private copy.
+type ringPacket struct {
+	ci   gopacket.CaptureInfo
+	data []byte
+}
+
+// ringBuffer keeps the most recent packets of one capturer, bounded by the
+// time span from the oldest to the newest and by their total size.
+type ringBuffer struct {
+	mu       sync.Mutex
+	maxAge   time.Duration
+	maxBytes int64
+	packets  []ringPacket
+	bytes    int64
+}
+
+func (r *ringBuffer) add(ci gopacket.CaptureInfo, data []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.packets = append(r.packets, ringPacket{ci: ci, data: append([]byte(nil), data...)})
+	r.bytes += int64(len(data))
+	r.evictLocked(ci.Timestamp)
+}
+
+// evictLocked drops packets older than maxAge before now, then the oldest
+// until the size fits.
+func (r *ringBuffer) evictLocked(now time.Time) {
+	drop := 0
+	for drop < len(r.packets) {
+		p := r.packets[drop]
+		tooOld := r.maxAge > 0 && now.Sub(p.ci.Timestamp) > r.maxAge
+		tooBig := r.maxBytes > 0 && r.bytes > r.maxBytes
+		if !tooOld && !tooBig {
+			break
+		}
+		r.bytes -= int64(len(p.data))
+		drop++
+	}
+	if drop > 0 {
+		clear(r.packets[:drop])
+		r.packets = r.packets[drop:]
+	}
+}
+
+// snapshot copies the buffered packets out, oldest first. The data slices
+// are shared but never written again.
+func (r *ringBuffer) snapshot() []ringPacket {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.evictLocked(time.Now())
+	return append([]ringPacket(nil), r.packets...)
+}
+
+func (r *ringBuffer) stats() (packets int, bytes int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.packets), r.bytes
+}
+
+// SetRingBuffer keeps the last maxAge of packets, up to maxBytes, in memory
+// so they can be saved after the fact with FlushRing. Zero for both turns the
+// buffer off and frees it.
+func (c *Capturer) SetRingBuffer(maxAge time.Duration, maxBytes int64) {
+	c.recordMu.Lock()
+	defer c.recordMu.Unlock()
+	if maxAge <= 0 && maxBytes <= 0 {
+		c.ring = nil
+		return
+	}
+	if c.ring == nil {
+		c.ring = &ringBuffer{}
+	}
+	c.ring.mu.Lock()
+	c.ring.maxAge, c.ring.maxBytes = maxAge, maxBytes
+	c.ring.evictLocked(time.Now())
+	c.ring.mu.Unlock()
+}
+
+// RingStats reports what the buffer holds; zeros when it is off.
+func (c *Capturer) RingStats() (packets int, bytes int64) {
+	c.recordMu.Lock()
+	ring := c.ring
+	c.recordMu.Unlock()
+	if ring == nil {
+		return 0, 0
+	}
+	return ring.stats()
+}
+
+// FlushRing writes the buffered packets to a new
+// capture_<TS>_<iface>_ring.pcap in dir and returns its path. The buffer
+// keeps its content, so a second flush overlaps the first.
+func (c *Capturer) FlushRing(dir string) (string, error) {
+	c.recordMu.Lock()
+	ring := c.ring
+	c.recordMu.Unlock()
+	if ring == nil {
+		return "", ErrRingDisabled
+	}
+	packets := ring.snapshot()
+	if len(packets) == 0 {
+		return "", ErrRingEmpty
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("create recording dir: %w", err)
+	}
+
+	ts := time.Now().Format("2006-01-02T15-04-05")
+	iface := sanitizeIfaceName(c.iface.Name)
+	path := filepath.Join(dir, fmt.Sprintf("capture_%s_%s_ring.pcap", ts, iface))
+	for n := 2; ; n++ {
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			break
+		}
+		path = filepath.Join(dir, fmt.Sprintf("capture_%s_%s_%d_ring.pcap", ts, iface, n))
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("create buffer file: %w", err)
+	}
+	linkType := layers.LinkTypeEthernet
+	if c.handle != nil {
+		linkType = c.handle.LinkType()
+	}
+	w := pcapgo.NewWriter(f)
+	err = w.WriteFileHeader(SnapLen, linkType)
+	for _, p := range packets {
+		if err != nil {
+			break
+		}
+		err = w.WritePacket(p.ci, p.data)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(path)
+		return "", fmt.Errorf("write buffer file: %w", err)
+	}
+	return path, nil
+}
@@ -0,0 +1,104 @@
+package capture
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/pcapgo"
+
+	"github.com/nospy/albion-openradar/internal/photon"
+)
+
+func TestRingBuffer_EvictsByAgeThenBySize(t *testing.T) {
+	r := &ringBuffer{maxAge: 10 * time.Second, maxBytes: 250}
+	base := time.Now()
+	for i := range 5 {
+		r.add(gopacket.CaptureInfo{Timestamp: base.Add(time.Duration(i) * 4 * time.Second)}, make([]byte, 50))
+	}
+	// Packets at 0s..16s: the ones at 0s and 4s are over 10s older than 16s.
+	if n, b := r.stats(); n != 3 || b != 150 {
+		t.Fatalf("after age eviction: %d packets, %d bytes; want 3, 150", n, b)
+	}
+
+	r.add(gopacket.CaptureInfo{Timestamp: base.Add(16 * time.Second)}, make([]byte, 200))
+	if n, b := r.stats(); n != 2 || b != 250 {
+		t.Fatalf("after size eviction: %d packets, %d bytes; want 2, 250", n, b)
+	}
+}
+
+// synthetic: packets are constructed in-process; no live Albion traffic needed.
+func TestFlushRing_WritesTheBufferedPackets(t *testing.T) {
+	c := &Capturer{iface: NetworkInterface{Name: "eth0"}}
+	dir := t.TempDir()
+	if _, err := c.FlushRing(dir); !errors.Is(err, ErrRingDisabled) {
+		t.Fatalf("flush with the buffer off: got %v, want ErrRingDisabled", err)
+	}
+
+	c.SetRingBuffer(time.Minute, 1<<20)
+	if _, err := c.FlushRing(dir); !errors.Is(err, ErrRingEmpty) {
+		t.Fatalf("empty flush: got %v, want ErrRingEmpty", err)
+	}
+	payloads := [][]byte{bytes.Repeat([]byte("a"), 40), bytes.Repeat([]byte("b"), 40)}
+	for _, pl := range payloads {
+		c.processPacket(buildUDPPacket(t, pl))
+	}
+	if n, _ := c.RingStats(); n != 2 {
+		t.Fatalf("buffered %d packets, want 2", n)
+	}
+
+	path, err := c.FlushRing(dir)
+	if err != nil {
+		t.Fatalf("FlushRing: %v", err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open %s: %v", path, err)
+	}
+	defer f.Close()
+	r, err := pcapgo.NewReader(f)
+	if err != nil {
+		t.Fatalf("read header: %v", err)
+	}
+	for i, pl := range payloads {
+		data, _, err := r.ReadPacketData()
+		if err != nil {
+			t.Fatalf("packet %d: %v", i, err)
+		}
+		if !bytes.HasSuffix(data, pl) {
+			t.Errorf("packet %d does not end with its payload", i)
+		}
+	}
+
+	second, err := c.FlushRing(dir)
+	if err != nil || second == path {
+		t.Errorf("a second flush should write a new file, got %q err=%v", second, err)
+	}
+
+	c.SetRingBuffer(0, 0)
+	if n, _ := c.RingStats(); n != 0 {
+		t.Error("turning the buffer off should free it")
+	}
+}
+
+func TestManagerFlushRing_ReportsADisabledBuffer(t *testing.T) {
+	defer withStubFactory(t, nil)()
+	m := NewManager(context.Background())
+	m.OnPacket(func([]byte, photon.PacketMeta) {})
+	if err := m.Reconfigure([]NetworkInterface{{Name: "a"}}); err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close(context.Background())
+
+	if _, err := m.FlushRing(t.TempDir()); !errors.Is(err, ErrRingDisabled) {
+		t.Fatalf("got %v, want ErrRingDisabled", err)
+	}
+	m.SetRingBuffer(time.Minute, 1<<20)
+	if _, err := m.FlushRing(t.TempDir()); !errors.Is(err, ErrRingEmpty) {
+		t.Fatalf("got %v, want ErrRingEmpty once the buffer is on", err)
+	}
+}
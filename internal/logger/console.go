@@ -1,21 +1,61 @@
 package logger
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/fatih/color"
+	"github.com/segmentio/encoding/json"
 )
 
 // LogCallback is a function that receives log messages
 type LogCallback func(level, tag, message string)
 
+// ConsoleFormat selects how logs are written to stdout when no callback is set.
+type ConsoleFormat string
+
+const (
+	// FormatPretty is the colored HH:MM:SS output for an interactive terminal.
+	FormatPretty ConsoleFormat = "pretty"
+	// FormatText writes logfmt lines (time=... level=... tag=... msg=...).
+	FormatText ConsoleFormat = "text"
+	// FormatJSON writes one JSON object per line.
+	FormatJSON ConsoleFormat = "json"
+)
+
+// ParseConsoleFormat accepts "text" or "json", the formats meant for log
+// collectors.
+func ParseConsoleFormat(s string) (ConsoleFormat, error) {
+	switch f := ConsoleFormat(s); f {
+	case FormatText, FormatJSON:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown log format %q (want text or json)", s)
+}
+
+// Field is a key/value pair attached to a structured log line.
+type Field struct {
+	Key   string
+	Value any
+}
+
 var (
 	// logCallback is called for each log message when set
 	logCallback LogCallback
 	callbackMu  sync.RWMutex
 
+	consoleFormat           = FormatPretty
+	consoleOut    io.Writer = os.Stdout
+	consoleMu     sync.Mutex
+
 	// Color printers (used when no callback is set)
 	gray   = color.New(color.FgHiBlack).SprintFunc()
 	cyan   = color.New(color.FgCyan).SprintFunc()
@@ -39,24 +79,78 @@ func ClearLogCallback() {
 	logCallback = nil
 }
 
+// SetConsoleFormat changes how stdout logs are written.
+func SetConsoleFormat(f ConsoleFormat) {
+	consoleMu.Lock()
+	defer consoleMu.Unlock()
+	consoleFormat = f
+}
+
 // timestamp returns the current time formatted as HH:MM:SS
 func timestamp() string {
 	return time.Now().Format("15:04:05")
 }
 
-// log sends a log message to the callback or prints to stdout
-func log(level, tag, msg string, args ...any) {
-	formatted := fmt.Sprintf(msg, args...)
+// log sends a formatted message through the slog pipeline.
+func log(level slog.Level, tag, msg string, args ...any) {
+	if !pipeline.Enabled(context.Background(), level) {
+		return
+	}
+	logFields(level, tag, fmt.Sprintf(msg, args...), nil)
+}
+
+func logFields(level slog.Level, tag, msg string, fields []Field) {
+	r := slog.NewRecord(time.Now(), level, msg, 0)
+	r.AddAttrs(slog.String(TagKey, tag))
+	for _, f := range fields {
+		r.AddAttrs(slog.Any(f.Key, f.Value))
+	}
+	_ = pipeline.Handler().Handle(context.Background(), r)
+}
 
-	callbackMu.RLock()
-	cb := logCallback
-	callbackMu.RUnlock()
+// writeConsole prints an entry on stdout in the configured format.
+func writeConsole(e entry) {
+	consoleMu.Lock()
+	format := consoleFormat
+	consoleMu.Unlock()
 
-	if cb != nil {
-		cb(level, tag, formatted)
+	level := levelName(e.level)
+	if format == FormatPretty {
+		printPretty(level, e.tag, e.line())
 		return
 	}
 
+	now := e.time.UTC().Format(time.RFC3339Nano)
+	var b bytes.Buffer
+	if format == FormatJSON {
+		b.WriteString(`{"time":`)
+		writeJSONValue(&b, now)
+		b.WriteString(`,"level":`)
+		writeJSONValue(&b, strings.ToLower(level))
+		b.WriteString(`,"tag":`)
+		writeJSONValue(&b, e.tag)
+		b.WriteString(`,"msg":`)
+		writeJSONValue(&b, e.msg)
+		for _, f := range e.fields {
+			b.WriteByte(',')
+			writeJSONValue(&b, f.Key)
+			b.WriteByte(':')
+			writeJSONValue(&b, f.Value)
+		}
+		b.WriteString("}\n")
+	} else {
+		fmt.Fprintf(&b, "time=%s level=%s tag=%s msg=%s", now, strings.ToLower(level), e.tag, logfmtValue(e.msg))
+		for _, f := range e.fields {
+			fmt.Fprintf(&b, " %s=%s", f.Key, logfmtValue(fmt.Sprint(f.Value)))
+		}
+		b.WriteByte('\n')
+	}
+	consoleMu.Lock()
+	_, _ = consoleOut.Write(b.Bytes())
+	consoleMu.Unlock()
+}
+
+func printPretty(level, tag, msg string) {
 	// Fallback to stdout with colors
 	ts := gray("[" + timestamp() + "]")
 	var tagStr string
@@ -65,30 +159,54 @@ func log(level, tag, msg string, args ...any) {
 		tagStr = green("[" + tag + "]")
 	case "WARN":
 		tagStr = yellow("[" + tag + "]")
-	case "ERROR":
+	case "ERROR", "CRITICAL":
 		tagStr = red("[" + tag + "]")
 	default:
 		tagStr = cyan("[" + tag + "]")
 	}
-	fmt.Printf("%s %s %s\n", ts, tagStr, formatted)
+	consoleMu.Lock()
+	fmt.Fprintf(consoleOut, "%s %s %s\n", ts, tagStr, msg)
+	consoleMu.Unlock()
+}
+
+func writeJSONValue(b *bytes.Buffer, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		data, _ = json.Marshal(fmt.Sprint(v))
+	}
+	b.Write(data)
+}
+
+// logfmtValue quotes s when it is empty or holds spaces, quotes or '='.
+func logfmtValue(s string) string {
+	if s == "" || strings.ContainsAny(s, " \"=\t\n") {
+		return strconv.Quote(s)
+	}
+	return s
 }
 
 // PrintInfo prints an info message with cyan tag
 func PrintInfo(tag, msg string, args ...any) {
-	log("INFO", tag, msg, args...)
+	log(slog.LevelInfo, tag, msg, args...)
 }
 
 // PrintSuccess prints a success message with green tag
 func PrintSuccess(tag, msg string, args ...any) {
-	log("SUCCESS", tag, msg, args...)
+	log(LevelSuccess, tag, msg, args...)
 }
 
 // PrintWarn prints a warning message with yellow tag
 func PrintWarn(tag, msg string, args ...any) {
-	log("WARN", tag, msg, args...)
+	log(slog.LevelWarn, tag, msg, args...)
 }
 
 // PrintError prints an error message with red tag
 func PrintError(tag, msg string, args ...any) {
-	log("ERROR", tag, msg, args...)
+	log(slog.LevelError, tag, msg, args...)
+}
+
+// PrintFields prints an info message with key/value fields, which the text
+// and JSON formats keep as separate keys for log collectors.
+func PrintFields(tag, msg string, fields ...Field) {
+	logFields(slog.LevelInfo, tag, msg, fields)
 }
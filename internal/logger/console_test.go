@@ -0,0 +1,75 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/segmentio/encoding/json"
+)
+
+func captureConsole(t *testing.T, format ConsoleFormat) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	prevOut, prevFormat := consoleOut, consoleFormat
+	consoleOut = &buf
+	SetConsoleFormat(format)
+	t.Cleanup(func() {
+		consoleOut = prevOut
+		SetConsoleFormat(prevFormat)
+	})
+	return &buf
+}
+
+func TestConsoleJSONLines(t *testing.T) {
+	buf := captureConsole(t, FormatJSON)
+
+	PrintWarn("NET", "Some interfaces failed to open: %s", `"eth0"`)
+	PrintFields("STATS", "Radar stats", Field{Key: "packets", Value: uint64(42)}, Field{Key: "heap_mb", Value: 12.5})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2:\n%s", len(lines), buf.String())
+	}
+	var warn map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &warn); err != nil {
+		t.Fatalf("line 1 is not JSON: %v", err)
+	}
+	if warn["level"] != "warn" || warn["tag"] != "NET" || warn["msg"] != `Some interfaces failed to open: "eth0"` || warn["time"] == "" {
+		t.Errorf("got %v", warn)
+	}
+	var stats map[string]any
+	if err := json.Unmarshal([]byte(lines[1]), &stats); err != nil {
+		t.Fatalf("line 2 is not JSON: %v", err)
+	}
+	if stats["packets"] != float64(42) || stats["heap_mb"] != 12.5 {
+		t.Errorf("fields must be top-level keys, got %v", stats)
+	}
+}
+
+func TestConsoleLogfmt(t *testing.T) {
+	buf := captureConsole(t, FormatText)
+
+	PrintFields("STATS", "Radar stats", Field{Key: "packets", Value: 7}, Field{Key: "iface", Value: "Wi-Fi 2"})
+
+	line := buf.String()
+	if !strings.HasPrefix(line, "time=") || !strings.HasSuffix(line, "\n") {
+		t.Errorf("got %q", line)
+	}
+	for _, want := range []string{` level=info `, ` tag=STATS `, ` msg="Radar stats" `, ` packets=7 `, ` iface="Wi-Fi 2"`} {
+		if !strings.Contains(line, want) {
+			t.Errorf("missing %q in %q", want, line)
+		}
+	}
+}
+
+func TestParseConsoleFormat(t *testing.T) {
+	for _, s := range []string{"text", "json"} {
+		if _, err := ParseConsoleFormat(s); err != nil {
+			t.Errorf("%s: %v", s, err)
+		}
+	}
+	if _, err := ParseConsoleFormat("pretty"); err == nil {
+		t.Error("pretty is the dashboard fallback, not a headless format")
+	}
+}
@@ -3,8 +3,11 @@ package logger
 import (
 	"bufio"
 	"fmt"
+	"log/slog"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -34,6 +37,13 @@ type Logger struct {
 	enabled            bool
 	mu                 sync.Mutex
 
+	// Rotation: past maxFileBytes a file is closed and the next write goes
+	// to <name>_<part>.jsonl. Zero disables rotation.
+	sessionStamp string
+	sessionPart  int
+	debugPart    int
+	maxFileBytes int64
+
 	// Batching
 	serverBuffer []any
 	clientBuffer []any
@@ -41,6 +51,11 @@ type Logger struct {
 	flushTicker  *time.Ticker
 	stopFlush    chan struct{}
 
+	// lineCounts caches the line count of each session file part by path,
+	// until its size or modification time changes.
+	lineCounts   map[string]lineCount
+	lineCountsMu sync.Mutex
+
 	// Stats
 	totalEntries  uint64
 	totalBatches  uint64
@@ -90,8 +105,9 @@ func (l *Logger) PrintSessionInfo() {
 
 func (l *Logger) SetEnabled(enabled bool) {
 	l.mu.Lock()
-	defer l.mu.Unlock()
 	l.enabled = enabled
+	l.mu.Unlock()
+	// Printed unlocked: the message itself goes to the session file.
 	if enabled {
 		PrintSuccess("LOG", "Server Side Logging ENABLED")
 	} else {
@@ -120,19 +136,67 @@ func (l *Logger) initializeDirectories() {
 }
 
 func (l *Logger) createSessionFile() {
-	timestamp := strings.ReplaceAll(time.Now().Format("2006-01-02T15-04-05"), ":", "-")
-	l.currentSessionFile = filepath.Join(
-		l.logsDir,
-		"sessions",
-		fmt.Sprintf("session_%s.jsonl", timestamp),
-	)
-	l.currentDebugFile = filepath.Join(
-		l.logsDir,
-		"debug",
-		fmt.Sprintf("front_%s.jsonl", timestamp),
+	l.sessionStamp = time.Now().Format(sessionStampLayout)
+	l.sessionPart, l.debugPart = 1, 1
+	l.currentSessionFile = l.partFile("sessions", "session", l.sessionPart)
+	l.currentDebugFile = l.partFile("debug", "front", l.debugPart)
+}
+
+// partFile names part n of a session file; the first part has no suffix.
+func (l *Logger) partFile(dir, prefix string, n int) string {
+	name := fmt.Sprintf("%s_%s.jsonl", prefix, l.sessionStamp)
+	if n > 1 {
+		name = fmt.Sprintf("%s_%s_%d.jsonl", prefix, l.sessionStamp, n)
+	}
+	return filepath.Join(l.logsDir, dir, name)
+}
+
+// SetMaxFileSize sets the size past which the session and front files
+// rotate. Zero disables rotation.
+func (l *Logger) SetMaxFileSize(n int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.maxFileBytes = n
+}
+
+// rotateIfFull moves to the next part once the current file is full.
+// Callers hold l.mu.
+func (l *Logger) rotateIfFull() {
+	if l.maxFileBytes <= 0 {
+		return
+	}
+	if info, err := os.Stat(l.currentSessionFile); err == nil && info.Size() >= l.maxFileBytes {
+		l.sessionPart++
+		l.currentSessionFile = l.partFile("sessions", "session", l.sessionPart)
+	}
+	if info, err := os.Stat(l.currentDebugFile); err == nil && info.Size() >= l.maxFileBytes {
+		l.debugPart++
+		l.currentDebugFile = l.partFile("debug", "front", l.debugPart)
+	}
+}
+
+// ActiveFiles lists the files still being written: the current session and
+// front parts, and today's errors file.
+func (l *Logger) ActiveFiles() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return []string{l.currentSessionFile, l.currentDebugFile, l.errorFile()}
+}
+
+// Sweep applies a retention policy to the sessions, debug and errors
+// directories, leaving the active files alone.
+func (l *Logger) Sweep(p Policy) (SweepResult, error) {
+	return Sweep(p, l.ActiveFiles(),
+		filepath.Join(l.logsDir, "sessions"),
+		filepath.Join(l.logsDir, "debug"),
+		filepath.Join(l.logsDir, "errors"),
 	)
 }
 
+func (l *Logger) errorFile() string {
+	return filepath.Join(l.logsDir, "errors", fmt.Sprintf("errors_%s.log", time.Now().Format("2006-01-02")))
+}
+
 // WriteLogs queues client logs for batched writing and mirrors ERROR/CRITICAL synchronously.
 func (l *Logger) WriteLogs(logs []any) {
 	if len(logs) == 0 {
@@ -176,6 +240,7 @@ func (l *Logger) Flush() {
 
 	l.mu.Lock()
 	defer l.mu.Unlock()
+	l.rotateIfFull()
 
 	if len(serverBatch) > 0 {
 		f, err := os.OpenFile(l.currentSessionFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
@@ -242,10 +307,7 @@ func (l *Logger) Log(level, category, event string, data any, context map[string
 
 // writeErrorLine appends one line to the daily errors file.
 func (l *Logger) writeErrorLine(category, event string, data any) {
-	date := time.Now().Format("2006-01-02")
-	errorFile := filepath.Join(l.logsDir, "errors", fmt.Sprintf("errors_%s.log", date))
-
-	f, err := os.OpenFile(errorFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	f, err := os.OpenFile(l.errorFile(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
 	if err != nil {
 		return
 	}
@@ -256,26 +318,49 @@ func (l *Logger) writeErrorLine(category, event string, data any) {
 	f.WriteString(line)
 }
 
+// emit writes a Debug, Info, ... call to l's session file whenever logging
+// is on, whatever the levels, and also shows it on the console or the
+// dashboard when the tag's level lets it through. Map data becomes fields
+// there, other data a "data" field.
+func (l *Logger) emit(level slog.Level, category, event string, data any, extra map[string]any) {
+	if level >= levelFor(category) {
+		e := entry{time: time.Now(), level: level, tag: category, msg: event}
+		switch d := data.(type) {
+		case nil:
+		case map[string]any:
+			for _, k := range slices.Sorted(maps.Keys(d)) {
+				e.fields = append(e.fields, Field{Key: k, Value: d[k]})
+			}
+		default:
+			e.fields = append(e.fields, Field{Key: "data", Value: d})
+		}
+		display(e)
+	}
+	l.Log(levelName(level), category, event, data, extra)
+}
+
+// Error also appends to the daily errors file, whatever the levels.
 func (l *Logger) Error(category, event string, data any, context map[string]any) {
 	l.writeErrorLine("[SERVER] "+category, event, data)
-	l.Log("ERROR", category, event, data, context)
+	l.emit(slog.LevelError, category, event, data, context)
 }
 
 func (l *Logger) Debug(category, event string, data any, context map[string]any) {
-	l.Log("DEBUG", category, event, data, context)
+	l.emit(slog.LevelDebug, category, event, data, context)
 }
 
 func (l *Logger) Info(category, event string, data any, context map[string]any) {
-	l.Log("INFO", category, event, data, context)
+	l.emit(slog.LevelInfo, category, event, data, context)
 }
 
 func (l *Logger) Warn(category, event string, data any, context map[string]any) {
-	l.Log("WARN", category, event, data, context)
+	l.emit(slog.LevelWarn, category, event, data, context)
 }
 
+// Critical also appends to the daily errors file, whatever the levels.
 func (l *Logger) Critical(category, event string, data any, context map[string]any) {
 	l.writeErrorLine("[SERVER] "+category, event, data)
-	l.Log("CRITICAL", category, event, data, context)
+	l.emit(LevelCritical, category, event, data, context)
 }
 
 type LogStats struct {
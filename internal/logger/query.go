@@ -0,0 +1,331 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/segmentio/encoding/json"
+)
+
+// sessionStampLayout is how session ids are written in file names.
+const sessionStampLayout = "2006-01-02T15-04-05"
+
+// sessionFileRe matches session and front files: prefix, id, optional part
+// number, optional .gz from the retention sweep.
+var sessionFileRe = regexp.MustCompile(`^(session|front)_(\d{4}-\d{2}-\d{2}T\d{2}-\d{2}-\d{2})(?:_(\d+))?\.jsonl(\.gz)?$`)
+
+// ErrUnknownSession is returned by Query for a session with no files.
+var ErrUnknownSession = errors.New("unknown session")
+
+// Session is one run of the radar, as found in the logs directory.
+type Session struct {
+	ID      string       `json:"id"`
+	Start   time.Time    `json:"start"`
+	Current bool         `json:"current"`
+	Server  SessionStats `json:"server"`
+	Client  SessionStats `json:"client"`
+}
+
+// Query selects log lines. Zero fields match everything.
+type Query struct {
+	// Session is the session id; empty means the current one.
+	Session string
+	// Source is "server" (session files), "client" (front files) or empty
+	// for both, server first.
+	Source string
+	// Levels keeps these levels (upper case).
+	Levels []string
+	// Category keeps entries whose category contains it, ignoring case.
+	Category string
+	Since    time.Time
+	Until    time.Time
+	// Text keeps lines containing it anywhere, ignoring case.
+	Text string
+	// Limit stops after that many lines.
+	Limit int
+}
+
+// SessionID is the id of the session being written.
+func (l *Logger) SessionID() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.sessionStamp
+}
+
+type sessionPart struct {
+	path string
+	part int
+}
+
+// lineCount is a cached line count, valid while the file keeps its size and
+// modification time.
+type lineCount struct {
+	size    int64
+	modTime time.Time
+	lines   int
+}
+
+// sessionParts lists the files of one kind ("session" or "front") by id,
+// in part order.
+func (l *Logger) sessionParts(kind string) (map[string][]sessionPart, error) {
+	dir := "sessions"
+	if kind == "front" {
+		dir = "debug"
+	}
+	entries, err := os.ReadDir(filepath.Join(l.logsDir, dir))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	out := make(map[string][]sessionPart)
+	for _, e := range entries {
+		m := sessionFileRe.FindStringSubmatch(e.Name())
+		if m == nil || m[1] != kind || e.IsDir() {
+			continue
+		}
+		part := 1
+		if m[3] != "" {
+			part, _ = strconv.Atoi(m[3])
+		}
+		out[m[2]] = append(out[m[2]], sessionPart{path: filepath.Join(l.logsDir, dir, e.Name()), part: part})
+	}
+	for id := range out {
+		slices.SortFunc(out[id], func(a, b sessionPart) int { return a.part - b.part })
+	}
+	return out, nil
+}
+
+// Sessions lists the sessions with files on disk, newest first. A file is
+// read for its line count once per size and modification time, so only the
+// parts being written are read again.
+func (l *Logger) Sessions() ([]Session, error) {
+	l.Flush()
+	server, err := l.sessionParts("session")
+	if err != nil {
+		return nil, err
+	}
+	client, err := l.sessionParts("front")
+	if err != nil {
+		return nil, err
+	}
+	current := l.SessionID()
+
+	ids := make(map[string]bool)
+	for id := range server {
+		ids[id] = true
+	}
+	for id := range client {
+		ids[id] = true
+	}
+	out := make([]Session, 0, len(ids))
+	seen := make(map[string]bool)
+	for id := range ids {
+		start, _ := time.ParseInLocation(sessionStampLayout, id, time.Local)
+		out = append(out, Session{
+			ID:      id,
+			Start:   start,
+			Current: id == current,
+			Server:  l.partsStats(server[id], start, seen),
+			Client:  l.partsStats(client[id], start, seen),
+		})
+	}
+	l.lineCountsMu.Lock()
+	for path := range l.lineCounts {
+		if !seen[path] {
+			delete(l.lineCounts, path)
+		}
+	}
+	l.lineCountsMu.Unlock()
+	slices.SortFunc(out, func(a, b Session) int { return strings.Compare(b.ID, a.ID) })
+	return out, nil
+}
+
+// partsStats sums the parts of one session file, adding their paths to seen.
+// SessionDuration runs from the session start to the last write.
+func (l *Logger) partsStats(parts []sessionPart, start time.Time, seen map[string]bool) SessionStats {
+	var st SessionStats
+	for i, p := range parts {
+		info, err := os.Stat(p.path)
+		if err != nil {
+			continue
+		}
+		seen[p.path] = true
+		if i == 0 {
+			st.SessionFile = filepath.Base(p.path)
+		}
+		st.FileSize += info.Size()
+		if d := int(info.ModTime().Sub(start).Seconds()); d > st.SessionDuration {
+			st.SessionDuration = d
+		}
+		st.LineCount += l.countLines(p.path, info)
+	}
+	return st
+}
+
+// countLines returns the line count of a file, from the cache while it is
+// unchanged. The file is read outside the lock.
+func (l *Logger) countLines(path string, info os.FileInfo) int {
+	l.lineCountsMu.Lock()
+	c, ok := l.lineCounts[path]
+	l.lineCountsMu.Unlock()
+	if ok && c.size == info.Size() && c.modTime.Equal(info.ModTime()) {
+		return c.lines
+	}
+	c = lineCount{size: info.Size(), modTime: info.ModTime()}
+	_ = eachLine(path, func([]byte) error {
+		c.lines++
+		return nil
+	})
+	l.lineCountsMu.Lock()
+	if l.lineCounts == nil {
+		l.lineCounts = make(map[string]lineCount)
+	}
+	l.lineCounts[path] = c
+	l.lineCountsMu.Unlock()
+	return c.lines
+}
+
+// Query streams the matching lines of a session to emit, unchanged, in file
+// order. An error from emit stops the query and is returned.
+func (l *Logger) Query(q Query, emit func(line []byte) error) error {
+	l.Flush()
+	id := q.Session
+	if id == "" {
+		id = l.SessionID()
+	}
+	if _, err := time.Parse(sessionStampLayout, id); err != nil {
+		return fmt.Errorf("%w: %q", ErrUnknownSession, id)
+	}
+
+	var files []sessionPart
+	for _, kind := range []string{"session", "front"} {
+		if (kind == "session" && q.Source == "client") || (kind == "front" && q.Source == "server") {
+			continue
+		}
+		parts, err := l.sessionParts(kind)
+		if err != nil {
+			return err
+		}
+		files = append(files, parts[id]...)
+	}
+	if len(files) == 0 {
+		if id == l.SessionID() {
+			return nil // nothing flushed yet
+		}
+		return fmt.Errorf("%w: %q", ErrUnknownSession, id)
+	}
+
+	match := q.matcher()
+	sent := 0
+	errLimit := errors.New("limit reached")
+	for _, f := range files {
+		err := eachLine(f.path, func(line []byte) error {
+			if !match(line) {
+				return nil
+			}
+			if err := emit(line); err != nil {
+				return err
+			}
+			sent++
+			if q.Limit > 0 && sent >= q.Limit {
+				return errLimit
+			}
+			return nil
+		})
+		if errors.Is(err, errLimit) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matcher builds the line filter. Lines that do not parse only match a
+// query without field filters.
+func (q Query) matcher() func([]byte) bool {
+	text := []byte(strings.ToLower(q.Text))
+	category := strings.ToLower(q.Category)
+	levels := make(map[string]bool, len(q.Levels))
+	for _, lv := range q.Levels {
+		levels[strings.ToUpper(lv)] = true
+	}
+	needFields := len(levels) > 0 || category != "" || !q.Since.IsZero() || !q.Until.IsZero()
+
+	return func(line []byte) bool {
+		if len(text) > 0 && !bytes.Contains(bytes.ToLower(line), text) {
+			return false
+		}
+		if !needFields {
+			return true
+		}
+		var e struct {
+			Timestamp string `json:"timestamp"`
+			Level     string `json:"level"`
+			Category  string `json:"category"`
+		}
+		if err := json.Unmarshal(line, &e); err != nil {
+			return false
+		}
+		if len(levels) > 0 && !levels[strings.ToUpper(e.Level)] {
+			return false
+		}
+		if category != "" && !strings.Contains(strings.ToLower(e.Category), category) {
+			return false
+		}
+		if !q.Since.IsZero() || !q.Until.IsZero() {
+			ts, err := time.Parse(time.RFC3339, e.Timestamp)
+			if err != nil {
+				return false
+			}
+			if (!q.Since.IsZero() && ts.Before(q.Since)) || (!q.Until.IsZero() && ts.After(q.Until)) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// eachLine calls fn on every non-empty line of a JSONL file, gzipped or not.
+func eachLine(path string, fn func([]byte) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			return fmt.Errorf("read %s: %w", path, err)
+		}
+		defer zr.Close()
+		r = zr
+	}
+	br := bufio.NewReaderSize(r, 64<<10)
+	for {
+		line, err := br.ReadBytes('\n')
+		if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+			if ferr := fn(trimmed); ferr != nil {
+				return ferr
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
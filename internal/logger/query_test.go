@@ -0,0 +1,149 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeLines(t *testing.T, path string, lines ...string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func queryLines(t *testing.T, l *Logger, q Query) []string {
+	t.Helper()
+	var out []string
+	if err := l.Query(q, func(line []byte) error {
+		out = append(out, string(line))
+		return nil
+	}); err != nil {
+		t.Fatalf("Query(%+v): %v", q, err)
+	}
+	return out
+}
+
+func TestQueryFiltersAcrossPartsAndSources(t *testing.T) {
+	dir := t.TempDir()
+	l := New(dir, true)
+	defer l.Stop()
+
+	const id = "2026-10-01T20-00-00"
+	writeLines(t, filepath.Join(dir, "sessions", "session_"+id+".jsonl"),
+		`{"timestamp":"2026-10-01T20:00:01Z","level":"INFO","category":"[SERVER] NET","event":"Capturing on eth0"}`,
+		`{"timestamp":"2026-10-01T20:05:00Z","level":"WARN","category":"[SERVER] PKT","event":"Parsing errors: 1"}`,
+	)
+	writeLines(t, filepath.Join(dir, "sessions", "session_"+id+"_2.jsonl"),
+		`{"timestamp":"2026-10-01T21:00:00Z","level":"ERROR","category":"[SERVER] HTTP","event":"Shutdown error"}`,
+	)
+	writeLines(t, filepath.Join(dir, "debug", "front_"+id+".jsonl"),
+		`{"timestamp":"2026-10-01T20:30:00.500Z","level":"WARN","category":"MOBS","event":"unknown_mob"}`,
+	)
+	if _, err := compressFile(filepath.Join(dir, "sessions", "session_"+id+"_2.jsonl")); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := queryLines(t, l, Query{Session: id}); len(got) != 4 || !strings.Contains(got[2], "Shutdown") {
+		t.Errorf("all lines, server parts in order then client: %q", got)
+	}
+	if got := queryLines(t, l, Query{Session: id, Levels: []string{"warn"}}); len(got) != 2 {
+		t.Errorf("level filter: %q", got)
+	}
+	if got := queryLines(t, l, Query{Session: id, Source: "client"}); len(got) != 1 || !strings.Contains(got[0], "unknown_mob") {
+		t.Errorf("source filter: %q", got)
+	}
+	if got := queryLines(t, l, Query{Session: id, Category: "pkt"}); len(got) != 1 {
+		t.Errorf("category filter: %q", got)
+	}
+	since, _ := time.Parse(time.RFC3339, "2026-10-01T20:04:00Z")
+	until, _ := time.Parse(time.RFC3339, "2026-10-01T20:31:00Z")
+	if got := queryLines(t, l, Query{Session: id, Since: since, Until: until}); len(got) != 2 {
+		t.Errorf("time range: %q", got)
+	}
+	if got := queryLines(t, l, Query{Session: id, Text: "ETH0"}); len(got) != 1 {
+		t.Errorf("text search: %q", got)
+	}
+	if got := queryLines(t, l, Query{Session: id, Limit: 1}); len(got) != 1 {
+		t.Errorf("limit: %q", got)
+	}
+	if err := l.Query(Query{Session: "../../etc"}, func([]byte) error { return nil }); err == nil {
+		t.Error("a malformed session id must be rejected")
+	}
+
+	sessions, err := l.Sessions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found *Session
+	for i := range sessions {
+		if sessions[i].ID == id {
+			found = &sessions[i]
+		}
+	}
+	if found == nil || found.Current || found.Server.LineCount != 3 || found.Client.LineCount != 1 ||
+		found.Server.SessionFile != "session_"+id+".jsonl" || found.Server.SessionDuration < 3600 {
+		t.Errorf("session = %+v", found)
+	}
+	if !slices.IsSortedFunc(sessions, func(a, b Session) int { return strings.Compare(b.ID, a.ID) }) {
+		t.Errorf("sessions must be newest first: %+v", sessions)
+	}
+}
+
+func TestSessionsCountsAnUnchangedFileOnce(t *testing.T) {
+	dir := t.TempDir()
+	l := New(dir, true)
+	defer l.Stop()
+
+	const id = "2026-10-01T20-00-00"
+	path := filepath.Join(dir, "sessions", "session_"+id+".jsonl")
+	writeLines(t, path, `{"event":"a"}`, `{"event":"b"}`)
+	stamp := time.Date(2026, 10, 1, 21, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(path, stamp, stamp); err != nil {
+		t.Fatal(err)
+	}
+	serverLines := func() int {
+		t.Helper()
+		sessions, err := l.Sessions()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, s := range sessions {
+			if s.ID == id {
+				return s.Server.LineCount
+			}
+		}
+		return -1
+	}
+	if n := serverLines(); n != 2 {
+		t.Fatalf("line count %d, want 2", n)
+	}
+
+	// Same size and time: the cached count stands, the file is not read.
+	writeLines(t, path, `{"event":"abcdefghijklmno"}`)
+	if err := os.Chtimes(path, stamp, stamp); err != nil {
+		t.Fatal(err)
+	}
+	if n := serverLines(); n != 2 {
+		t.Errorf("line count %d, want the cached 2", n)
+	}
+
+	writeLines(t, path, `{"event":"a"}`, `{"event":"b"}`, `{"event":"c"}`)
+	if n := serverLines(); n != 3 {
+		t.Errorf("line count %d, want 3 once the file grew", n)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	serverLines()
+	l.lineCountsMu.Lock()
+	defer l.lineCountsMu.Unlock()
+	if _, ok := l.lineCounts[path]; ok {
+		t.Error("a deleted file must leave the cache")
+	}
+}
@@ -0,0 +1,187 @@
+package logger
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Policy bounds a log tree. Zero fields disable the matching limit.
+type Policy struct {
+	// MaxFileBytes is the size past which a file being written is closed and
+	// a new one started.
+	MaxFileBytes int64
+	// MaxAge deletes closed files last modified longer ago.
+	MaxAge time.Duration
+	// MaxTotalBytes deletes the oldest closed files until the tree fits.
+	MaxTotalBytes int64
+	// Compress gzips closed files.
+	Compress bool
+}
+
+// sweepGrace protects files modified this recently, such as a part opened by
+// a rotation after the caller listed its active files.
+const sweepGrace = time.Minute
+
+// SweepResult counts what Sweep did.
+type SweepResult struct {
+	Compressed int
+	Deleted    int
+	FreedBytes int64
+}
+
+type sweptFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// Sweep applies p to every file under dirs except the active ones, which are
+// still being written: it gzips closed files, then deletes by age, then
+// deletes the oldest until the total fits. Active files, and any modified in
+// the last minute, count towards the total but are never touched. Errors on
+// single files are joined and do not stop the sweep.
+func Sweep(p Policy, active []string, dirs ...string) (SweepResult, error) {
+	var res SweepResult
+	isActive := make(map[string]bool, len(active))
+	for _, a := range active {
+		isActive[filepath.Clean(a)] = true
+	}
+
+	var files []sweptFile
+	var activeBytes int64
+	var errs []error
+	walk := func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if !d.Type().IsRegular() || strings.HasSuffix(path, ".tmp") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		if isActive[filepath.Clean(path)] || time.Since(info.ModTime()) < sweepGrace {
+			activeBytes += info.Size()
+			return nil
+		}
+		files = append(files, sweptFile{path: path, size: info.Size(), modTime: info.ModTime()})
+		return nil
+	}
+	for _, dir := range dirs {
+		if err := filepath.WalkDir(dir, walk); err != nil {
+			return res, err
+		}
+	}
+
+	if p.Compress {
+		for i, f := range files {
+			if strings.HasSuffix(f.path, ".gz") {
+				continue
+			}
+			gz, err := compressFile(f.path)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			info, err := os.Stat(gz)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			res.Compressed++
+			files[i] = sweptFile{path: gz, size: info.Size(), modTime: f.modTime}
+		}
+	}
+
+	remove := func(f sweptFile) bool {
+		if err := os.Remove(f.path); err != nil {
+			errs = append(errs, err)
+			return false
+		}
+		res.Deleted++
+		res.FreedBytes += f.size
+		return true
+	}
+
+	slices.SortFunc(files, func(a, b sweptFile) int { return a.modTime.Compare(b.modTime) })
+	var kept []sweptFile
+	cutoff := time.Now().Add(-p.MaxAge)
+	for _, f := range files {
+		if p.MaxAge > 0 && f.modTime.Before(cutoff) && remove(f) {
+			continue
+		}
+		kept = append(kept, f)
+	}
+
+	if p.MaxTotalBytes > 0 {
+		total := activeBytes
+		for _, f := range kept {
+			total += f.size
+		}
+		for _, f := range kept {
+			if total <= p.MaxTotalBytes {
+				break
+			}
+			if remove(f) {
+				total -= f.size
+			}
+		}
+	}
+	return res, errors.Join(errs...)
+}
+
+// compressFile writes path.gz, keeping the modification time, and removes
+// path. A failed attempt leaves the original in place.
+func compressFile(path string) (string, error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+	info, err := src.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	dst := path + ".gz"
+	tmp := dst + ".tmp"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return "", err
+	}
+	zw := gzip.NewWriter(out)
+	zw.Name = filepath.Base(path)
+	zw.ModTime = info.ModTime()
+	_, err = io.Copy(zw, src)
+	if cerr := zw.Close(); err == nil {
+		err = cerr
+	}
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, dst)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return "", fmt.Errorf("compress %s: %w", path, err)
+	}
+	_ = os.Chtimes(dst, info.ModTime(), info.ModTime())
+	src.Close()
+	if err := os.Remove(path); err != nil {
+		return dst, fmt.Errorf("remove %s: %w", path, err)
+	}
+	return dst, nil
+}
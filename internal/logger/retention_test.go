@@ -0,0 +1,111 @@
+package logger
+
+import (
+	"compress/gzip"
+	"crypto/rand"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeAged(t *testing.T, path string, size int, age time.Duration) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// Random bytes barely compress, so sizes hold after gzip.
+	data := make([]byte, size)
+	_, _ = rand.Read(data)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	mtime := time.Now().Add(-age)
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func TestSweepCompressesAndPrunes(t *testing.T) {
+	dir := t.TempDir()
+	ancient := filepath.Join(dir, "a", "session_old.jsonl")
+	older := filepath.Join(dir, "a", "session_older.jsonl")
+	recent := filepath.Join(dir, "b", "errors_recent.log")
+	active := filepath.Join(dir, "b", "session_active.jsonl")
+	fresh := filepath.Join(dir, "b", "session_fresh.jsonl")
+	writeAged(t, ancient, 100, 30*24*time.Hour)
+	writeAged(t, older, 5000, 3*time.Hour)
+	writeAged(t, recent, 5000, 2*time.Hour)
+	writeAged(t, active, 3000, time.Hour)
+	writeAged(t, fresh, 10, 0)
+
+	res, err := Sweep(Policy{MaxAge: 7 * 24 * time.Hour, MaxTotalBytes: 9000, Compress: true},
+		[]string{active}, filepath.Join(dir, "a"), filepath.Join(dir, "b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exists(ancient) || exists(ancient+".gz") {
+		t.Error("files past MaxAge must be deleted")
+	}
+	if exists(older) || exists(older+".gz") {
+		t.Error("the oldest file must go first when over MaxTotalBytes")
+	}
+	if !exists(active) || !exists(fresh) {
+		t.Error("active and just-written files must be left alone")
+	}
+	if exists(recent) || !exists(recent+".gz") {
+		t.Fatal("closed files must be gzipped")
+	}
+	if res.Compressed != 3 || res.Deleted != 2 {
+		t.Errorf("result = %+v, want 3 compressed and 2 deleted", res)
+	}
+
+	f, err := os.Open(recent + ".gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil || len(data) != 5000 {
+		t.Errorf("decompressed %d bytes, err=%v", len(data), err)
+	}
+	if info, _ := os.Stat(recent + ".gz"); time.Since(info.ModTime()) < time.Hour {
+		t.Error("the archive must keep the original modification time")
+	}
+}
+
+func TestLoggerRotatesPastMaxFileSize(t *testing.T) {
+	dir := t.TempDir()
+	l := New(dir, true)
+	defer l.Stop()
+	l.SetMaxFileSize(200)
+
+	for range 3 {
+		l.Info("CAT", "event", strings.Repeat("y", 250), nil)
+		l.Flush()
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "sessions", "session_*.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 3 {
+		t.Fatalf("got %d session parts, want 3: %v", len(files), files)
+	}
+	active := l.ActiveFiles()[0]
+	if !strings.HasSuffix(active, "_3.jsonl") {
+		t.Errorf("active session file = %s, want the third part", active)
+	}
+}
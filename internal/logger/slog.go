@@ -0,0 +1,273 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LevelSuccess sits between info and warn; the console prints it in green.
+const LevelSuccess = slog.Level(2)
+
+// LevelCritical is above error; Logger.Critical logs at it.
+const LevelCritical = slog.LevelError + 4
+
+// TagKey is the attribute holding the subsystem tag ("PKT", "NET", ...).
+// Records without one are tagged "APP".
+const TagKey = "tag"
+
+// DefaultLevelKey names the fallback entry in Levels and SetLevels.
+const DefaultLevelKey = "default"
+
+var (
+	levelsMu     sync.RWMutex
+	defaultLevel = slog.LevelInfo
+	tagLevels    = map[string]slog.Level{}
+
+	sessionMu sync.RWMutex
+	session   *Logger
+
+	pipeline = slog.New(&handler{})
+)
+
+// Slog returns the logger every Print* helper goes through. Records fan out
+// to the TUI callback or stdout, and to the session file once attached.
+func Slog() *slog.Logger {
+	return pipeline
+}
+
+// For returns a logger tagged with a subsystem, as PrintInfo(tag, ...) is.
+func For(tag string) *slog.Logger {
+	return pipeline.With(TagKey, tag)
+}
+
+// AttachSession also writes records to l's JSONL session file, under the
+// "[SERVER] <tag>" category. Pass nil to detach.
+func AttachSession(l *Logger) {
+	sessionMu.Lock()
+	defer sessionMu.Unlock()
+	session = l
+}
+
+// ParseLevel accepts debug, info, success, warn and error, in any case.
+func ParseLevel(s string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "success":
+		return LevelSuccess, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("unknown log level %q (want debug, info, success, warn or error)", s)
+}
+
+// levelName is the upper-case name the callback and the session file use.
+func levelName(l slog.Level) string {
+	switch {
+	case l < slog.LevelInfo:
+		return "DEBUG"
+	case l < LevelSuccess:
+		return "INFO"
+	case l < slog.LevelWarn:
+		return "SUCCESS"
+	case l < slog.LevelError:
+		return "WARN"
+	case l < LevelCritical:
+		return "ERROR"
+	}
+	return "CRITICAL"
+}
+
+// SetLevels replaces the per-tag levels. The "default" key sets the level of
+// tags without their own entry; it is info when absent. Nothing changes when
+// a level does not parse.
+func SetLevels(levels map[string]string) error {
+	def := slog.LevelInfo
+	byTag := make(map[string]slog.Level, len(levels))
+	for tag, s := range levels {
+		l, err := ParseLevel(s)
+		if err != nil {
+			return fmt.Errorf("%s: %w", tag, err)
+		}
+		if tag == DefaultLevelKey {
+			def = l
+			continue
+		}
+		byTag[strings.ToUpper(tag)] = l
+	}
+	levelsMu.Lock()
+	defer levelsMu.Unlock()
+	defaultLevel, tagLevels = def, byTag
+	return nil
+}
+
+// Levels returns the current levels by tag, with the fallback under "default".
+func Levels() map[string]string {
+	levelsMu.RLock()
+	defer levelsMu.RUnlock()
+	out := make(map[string]string, len(tagLevels)+1)
+	out[DefaultLevelKey] = strings.ToLower(levelName(defaultLevel))
+	for tag, l := range tagLevels {
+		out[tag] = strings.ToLower(levelName(l))
+	}
+	return out
+}
+
+func levelFor(tag string) slog.Level {
+	levelsMu.RLock()
+	defer levelsMu.RUnlock()
+	if l, ok := tagLevels[tag]; ok {
+		return l
+	}
+	return defaultLevel
+}
+
+// minLevel is the lowest level any tag lets through.
+func minLevel() slog.Level {
+	levelsMu.RLock()
+	defer levelsMu.RUnlock()
+	lowest := defaultLevel
+	for _, l := range tagLevels {
+		lowest = min(lowest, l)
+	}
+	return lowest
+}
+
+// entry is a record as the outputs see it, with the tag taken out of the
+// attributes.
+type entry struct {
+	time   time.Time
+	level  slog.Level
+	tag    string
+	msg    string
+	fields []Field
+}
+
+// handler is the slog.Handler behind Slog. It filters on the tag's level and
+// hands each record to the outputs: the TUI callback when one is set,
+// otherwise stdout, plus the session file when attached.
+type handler struct {
+	tag    string
+	attrs  []Field
+	prefix string // open groups, dot-joined, ending with "."
+}
+
+func (h *handler) Enabled(_ context.Context, l slog.Level) bool {
+	if h.tag != "" {
+		return l >= levelFor(h.tag)
+	}
+	return l >= minLevel()
+}
+
+func (h *handler) Handle(_ context.Context, r slog.Record) error {
+	e := entry{time: r.Time, level: r.Level, tag: h.tag, msg: r.Message, fields: slices.Clone(h.attrs)}
+	r.Attrs(func(a slog.Attr) bool {
+		if a.Key == TagKey && h.prefix == "" {
+			e.tag = a.Value.String()
+			return true
+		}
+		e.fields = appendAttr(e.fields, h.prefix, a)
+		return true
+	})
+	if e.tag == "" {
+		e.tag = "APP"
+	}
+	if e.time.IsZero() {
+		e.time = time.Now()
+	}
+	if e.level < levelFor(e.tag) {
+		return nil
+	}
+	dispatch(e)
+	return nil
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	out := *h
+	out.attrs = slices.Clip(h.attrs)
+	for _, a := range attrs {
+		if a.Key == TagKey && h.prefix == "" {
+			out.tag = a.Value.String()
+			continue
+		}
+		out.attrs = appendAttr(out.attrs, h.prefix, a)
+	}
+	return &out
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	out := *h
+	out.prefix = h.prefix + name + "."
+	return &out
+}
+
+// appendAttr flattens groups into dotted keys.
+func appendAttr(fields []Field, prefix string, a slog.Attr) []Field {
+	v := a.Value.Resolve()
+	if v.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, g := range v.Group() {
+			fields = appendAttr(fields, prefix, g)
+		}
+		return fields
+	}
+	if a.Key == "" {
+		return fields
+	}
+	return append(fields, Field{Key: prefix + a.Key, Value: v.Any()})
+}
+
+func dispatch(e entry) {
+	display(e)
+
+	sessionMu.RLock()
+	s := session
+	sessionMu.RUnlock()
+	if s != nil {
+		var data map[string]any
+		if len(e.fields) > 0 {
+			data = make(map[string]any, len(e.fields))
+			for _, f := range e.fields {
+				data[f.Key] = f.Value
+			}
+		}
+		s.Log(levelName(e.level), e.tag, e.msg, data, nil)
+	}
+}
+
+// display hands an entry to the TUI callback when one is set, otherwise to
+// stdout.
+func display(e entry) {
+	callbackMu.RLock()
+	cb := logCallback
+	callbackMu.RUnlock()
+	if cb != nil {
+		cb(levelName(e.level), e.tag, e.line())
+	} else {
+		writeConsole(e)
+	}
+}
+
+// line is the message followed by key=value fields, for the dashboard and
+// the pretty console.
+func (e entry) line() string {
+	line := e.msg
+	for _, f := range e.fields {
+		line += " " + f.Key + "=" + fmt.Sprint(f.Value)
+	}
+	return line
+}
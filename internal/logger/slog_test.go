@@ -0,0 +1,135 @@
+package logger
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/segmentio/encoding/json"
+)
+
+func withLevels(t *testing.T, levels map[string]string) {
+	t.Helper()
+	prev := Levels()
+	if err := SetLevels(levels); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = SetLevels(prev) })
+}
+
+func TestSlogPerTagLevels(t *testing.T) {
+	buf := captureConsole(t, FormatText)
+	withLevels(t, map[string]string{"default": "warn", "pkt": "debug"})
+
+	PrintInfo("NET", "hidden")
+	PrintWarn("NET", "shown")
+	For("PKT").Debug("decoded", slog.Int("code", 3))
+	Slog().Info("untagged")
+
+	out := buf.String()
+	if strings.Contains(out, "hidden") || strings.Contains(out, "untagged") {
+		t.Errorf("info must be filtered under a warn default:\n%s", out)
+	}
+	for _, want := range []string{"tag=NET msg=shown", "level=debug tag=PKT msg=decoded code=3"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+	if got := Levels(); got["default"] != "warn" || got["PKT"] != "debug" {
+		t.Errorf("Levels() = %v", got)
+	}
+	if err := SetLevels(map[string]string{"NET": "loud"}); err == nil {
+		t.Error("an unknown level must be rejected")
+	}
+	if Levels()["PKT"] != "debug" {
+		t.Error("a rejected SetLevels must keep the previous levels")
+	}
+}
+
+func TestSlogKeepsTagAndGroupsAsAttributes(t *testing.T) {
+	buf := captureConsole(t, FormatJSON)
+
+	For("WS").WithGroup("client").Info("connected", "addr", "10.0.0.2", "tag", "not-the-tag")
+
+	var line map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("%v: %s", err, buf.String())
+	}
+	if line["tag"] != "WS" || line["client.addr"] != "10.0.0.2" || line["client.tag"] != "not-the-tag" {
+		t.Errorf("got %v", line)
+	}
+}
+
+func TestSlogCallbackAndSession(t *testing.T) {
+	dir := t.TempDir()
+	l := New(dir, true)
+	AttachSession(l)
+	var got []string
+	SetLogCallback(func(level, tag, message string) {
+		got = append(got, level+" "+tag+" "+message)
+	})
+	t.Cleanup(func() {
+		ClearLogCallback()
+		AttachSession(nil)
+		l.Stop()
+	})
+
+	PrintSuccess("HTTP", "Server: %s", "http://localhost:5001")
+	PrintFields("STATS", "Radar stats", Field{Key: "packets", Value: 7})
+	l.Flush()
+
+	if len(got) != 2 || got[0] != "SUCCESS HTTP Server: http://localhost:5001" || got[1] != "INFO STATS Radar stats packets=7" {
+		t.Errorf("callback got %q", got)
+	}
+	lines := readSessionLines(t, dir)
+	if len(lines) != 2 {
+		t.Fatalf("session file: got %d lines, want 2: %q", len(lines), lines)
+	}
+	var entry LogEntry
+	if err := json.Unmarshal([]byte(lines[1]), &entry); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := entry.Data.(map[string]any)
+	if entry.Category != "[SERVER] STATS" || entry.Event != "Radar stats" || data["packets"] != float64(7) {
+		t.Errorf("session entry = %+v", entry)
+	}
+}
+
+func TestLoggerMethodsFilterTheConsoleOnly(t *testing.T) {
+	buf := captureConsole(t, FormatText)
+	withLevels(t, map[string]string{"default": "info", "EVENT_CAPTURE": "warn"})
+	dir := t.TempDir()
+	l := New(dir, true)
+	t.Cleanup(l.Stop)
+
+	l.Debug("NET", "too low", nil, nil)
+	l.Info("EVENT_CAPTURE", "Event_3", map[string]any{"code": 3}, nil)
+	l.Warn("NET", "shown", map[string]any{"iface": "eth0"}, nil)
+	l.Flush()
+
+	out := buf.String()
+	if strings.Contains(out, "too low") || strings.Contains(out, "Event_3") {
+		t.Errorf("the per-tag levels must apply to the console:\n%s", out)
+	}
+	if !strings.Contains(out, "level=warn tag=NET msg=shown iface=eth0") {
+		t.Errorf("missing the warning in:\n%s", out)
+	}
+	lines := readSessionLines(t, dir)
+	if len(lines) != 3 {
+		t.Fatalf("session file: got %d lines, want all 3: %q", len(lines), lines)
+	}
+	var entry LogEntry
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry.Level != "DEBUG" || entry.Category != "[SERVER] NET" || entry.Event != "too low" {
+		t.Errorf("a debug entry must reach the session file at the default level, got %+v", entry)
+	}
+	if err := json.Unmarshal([]byte(lines[2]), &entry); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := entry.Data.(map[string]any)
+	if entry.Level != "WARN" || data["iface"] != "eth0" {
+		t.Errorf("session entry = %+v", entry)
+	}
+}
@@ -0,0 +1,221 @@
+// Package pcapiter walks the UDP packets of captures, classic pcap or pcapng,
+// gzipped or not, named one by one, by directory or by glob. The tools and
+// photonscan share it so that a broken file is reported the same way
+// everywhere.
+package pcapiter
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+)
+
+// ErrStop, returned by a callback, ends the walk early without an error.
+var ErrStop = errors.New("pcapiter: stop")
+
+// Flow is the UDP endpoints of a packet.
+type Flow struct {
+	Src netip.AddrPort
+	Dst netip.AddrPort
+}
+
+func (f Flow) String() string { return f.Src.String() + " -> " + f.Dst.String() }
+
+// Packet is one UDP packet of a capture.
+type Packet struct {
+	File string
+	// Index counts every packet of the file, UDP or not, from 0.
+	Index     int
+	Timestamp time.Time
+	Flow      Flow
+	Payload   []byte
+	// Frame is the whole decoded packet, for callers that rewrite it.
+	Frame gopacket.Packet
+}
+
+// Stats describes one walked file.
+type Stats struct {
+	Path string
+	// Packets counts every packet read, UDP the ones handed to the callback
+	// and Bytes their payloads.
+	Packets int
+	UDP     int
+	Bytes   int64
+	// First and Last are the timestamps of the first and last packet.
+	First time.Time
+	Last  time.Time
+	// Truncated is set when the file ends mid-record, as after a crash or
+	// when copied while still being written. What came before is kept.
+	Truncated bool
+}
+
+// Duration is the time the capture spans.
+func (s Stats) Duration() time.Duration {
+	return s.Last.Sub(s.First)
+}
+
+// File hands every UDP packet of path to fn. A truncated file ends the walk
+// with Stats.Truncated set rather than an error; any other read error is
+// returned along with the stats so far, as is an error from fn.
+func File(path string, fn func(Packet) error) (Stats, error) {
+	st := Stats{Path: path}
+	r, closer, err := Open(path)
+	if err != nil {
+		return st, err
+	}
+	defer closer.Close()
+
+	for i := 0; ; i++ {
+		data, ci, err := r.ReadPacketData()
+		switch {
+		case errors.Is(err, io.EOF):
+			return st, nil
+		case errors.Is(err, io.ErrUnexpectedEOF):
+			st.Truncated = true
+			return st, nil
+		case err != nil:
+			return st, fmt.Errorf("%s: packet %d: %w", path, i, err)
+		}
+		st.Packets++
+		if st.First.IsZero() {
+			st.First = ci.Timestamp
+		}
+		st.Last = ci.Timestamp
+
+		frame := gopacket.NewPacket(data, r.LinkType(), gopacket.Default)
+		udp, _ := frame.Layer(layers.LayerTypeUDP).(*layers.UDP)
+		if udp == nil {
+			continue
+		}
+		st.UDP++
+		st.Bytes += int64(len(udp.Payload))
+		p := Packet{File: path, Index: i, Timestamp: ci.Timestamp, Payload: udp.Payload, Frame: frame}
+		if nl := frame.NetworkLayer(); nl != nil {
+			src, _ := netip.AddrFromSlice(nl.NetworkFlow().Src().Raw())
+			dst, _ := netip.AddrFromSlice(nl.NetworkFlow().Dst().Raw())
+			p.Flow = Flow{
+				Src: netip.AddrPortFrom(src.Unmap(), uint16(udp.SrcPort)),
+				Dst: netip.AddrPortFrom(dst.Unmap(), uint16(udp.DstPort)),
+			}
+		}
+		if err := fn(p); err != nil {
+			return st, err
+		}
+	}
+}
+
+// Walk expands args and walks each file in turn. A file that fails to read
+// does not stop the others: its error is joined into the one returned. An
+// error from fn stops the walk, and ErrStop does so quietly.
+func Walk(args []string, fn func(Packet) error) ([]Stats, error) {
+	paths, err := Expand(args...)
+	if err != nil {
+		return nil, err
+	}
+	var stats []Stats
+	var errs []error
+	for _, path := range paths {
+		fnFailed := false
+		st, err := File(path, func(p Packet) error {
+			if err := fn(p); err != nil {
+				fnFailed = true
+				return err
+			}
+			return nil
+		})
+		stats = append(stats, st)
+		switch {
+		case fnFailed && errors.Is(err, ErrStop):
+			return stats, errors.Join(errs...)
+		case fnFailed:
+			return stats, err
+		case err != nil:
+			errs = append(errs, err)
+		}
+	}
+	return stats, errors.Join(errs...)
+}
+
+// IsCapture reports whether name looks like a capture file: .pcap or
+// .pcapng, gzipped or not.
+func IsCapture(name string) bool {
+	name = strings.TrimSuffix(name, ".gz")
+	return strings.HasSuffix(name, ".pcap") || strings.HasSuffix(name, ".pcapng")
+}
+
+// Expand resolves args into capture files. A file is taken as it is, a
+// directory gives the captures directly inside it, and anything else is
+// matched as a glob. The result is sorted without duplicates; an argument
+// that yields nothing is an error.
+func Expand(args ...string) ([]string, error) {
+	var out []string
+	for _, arg := range args {
+		found, err := expandOne(arg)
+		if err != nil {
+			return nil, err
+		}
+		if len(found) == 0 {
+			return nil, fmt.Errorf("%s: no capture files", arg)
+		}
+		out = append(out, found...)
+	}
+	slices.Sort(out)
+	return slices.Compact(out), nil
+}
+
+func expandOne(arg string) ([]string, error) {
+	info, err := os.Stat(arg)
+	switch {
+	case err == nil && !info.IsDir():
+		return []string{arg}, nil
+	case err == nil:
+		entries, err := os.ReadDir(arg)
+		if err != nil {
+			return nil, err
+		}
+		var out []string
+		for _, e := range entries {
+			if e.Type().IsRegular() && IsCapture(e.Name()) {
+				out = append(out, filepath.Join(arg, e.Name()))
+			}
+		}
+		return out, nil
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, err
+	}
+	matches, err := filepath.Glob(arg)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", arg, err)
+	}
+	if matches == nil {
+		return nil, fmt.Errorf("%s: %w", arg, os.ErrNotExist)
+	}
+	var out []string
+	for _, m := range matches {
+		if info, err := os.Stat(m); err == nil && !info.IsDir() {
+			out = append(out, m)
+		}
+	}
+	return out, nil
+}
+
+// ReportStats writes one line per file: packets, UDP packets, span, and a
+// warning when the file was cut short.
+func ReportStats(w io.Writer, stats []Stats) {
+	for _, s := range stats {
+		line := fmt.Sprintf("%s: %d packets, %d UDP, %s", s.Path, s.Packets, s.UDP, s.Duration().Round(time.Millisecond))
+		if s.Truncated {
+			line += ", TRUNCATED (last record incomplete)"
+		}
+		fmt.Fprintln(w, line)
+	}
+}
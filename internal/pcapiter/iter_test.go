@@ -0,0 +1,125 @@
+package pcapiter
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/google/gopacket/pcapgo"
+	"github.com/stretchr/testify/require"
+)
+
+// synthetic: packets are constructed in-process; no live Albion traffic needed.
+
+func writeMiniPcap(t *testing.T, path string, payloads ...[]byte) {
+	t.Helper()
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+	w := pcapgo.NewWriter(f)
+	require.NoError(t, w.WriteFileHeader(1600, layers.LinkTypeEthernet))
+	for i, payload := range payloads {
+		eth := &layers.Ethernet{
+			SrcMAC: []byte{0xaa, 0, 0, 0, 0, 1}, DstMAC: []byte{0xbb, 0, 0, 0, 0, 2},
+			EthernetType: layers.EthernetTypeIPv4,
+		}
+		ip := &layers.IPv4{Version: 4, IHL: 5, TTL: 64, Protocol: layers.IPProtocolUDP,
+			SrcIP: []byte{10, 0, 0, 1}, DstIP: []byte{10, 0, 0, 2}}
+		udp := &layers.UDP{SrcPort: 5056, DstPort: 50000}
+		require.NoError(t, udp.SetNetworkLayerForChecksum(ip))
+		buf := gopacket.NewSerializeBuffer()
+		opts := gopacket.SerializeOptions{FixLengths: true, ComputeChecksums: true}
+		require.NoError(t, gopacket.SerializeLayers(buf, opts, eth, ip, udp, gopacket.Payload(payload)))
+		require.NoError(t, w.WritePacket(gopacket.CaptureInfo{
+			Timestamp: time.Unix(int64(i), 0), CaptureLength: len(buf.Bytes()), Length: len(buf.Bytes()),
+		}, buf.Bytes()))
+	}
+}
+
+func TestFile_HandsOverEachUDPPayloadWithItsFlowAndTime(t *testing.T) {
+	in := filepath.Join(t.TempDir(), "mini.pcap")
+	writeMiniPcap(t, in, []byte{0x01, 0x02}, []byte{0x03, 0x04, 0x05})
+
+	var got []Packet
+	st, err := File(in, func(p Packet) error { got = append(got, p); return nil })
+
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	require.Equal(t, []byte{0x03, 0x04, 0x05}, got[1].Payload)
+	require.Equal(t, "10.0.0.1:5056 -> 10.0.0.2:50000", got[1].Flow.String())
+	require.Equal(t, time.Unix(1, 0).UTC(), got[1].Timestamp.UTC())
+	require.Equal(t, 1, got[1].Index)
+	require.Equal(t, Stats{Path: in, Packets: 2, UDP: 2, Bytes: 5, First: got[0].Timestamp, Last: got[1].Timestamp}, st)
+	require.Equal(t, time.Second, st.Duration())
+}
+
+func TestFile_ReportsATruncatedFileAndKeepsWhatCameBefore(t *testing.T) {
+	in := filepath.Join(t.TempDir(), "cut.pcap")
+	writeMiniPcap(t, in, []byte("first"), []byte("second"))
+	info, err := os.Stat(in)
+	require.NoError(t, err)
+	require.NoError(t, os.Truncate(in, info.Size()-3))
+
+	n := 0
+	st, err := File(in, func(Packet) error { n++; return nil })
+
+	require.NoError(t, err)
+	require.True(t, st.Truncated)
+	require.Equal(t, 1, n)
+}
+
+func TestWalk_GoesOnPastABrokenFileAndStopsOnErrStop(t *testing.T) {
+	dir := t.TempDir()
+	writeMiniPcap(t, filepath.Join(dir, "a.pcap"), []byte("a1"), []byte("a2"))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.pcap"), []byte("not a capture"), 0o644))
+	writeMiniPcap(t, filepath.Join(dir, "c.pcap"), []byte("c1"))
+
+	var files []string
+	stats, err := Walk([]string{dir}, func(p Packet) error {
+		files = append(files, filepath.Base(p.File))
+		return nil
+	})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "b.pcap")
+	require.Equal(t, []string{"a.pcap", "a.pcap", "c.pcap"}, files)
+	require.Len(t, stats, 3)
+
+	n := 0
+	stats, err = Walk([]string{dir}, func(Packet) error { n++; return ErrStop })
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Len(t, stats, 1)
+
+	boom := errors.New("boom")
+	_, err = Walk([]string{dir}, func(Packet) error { return boom })
+	require.ErrorIs(t, err, boom)
+}
+
+func TestExpand_ResolvesFilesDirectoriesAndGlobs(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.pcap", "b.pcapng.gz", "notes.txt"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0o644))
+	}
+	a, b := filepath.Join(dir, "a.pcap"), filepath.Join(dir, "b.pcapng.gz")
+
+	got, err := Expand(dir)
+	require.NoError(t, err)
+	require.Equal(t, []string{a, b}, got)
+
+	got, err = Expand(filepath.Join(dir, "*.pcap"), a)
+	require.NoError(t, err)
+	require.Equal(t, []string{a}, got, "duplicates are dropped")
+
+	got, err = Expand(filepath.Join(dir, "notes.txt"))
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join(dir, "notes.txt")}, got, "a named file is taken as it is")
+
+	_, err = Expand(filepath.Join(dir, "missing.pcap"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+	_, err = Expand(filepath.Join(dir, "*.erf"))
+	require.Error(t, err)
+}
@@ -0,0 +1,104 @@
+package pcapiter
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/google/gopacket/pcapgo"
+)
+
+// PacketReader is what the classic pcap and the pcapng readers share.
+// LinkType is the link type of the last packet read, since a pcapng file may
+// mix interfaces of different types; before the first packet it is the
+// file's first one.
+type PacketReader interface {
+	ReadPacketData() ([]byte, gopacket.CaptureInfo, error)
+	LinkType() layers.LinkType
+}
+
+var (
+	gzipMagic   = []byte{0x1f, 0x8b}
+	pcapngMagic = []byte{0x0a, 0x0d, 0x0d, 0x0a}
+)
+
+// Open reads a classic pcap or a pcapng capture, gzipped or not, telling
+// them apart by their first bytes rather than the file name. Close the
+// returned closer when done.
+func Open(path string) (PacketReader, io.Closer, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	r, closer, err := NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	return r, closers{closer, f}, nil
+}
+
+// NewReader is Open over an already opened stream.
+func NewReader(src io.Reader) (PacketReader, io.Closer, error) {
+	br := bufio.NewReader(src)
+	var closer io.Closer = closers{}
+	if magic, _ := br.Peek(2); bytes.Equal(magic, gzipMagic) {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, nil, err
+		}
+		closer = zr
+		br = bufio.NewReader(zr)
+	}
+
+	if magic, _ := br.Peek(4); bytes.Equal(magic, pcapngMagic) {
+		ng, err := pcapgo.NewNgReader(br, pcapgo.NgReaderOptions{WantMixedLinkType: true})
+		if err != nil {
+			closer.Close()
+			return nil, nil, err
+		}
+		return &ngReader{NgReader: ng, linkType: ng.LinkType()}, closer, nil
+	}
+	r, err := pcapgo.NewReader(br)
+	if err != nil {
+		closer.Close()
+		return nil, nil, err
+	}
+	return r, closer, nil
+}
+
+// ngReader tracks the link type of each packet's interface.
+type ngReader struct {
+	*pcapgo.NgReader
+	linkType layers.LinkType
+}
+
+func (r *ngReader) ReadPacketData() ([]byte, gopacket.CaptureInfo, error) {
+	data, ci, err := r.NgReader.ReadPacketData()
+	if err == nil && len(ci.AncillaryData) > 0 {
+		if lt, ok := ci.AncillaryData[0].(layers.LinkType); ok {
+			r.linkType = lt
+		}
+	}
+	return data, ci, err
+}
+
+func (r *ngReader) LinkType() layers.LinkType { return r.linkType }
+
+// closers closes each in turn and returns the first error.
+type closers []io.Closer
+
+func (cs closers) Close() error {
+	var first error
+	for _, c := range cs {
+		if err := c.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
@@ -0,0 +1,145 @@
+// Package codestats counts decoded Photon messages per kind and Albion code,
+// so "is code X still arriving after the patch?" can be answered live
+// instead of from a pcap and photon-dump -inventory.
+package codestats
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/nospy/albion-openradar/internal/photon/eventcodes"
+	"github.com/nospy/albion-openradar/internal/photon/operationcodes"
+)
+
+// RateWindow is how far back Stat.Rate looks.
+const RateWindow = 10 * time.Second
+
+const windowSecs = int64(RateWindow / time.Second)
+
+// Kind matches the "code" field of the WebSocket messages.
+type Kind string
+
+const (
+	Event    Kind = "event"
+	Request  Kind = "request"
+	Response Kind = "response"
+)
+
+// Stat is one (kind, code) pair. Name is empty for codes missing from the JS
+// enums, which is usually the first sign of a game patch.
+type Stat struct {
+	Kind     Kind      `json:"kind"`
+	Code     int       `json:"code"`
+	Name     string    `json:"name"`
+	Count    uint64    `json:"count"`
+	Rate     float64   `json:"rate"`
+	LastSeen time.Time `json:"lastSeen"`
+}
+
+type key struct {
+	kind Kind
+	code int
+}
+
+// counter keeps the total plus one bucket per second of RateWindow, indexed
+// by unix second modulo the window.
+type counter struct {
+	count    uint64
+	lastSeen time.Time
+	buckets  [windowSecs]uint64
+	seconds  [windowSecs]int64
+}
+
+// Counter tallies messages. It is safe for concurrent use.
+type Counter struct {
+	mu      sync.Mutex
+	started time.Time
+	byKey   map[key]*counter
+}
+
+func New() *Counter {
+	return &Counter{started: time.Now(), byKey: make(map[key]*counter)}
+}
+
+// Started is when counting began.
+func (c *Counter) Started() time.Time {
+	return c.started
+}
+
+// Add counts one message. Call it after photon.PostProcess*, which fills in
+// the Albion code.
+func (c *Counter) Add(kind Kind, code int) {
+	c.add(kind, code, time.Now())
+}
+
+func (c *Counter) add(kind Kind, code int, now time.Time) {
+	sec := now.Unix()
+	i := sec % windowSecs
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e := c.byKey[key{kind, code}]
+	if e == nil {
+		e = &counter{}
+		c.byKey[key{kind, code}] = e
+	}
+	e.count++
+	e.lastSeen = now
+	if e.seconds[i] != sec {
+		e.seconds[i] = sec
+		e.buckets[i] = 0
+	}
+	e.buckets[i]++
+}
+
+// Snapshot returns every pair seen so far, busiest first: by rate over the
+// last RateWindow of completed seconds, then by total, then by kind and code.
+func (c *Counter) Snapshot() []Stat {
+	return c.snapshot(time.Now())
+}
+
+func (c *Counter) snapshot(now time.Time) []Stat {
+	sec := now.Unix()
+	// Early on the window is not full yet; average over what exists.
+	span := min(max(sec-c.started.Unix(), 1), windowSecs)
+
+	c.mu.Lock()
+	out := make([]Stat, 0, len(c.byKey))
+	for k, e := range c.byKey {
+		var recent uint64
+		for i, s := range e.seconds {
+			if s < sec && s >= sec-span {
+				recent += e.buckets[i]
+			}
+		}
+		out = append(out, Stat{
+			Kind:     k.kind,
+			Code:     k.code,
+			Name:     Name(k.kind, k.code),
+			Count:    e.count,
+			Rate:     float64(recent) / float64(span),
+			LastSeen: e.lastSeen,
+		})
+	}
+	c.mu.Unlock()
+
+	slices.SortFunc(out, func(a, b Stat) int {
+		return cmp.Or(
+			cmp.Compare(b.Rate, a.Rate),
+			cmp.Compare(b.Count, a.Count),
+			cmp.Compare(a.Kind, b.Kind),
+			cmp.Compare(a.Code, b.Code),
+		)
+	})
+	return out
+}
+
+// Name looks the code up in the enum for its kind.
+func Name(kind Kind, code int) string {
+	if kind == Event {
+		return eventcodes.Name(code)
+	}
+	return operationcodes.Name(code)
+}
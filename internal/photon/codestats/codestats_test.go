@@ -0,0 +1,63 @@
+package codestats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nospy/albion-openradar/internal/photon/eventcodes"
+	"github.com/nospy/albion-openradar/internal/photon/operationcodes"
+)
+
+func TestSnapshotSortsByRateAndNamesCodes(t *testing.T) {
+	start := time.Unix(1_000_000, 0)
+	c := &Counter{started: start, byKey: make(map[key]*counter)}
+
+	// Move is busy now; HealthUpdate was busy long ago and has more in total.
+	for range 50 {
+		c.add(Event, eventcodes.HealthUpdate, start)
+	}
+	later := start.Add(time.Minute)
+	for range 20 {
+		c.add(Event, eventcodes.Move, later)
+	}
+	c.add(Request, operationcodes.Move, later)
+	c.add(Event, 9999, later)
+
+	got := c.snapshot(later.Add(time.Second))
+	if len(got) != 4 {
+		t.Fatalf("got %d stats, want 4: %+v", len(got), got)
+	}
+	if got[0].Kind != Event || got[0].Code != eventcodes.Move || got[0].Name != "Move" {
+		t.Errorf("busiest first: got %+v", got[0])
+	}
+	if got[0].Rate != 2 || got[0].Count != 20 {
+		t.Errorf("20 messages over a 10s window: got rate %v count %d", got[0].Rate, got[0].Count)
+	}
+	if got[1].Kind != Event || got[1].Code != 9999 || got[2].Kind != Request {
+		t.Errorf("equal rates and counts break ties by kind: got %+v", got[1:3])
+	}
+	last := got[3]
+	if last.Code != eventcodes.HealthUpdate || last.Rate != 0 || last.Count != 50 {
+		t.Errorf("stale code keeps its total but no rate: got %+v", last)
+	}
+	for _, s := range got {
+		if s.Code == 9999 && s.Name != "" {
+			t.Errorf("unknown code must have no name, got %q", s.Name)
+		}
+		if s.Kind == Request && s.Name != "Move" {
+			t.Errorf("requests are named from operationcodes, got %q", s.Name)
+		}
+	}
+}
+
+func TestSnapshotRateEarlyInSession(t *testing.T) {
+	start := time.Unix(2_000_000, 0)
+	c := &Counter{started: start, byKey: make(map[key]*counter)}
+	for range 6 {
+		c.add(Response, 1, start.Add(time.Second))
+	}
+	got := c.snapshot(start.Add(3 * time.Second))
+	if got[0].Rate != 2 {
+		t.Errorf("6 messages 3s into the session: got rate %v, want 2", got[0].Rate)
+	}
+}
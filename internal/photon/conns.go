@@ -0,0 +1,205 @@
+package photon
+
+import (
+	"net/netip"
+	"slices"
+	"sync"
+	"time"
+)
+
+// sentRingSize is how many client->server packets a connection remembers to
+// match the server's acknowledgements against.
+const sentRingSize = 64
+
+// Conn is one Photon connection between the game client and a game server.
+type Conn struct {
+	Server netip.AddrPort
+	Client netip.AddrPort
+	// Challenge is the connection id both ends repeat in every header; a new
+	// one on the same flow means the client reconnected.
+	Challenge uint32
+	// Connected is when the connect command, or failing that the first
+	// packet, was seen; Disconnected stays zero until a disconnect command.
+	Connected    time.Time
+	Disconnected time.Time
+	LastPacket   time.Time
+	Packets      uint64
+	// ServerTime is the header clock of the last server->client packet.
+	ServerTime uint32
+	// RTT is the round trip from a client packet to the server's ack of it,
+	// smoothed over the samples; zero until the first ack.
+	RTT time.Duration
+}
+
+// Active reports whether the connection has not been closed.
+func (c Conn) Active() bool { return c.Disconnected.IsZero() }
+
+type connKey struct {
+	server netip.AddrPort
+	client netip.AddrPort
+}
+
+type sentPacket struct {
+	sentTime uint32
+	at       time.Time
+}
+
+type connState struct {
+	Conn
+	sent [sentRingSize]sentPacket
+	next int
+}
+
+// packetCommands is what the parser saw in one packet that matters to the
+// connection: the lifecycle commands and the sent times the acks echo. A
+// connect only reopens a closed flow, as it is resent until verified.
+type packetCommands struct {
+	connect    bool
+	disconnect bool
+	acks       []uint32
+}
+
+// ConnTracker follows the Photon connections a parser sees, one per
+// server/client flow. Set it as PhotonParser.Conns; it only learns from
+// packets fed with a known direction. It is safe for concurrent use.
+type ConnTracker struct {
+	mu    sync.Mutex
+	conns map[connKey]*connState
+
+	// OnConnect and OnDisconnect, when set before the first packet, are
+	// called from the parser's goroutine as connections open and close.
+	OnConnect    func(Conn)
+	OnDisconnect func(Conn)
+}
+
+func NewConnTracker() *ConnTracker {
+	return &ConnTracker{conns: make(map[connKey]*connState)}
+}
+
+// connEvent is an OnConnect (open) or OnDisconnect call, made once the lock
+// is released.
+type connEvent struct {
+	conn Conn
+	open bool
+}
+
+// observe updates the flow of meta with one packet and its commands.
+func (t *ConnTracker) observe(meta PacketMeta, challenge uint32, cmds *packetCommands) {
+	if meta.Direction == DirUnknown {
+		return
+	}
+	var events []connEvent
+	t.mu.Lock()
+	key := connKey{server: meta.Server, client: meta.Client}
+	c := t.conns[key]
+	if c != nil && c.Active() && c.Challenge != challenge {
+		// The client reconnected without a disconnect we saw.
+		c.Disconnected = c.LastPacket
+		events = append(events, connEvent{conn: c.Conn})
+		c = nil
+	}
+	if c != nil && !c.Active() {
+		if !cmds.connect && c.Challenge == challenge {
+			// Stragglers of the closed connection, such as its ack.
+			t.mu.Unlock()
+			return
+		}
+		c = nil
+	}
+	opened := c == nil
+	if opened {
+		t.pruneLocked(meta.Time)
+		c = &connState{Conn: Conn{Server: meta.Server, Client: meta.Client, Connected: meta.Time}}
+		t.conns[key] = c
+	}
+	c.Challenge = challenge
+	c.LastPacket = meta.Time
+	c.Packets++
+	switch meta.Direction {
+	case DirToServer:
+		c.sent[c.next] = sentPacket{sentTime: meta.SentTime, at: meta.Time}
+		c.next = (c.next + 1) % sentRingSize
+	case DirToClient:
+		c.ServerTime = meta.SentTime
+		for _, sent := range cmds.acks {
+			c.ack(sent, meta.Time)
+		}
+	}
+	if opened {
+		events = append(events, connEvent{conn: c.Conn, open: true})
+	}
+	if cmds.disconnect {
+		c.Disconnected = meta.Time
+		events = append(events, connEvent{conn: c.Conn})
+	}
+	t.mu.Unlock()
+
+	for _, e := range events {
+		switch {
+		case e.open && t.OnConnect != nil:
+			t.OnConnect(e.conn)
+		case !e.open && t.OnDisconnect != nil:
+			t.OnDisconnect(e.conn)
+		}
+	}
+}
+
+// ack takes an RTT sample from the server acknowledging the client packet
+// sent at sentTime, and forgets that packet so a repeated ack does not count.
+func (c *connState) ack(sentTime uint32, at time.Time) {
+	for i := range c.sent {
+		s := &c.sent[i]
+		if s.at.IsZero() || s.sentTime != sentTime {
+			continue
+		}
+		sample := at.Sub(s.at)
+		*s = sentPacket{}
+		if sample < 0 {
+			return
+		}
+		if c.RTT == 0 {
+			c.RTT = sample
+		} else {
+			c.RTT += (sample - c.RTT) / 8
+		}
+		return
+	}
+}
+
+// connIdleExpiry is how long a connection that never said goodbye is kept.
+const connIdleExpiry = 10 * time.Minute
+
+func (t *ConnTracker) pruneLocked(now time.Time) {
+	for k, c := range t.conns {
+		if now.Sub(c.LastPacket) > connIdleExpiry {
+			delete(t.conns, k)
+		}
+	}
+}
+
+// Conns lists the known connections, most recently heard from first.
+func (t *ConnTracker) Conns() []Conn {
+	t.mu.Lock()
+	out := make([]Conn, 0, len(t.conns))
+	for _, c := range t.conns {
+		out = append(out, c.Conn)
+	}
+	t.mu.Unlock()
+	slices.SortFunc(out, func(a, b Conn) int { return b.LastPacket.Compare(a.LastPacket) })
+	return out
+}
+
+// Current is the open connection heard from last, the game server the
+// client plays on.
+func (t *ConnTracker) Current() (Conn, bool) {
+	var cur Conn
+	found := false
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, c := range t.conns {
+		if c.Active() && (!found || c.LastPacket.After(cur.LastPacket)) {
+			cur, found = c.Conn, true
+		}
+	}
+	return cur, found
+}
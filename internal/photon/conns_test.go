@@ -0,0 +1,133 @@
+package photon
+
+import (
+	"encoding/binary"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testServer = netip.MustParseAddrPort("5.188.125.10:5056")
+	testClient = netip.MustParseAddrPort("192.168.1.2:50000")
+	testStart  = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+)
+
+// connPacket is a one-command packet with the given header clock and
+// challenge.
+func connPacket(cmdType byte, body []byte, sentTime, challenge uint32) []byte {
+	pkt := newSingleCommandPhotonPacket(cmdType, body)
+	binary.BigEndian.PutUint32(pkt[4:], sentTime)
+	binary.BigEndian.PutUint32(pkt[8:], challenge)
+	return pkt
+}
+
+func ackBody(sentTime uint32) []byte {
+	body := make([]byte, 8)
+	binary.BigEndian.PutUint32(body[4:], sentTime)
+	return body
+}
+
+// feed hands pkt to p as sent by the client (up) or the server, at ms after
+// testStart.
+func feed(p *PhotonParser, pkt []byte, up bool, ms int) {
+	at := testStart.Add(time.Duration(ms) * time.Millisecond)
+	src, dst := testServer, testClient
+	if up {
+		src, dst = testClient, testServer
+	}
+	p.ReceivePacketMeta(pkt, FlowMeta(at, src, dst, ServerPort))
+}
+
+func newTrackedParser() (*PhotonParser, *ConnTracker, *[]string) {
+	var log []string
+	conns := NewConnTracker()
+	conns.OnConnect = func(c Conn) { log = append(log, "connect "+c.Server.String()) }
+	conns.OnDisconnect = func(c Conn) { log = append(log, "disconnect "+c.Server.String()) }
+	p := NewPhotonParser(nil, nil, nil)
+	p.Conns = conns
+	return p, conns, &log
+}
+
+func TestConnTracker_ConnectAckAndServerClock(t *testing.T) {
+	p, conns, log := newTrackedParser()
+
+	feed(p, connPacket(cmdConnect, nil, 1000, 7), true, 0)
+	feed(p, connPacket(cmdConnect, nil, 1000, 7), true, 5) // resent until verified
+	feed(p, connPacket(cmdAck, ackBody(1000), 555000, 7), false, 40)
+	feed(p, connPacket(cmdSendReliable, []byte{0, msgEvent}, 2000, 7), true, 1000)
+	feed(p, connPacket(cmdAck, ackBody(2000), 556000, 7), false, 1060)
+	feed(p, connPacket(cmdAck, ackBody(2000), 556010, 7), false, 1500) // repeated ack
+
+	require.Equal(t, []string{"connect " + testServer.String()}, *log)
+	cur, ok := conns.Current()
+	require.True(t, ok)
+	require.Equal(t, testServer, cur.Server)
+	require.Equal(t, testClient, cur.Client)
+	require.Equal(t, testStart, cur.Connected)
+	require.Equal(t, testStart.Add(1500*time.Millisecond), cur.LastPacket)
+	require.Equal(t, uint32(556010), cur.ServerTime)
+	require.Equal(t, uint64(6), cur.Packets)
+	// 40 ms, then 60 ms smoothed in by an eighth.
+	require.Equal(t, 42500*time.Microsecond, cur.RTT)
+}
+
+func TestConnTracker_DisconnectEndsTheConnection(t *testing.T) {
+	p, conns, log := newTrackedParser()
+
+	feed(p, connPacket(cmdSendReliable, []byte{0, msgEvent}, 1000, 7), false, 0)
+	feed(p, connPacket(cmdDisconnect, nil, 2000, 7), true, 100)
+	feed(p, connPacket(cmdAck, ackBody(2000), 3000, 7), false, 140)
+
+	require.Equal(t, []string{"connect " + testServer.String(), "disconnect " + testServer.String()}, *log)
+	_, ok := conns.Current()
+	require.False(t, ok, "a logged out client has no current server")
+	all := conns.Conns()
+	require.Len(t, all, 1)
+	require.Equal(t, testStart.Add(100*time.Millisecond), all[0].Disconnected)
+
+	feed(p, connPacket(cmdConnect, nil, 5000, 8), true, 10000)
+	cur, ok := conns.Current()
+	require.True(t, ok)
+	require.Equal(t, uint32(8), cur.Challenge)
+	require.Len(t, *log, 3)
+}
+
+func TestConnTracker_NewChallengeIsAReconnect(t *testing.T) {
+	p, _, log := newTrackedParser()
+
+	feed(p, connPacket(cmdSendReliable, []byte{0, msgEvent}, 1000, 7), false, 0)
+	feed(p, connPacket(cmdSendReliable, []byte{0, msgEvent}, 2000, 9), false, 100)
+
+	require.Equal(t, []string{
+		"connect " + testServer.String(),
+		"disconnect " + testServer.String(),
+		"connect " + testServer.String(),
+	}, *log)
+}
+
+func TestConnTracker_CurrentFollowsTheServerSwitch(t *testing.T) {
+	conns := NewConnTracker()
+	p := NewPhotonParser(nil, nil, nil)
+	p.Conns = conns
+	other := netip.MustParseAddrPort("5.188.125.99:5056")
+
+	feed(p, connPacket(cmdSendReliable, []byte{0, msgEvent}, 1000, 7), false, 0)
+	p.ReceivePacketMeta(connPacket(cmdSendReliable, []byte{0, msgEvent}, 1000, 3),
+		FlowMeta(testStart.Add(time.Second), other, testClient, ServerPort))
+
+	cur, ok := conns.Current()
+	require.True(t, ok)
+	require.Equal(t, other, cur.Server)
+	require.Len(t, conns.Conns(), 2)
+}
+
+func TestConnTracker_IgnoresPacketsWithoutDirection(t *testing.T) {
+	conns := NewConnTracker()
+	p := NewPhotonParser(nil, nil, nil)
+	p.Conns = conns
+	p.ReceivePacket(connPacket(cmdConnect, nil, 1000, 7))
+	require.Empty(t, conns.Conns())
+}
@@ -0,0 +1,388 @@
+// Package entities keeps what the current cluster has announced: mobs,
+// resources, loot chests, dungeon entrances and players. It decodes the same
+// spawn, update and leave events as the web handlers, with the same parameter
+// indices, and forgets everything on a cluster change.
+package entities
+
+import (
+	"encoding/binary"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nospy/albion-openradar/internal/photon"
+	"github.com/nospy/albion-openradar/internal/photon/eventcodes"
+	"github.com/nospy/albion-openradar/internal/photon/operationcodes"
+)
+
+// MaxEntities bounds the tracker; spawns past it are ignored until some leave.
+const MaxEntities = 5000
+
+// Kind is the entity family.
+type Kind string
+
+const (
+	Mob      Kind = "mob"
+	Resource Kind = "resource"
+	Chest    Kind = "chest"
+	Dungeon  Kind = "dungeon"
+	Player   Kind = "player"
+)
+
+// Entity is one tracked object. Fields a kind does not carry stay zero:
+// players have no position (the game encrypts it) and only resources have a
+// Size.
+type Entity struct {
+	ID       int64
+	Kind     Kind
+	Name     string // mob or chest name, resource family, dungeon id, player nickname
+	TypeID   int    // mob type, resource type number (0-27)
+	Tier     int
+	Enchant  int
+	Size     int // resource charges left
+	Guild    string
+	Alliance string
+	X, Y     float32
+	HasPos   bool
+
+	FirstSeen time.Time
+	LastSeen  time.Time
+}
+
+// Snapshot is a copy of the tracker state.
+type Snapshot struct {
+	Cluster string
+	// X, Y is the local player, from the Move request and the Join response.
+	X, Y     float32
+	Entities []Entity
+}
+
+// Tracker is safe for concurrent use.
+type Tracker struct {
+	mu      sync.Mutex
+	cluster string
+	x, y    float32
+	byID    map[int64]*Entity
+	now     func() time.Time
+}
+
+// New returns an empty Tracker.
+func New() *Tracker {
+	return &Tracker{byID: make(map[int64]*Entity), now: time.Now}
+}
+
+// HandleEvent applies a post-processed event.
+func (t *Tracker) HandleEvent(params map[byte]any) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	id, _ := intParam(params, 0)
+	switch photon.AlbionCode(params, 252) {
+	case eventcodes.Leave:
+		delete(t.byID, id)
+
+	case eventcodes.Move:
+		x, okX := floatParam(params, 4)
+		y, okY := floatParam(params, 5)
+		if e := t.byID[id]; e != nil && okX && okY {
+			e.X, e.Y, e.HasPos = x, y, true
+			e.LastSeen = t.now()
+		}
+
+	case eventcodes.NewCharacter:
+		e := t.upsert(id, Player)
+		e.Name = stringParam(params, 1)
+		e.Guild = stringParam(params, 8)
+		e.Alliance = stringParam(params, 51)
+
+	case eventcodes.NewMob:
+		e := t.upsert(id, Mob)
+		e.TypeID = intOr(params, 1)
+		e.Enchant = clampEnchant(intOr(params, 33))
+		e.Name = stringParam(params, 32)
+		if e.Name == "" {
+			e.Name = stringParam(params, 31)
+		}
+		setPos(e, params[7])
+
+	case eventcodes.MobChangeState:
+		if e := t.byID[id]; e != nil && e.Kind == Mob {
+			e.Enchant = clampEnchant(intOr(params, 1))
+			e.LastSeen = t.now()
+		}
+
+	case eventcodes.NewHarvestableObject:
+		e := t.upsert(id, Resource)
+		e.TypeID = intOr(params, 5)
+		e.Tier = intOr(params, 7)
+		e.Size = intOr(params, 10)
+		e.Enchant = clampEnchant(intOr(params, 11))
+		e.Name = resourceFamily(e.TypeID)
+		if mobile, ok := intParam(params, 6); ok && mobile != 65535 && mobile != -1 {
+			// The type number is wrong for living resources; the tier holds.
+			e.Name = "Living resource"
+		}
+		setPos(e, params[8])
+
+	case eventcodes.NewSimpleHarvestableObjectList:
+		t.addResourceList(params)
+
+	case eventcodes.HarvestableChangeState:
+		e := t.byID[id]
+		if e == nil || e.Kind != Resource {
+			return
+		}
+		size, ok := intParam(params, 1)
+		if !ok {
+			delete(t.byID, id) // depleted
+			return
+		}
+		e.Size = int(size)
+		if enchant, ok := intParam(params, 2); ok {
+			e.Enchant = clampEnchant(int(enchant))
+		}
+		e.LastSeen = t.now()
+
+	case eventcodes.NewRandomDungeonExit:
+		e := t.upsert(id, Dungeon)
+		e.Name = stringParam(params, 3)
+		if e.Name == "" {
+			e.Name = stringParam(params, 15)
+		}
+		e.Enchant = clampEnchant(intOr(params, 8))
+		setPos(e, params[1])
+
+	case eventcodes.NewLootChest:
+		e := t.upsert(id, Chest)
+		e.Name = stringParam(params, 3)
+		if strings.Contains(strings.ToLower(e.Name), "mist") {
+			e.Name = stringParam(params, 4)
+		}
+		setPos(e, params[1])
+	}
+}
+
+// HandleRequest tracks the local player from Move requests.
+func (t *Tracker) HandleRequest(params map[byte]any) {
+	if photon.AlbionCode(params, 253) != operationcodes.Move {
+		return
+	}
+	if x, y, ok := position(params[1]); ok {
+		t.mu.Lock()
+		t.x, t.y = x, y
+		t.mu.Unlock()
+	}
+}
+
+// HandleResponse resets the tracker when the player changes cluster.
+func (t *Tracker) HandleResponse(params map[byte]any) {
+	switch photon.AlbionCode(params, 253) {
+	case operationcodes.ChangeCluster:
+		cluster := stringParam(params, 0)
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if cluster != "" && cluster != t.cluster {
+			t.reset(cluster)
+		}
+	case operationcodes.Join:
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		cluster := stringParam(params, 8)
+		if cluster == "" {
+			cluster = t.cluster
+		}
+		t.reset(cluster)
+		if x, y, ok := position(params[9]); ok {
+			t.x, t.y = x, y
+		}
+	}
+}
+
+// Cluster is the current cluster, "" before the first change or join.
+func (t *Tracker) Cluster() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.cluster
+}
+
+// Snapshot copies the tracked entities, sorted by ID.
+func (t *Tracker) Snapshot() Snapshot {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := Snapshot{Cluster: t.cluster, X: t.x, Y: t.y, Entities: make([]Entity, 0, len(t.byID))}
+	for _, e := range t.byID {
+		out.Entities = append(out.Entities, *e)
+	}
+	sort.Slice(out.Entities, func(i, j int) bool { return out.Entities[i].ID < out.Entities[j].ID })
+	return out
+}
+
+func (t *Tracker) reset(cluster string) {
+	t.cluster = cluster
+	t.x, t.y = 0, 0
+	clear(t.byID)
+}
+
+// upsert returns the entity for id, creating it as kind. A kind mismatch
+// means the game reused the id, so the old entry is replaced. Over
+// MaxEntities the returned entity is detached and discarded.
+func (t *Tracker) upsert(id int64, kind Kind) *Entity {
+	now := t.now()
+	if e := t.byID[id]; e != nil && e.Kind == kind {
+		e.LastSeen = now
+		return e
+	}
+	e := &Entity{ID: id, Kind: kind, FirstSeen: now, LastSeen: now}
+	if _, exists := t.byID[id]; exists || len(t.byID) < MaxEntities {
+		t.byID[id] = e
+	}
+	return e
+}
+
+// addResourceList decodes NewSimpleHarvestableObjectList: parallel arrays of
+// ids, type numbers, tiers, flattened x/y pairs and charges. It carries no
+// enchantment; HarvestableChangeState fills it in later.
+func (t *Tracker) addResourceList(params map[byte]any) {
+	ids, types, tiers := numbers(params[0]), numbers(params[1]), numbers(params[2])
+	pos, sizes := numbers(params[3]), numbers(params[4])
+	n := min(len(ids), len(types), len(tiers), len(sizes), len(pos)/2)
+	for i := range n {
+		e := t.upsert(int64(ids[i]), Resource)
+		e.TypeID = int(types[i])
+		e.Tier = int(tiers[i])
+		e.Size = int(sizes[i])
+		e.Name = resourceFamily(e.TypeID)
+		e.X, e.Y, e.HasPos = float32(pos[2*i]), float32(pos[2*i+1]), true
+	}
+}
+
+// resourceFamily maps a harvestable type number to its family, as
+// HarvestablesDatabase.getResourceTypeFromTypeNumber does.
+func resourceFamily(typeNumber int) string {
+	switch {
+	case typeNumber >= 0 && typeNumber <= 5:
+		return "Wood"
+	case typeNumber >= 6 && typeNumber <= 10:
+		return "Rock"
+	case typeNumber >= 11 && typeNumber <= 15:
+		return "Fiber"
+	case typeNumber >= 16 && typeNumber <= 22:
+		return "Hide"
+	case typeNumber >= 23 && typeNumber <= 27:
+		return "Ore"
+	}
+	return "Resource"
+}
+
+func clampEnchant(v int) int {
+	return min(max(v, 0), 4)
+}
+
+func setPos(e *Entity, v any) {
+	if x, y, ok := position(v); ok {
+		e.X, e.Y, e.HasPos = x, y, true
+	}
+}
+
+// position reads an [x, y] pair, sent as a float array or as 8 little-endian
+// bytes.
+func position(v any) (float32, float32, bool) {
+	if b, ok := v.(photon.ByteArray); ok {
+		if len(b) < 8 {
+			return 0, 0, false
+		}
+		x := math.Float32frombits(binary.LittleEndian.Uint32(b[0:4]))
+		y := math.Float32frombits(binary.LittleEndian.Uint32(b[4:8]))
+		return x, y, finite(x) && finite(y)
+	}
+	xy := numbers(v)
+	if len(xy) < 2 {
+		return 0, 0, false
+	}
+	x, y := float32(xy[0]), float32(xy[1])
+	return x, y, finite(x) && finite(y)
+}
+
+func finite(f float32) bool {
+	return !math.IsNaN(float64(f)) && !math.IsInf(float64(f), 0)
+}
+
+func stringParam(params map[byte]any, key byte) string {
+	s, _ := params[key].(string)
+	return s
+}
+
+func intOr(params map[byte]any, key byte) int {
+	v, _ := intParam(params, key)
+	return int(v)
+}
+
+func intParam(params map[byte]any, key byte) (int64, bool) {
+	f, ok := number(params[key])
+	return int64(f), ok
+}
+
+func floatParam(params map[byte]any, key byte) (float32, bool) {
+	f, ok := number(params[key])
+	return float32(f), ok
+}
+
+func number(v any) (float64, bool) {
+	switch n := v.(type) {
+	case byte:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// numbers reads the numeric arrays the deserializer produces.
+func numbers(v any) []float64 {
+	var out []float64
+	switch a := v.(type) {
+	case photon.ByteArray:
+		for _, n := range a {
+			out = append(out, float64(n))
+		}
+	case []int16:
+		for _, n := range a {
+			out = append(out, float64(n))
+		}
+	case []int32:
+		for _, n := range a {
+			out = append(out, float64(n))
+		}
+	case []int64:
+		for _, n := range a {
+			out = append(out, float64(n))
+		}
+	case []float32:
+		for _, n := range a {
+			out = append(out, float64(n))
+		}
+	case []float64:
+		out = append(out, a...)
+	case []any:
+		for _, e := range a {
+			n, ok := number(e)
+			if !ok {
+				return nil
+			}
+			out = append(out, n)
+		}
+	}
+	return out
+}
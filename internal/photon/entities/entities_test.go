@@ -0,0 +1,128 @@
+package entities
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/nospy/albion-openradar/internal/photon"
+	"github.com/nospy/albion-openradar/internal/photon/eventcodes"
+	"github.com/nospy/albion-openradar/internal/photon/operationcodes"
+)
+
+func event(code int16, params map[byte]any) map[byte]any {
+	params[252] = code
+	return params
+}
+
+func byID(s Snapshot) map[int64]Entity {
+	out := make(map[int64]Entity, len(s.Entities))
+	for _, e := range s.Entities {
+		out[e.ID] = e
+	}
+	return out
+}
+
+func TestTrackerSpawnsUpdatesAndLeaves(t *testing.T) {
+	tr := New()
+	tr.HandleEvent(event(eventcodes.NewMob, map[byte]any{
+		0: int32(100), 1: int16(412), 7: []float32{10, 20}, 33: byte(2), 32: "T5_MOB_BOSS",
+	}))
+	tr.HandleEvent(event(eventcodes.NewHarvestableObject, map[byte]any{
+		0: int32(200), 5: byte(12), 6: int16(-1), 7: byte(6), 8: []float32{1, 2}, 10: byte(4), 11: byte(1),
+	}))
+	tr.HandleEvent(event(eventcodes.NewSimpleHarvestableObjectList, map[byte]any{
+		0: []int16{300, 301},
+		1: photon.ByteArray{0, 24},
+		2: photon.ByteArray{4, 8},
+		3: []float32{5, 6, 7, 8},
+		4: photon.ByteArray{3, 9},
+	}))
+	tr.HandleEvent(event(eventcodes.NewCharacter, map[byte]any{
+		0: int32(400), 1: "Someone", 8: "Guild", 51: "ALLY",
+	}))
+	tr.HandleEvent(event(eventcodes.NewLootChest, map[byte]any{
+		0: int32(500), 1: []float32{3, 4}, 3: "MISTS_CHEST", 4: "CHEST_RARE",
+	}))
+	tr.HandleEvent(event(eventcodes.NewRandomDungeonExit, map[byte]any{
+		0: int32(600), 1: []float32{9, 9}, 3: "DUNGEON_SOLO", 8: byte(3),
+	}))
+
+	got := byID(tr.Snapshot())
+	if len(got) != 7 {
+		t.Fatalf("got %d entities, want 7: %+v", len(got), got)
+	}
+	if m := got[100]; m.Kind != Mob || m.TypeID != 412 || m.Enchant != 2 || m.X != 10 || m.Name != "T5_MOB_BOSS" {
+		t.Errorf("mob = %+v", m)
+	}
+	if r := got[200]; r.Name != "Fiber" || r.Tier != 6 || r.Enchant != 1 || r.Size != 4 || !r.HasPos {
+		t.Errorf("resource = %+v", r)
+	}
+	if r := got[301]; r.Name != "Ore" || r.Tier != 8 || r.Size != 9 || r.X != 7 || r.Y != 8 {
+		t.Errorf("listed resource = %+v", r)
+	}
+	if p := got[400]; p.Kind != Player || p.Name != "Someone" || p.Guild != "Guild" || p.Alliance != "ALLY" || p.HasPos {
+		t.Errorf("player = %+v", p)
+	}
+	if c := got[500]; c.Kind != Chest || c.Name != "CHEST_RARE" {
+		t.Errorf("mist chests take their name from parameter 4, got %+v", c)
+	}
+	if d := got[600]; d.Kind != Dungeon || d.Enchant != 3 || d.Name != "DUNGEON_SOLO" {
+		t.Errorf("dungeon = %+v", d)
+	}
+
+	tr.HandleEvent(event(eventcodes.Move, map[byte]any{0: int32(100), 4: float32(11), 5: float32(21)}))
+	tr.HandleEvent(event(eventcodes.MobChangeState, map[byte]any{0: int32(100), 1: byte(9)}))
+	tr.HandleEvent(event(eventcodes.HarvestableChangeState, map[byte]any{0: int32(300), 1: byte(2), 2: byte(3)}))
+	tr.HandleEvent(event(eventcodes.HarvestableChangeState, map[byte]any{0: int32(200)}))
+	tr.HandleEvent(event(eventcodes.Leave, map[byte]any{0: int32(400)}))
+
+	got = byID(tr.Snapshot())
+	if m := got[100]; m.X != 11 || m.Y != 21 || m.Enchant != 4 {
+		t.Errorf("moved mob = %+v, want (11,21) and enchant clamped to 4", m)
+	}
+	if r := got[300]; r.Size != 2 || r.Enchant != 3 {
+		t.Errorf("changed resource = %+v", r)
+	}
+	if _, ok := got[200]; ok {
+		t.Error("a change state without size means depleted")
+	}
+	if _, ok := got[400]; ok {
+		t.Error("Leave must drop the player")
+	}
+}
+
+func TestTrackerResetsOnClusterChange(t *testing.T) {
+	tr := New()
+	pos := make(photon.ByteArray, 8)
+	binary.LittleEndian.PutUint32(pos[0:4], math.Float32bits(-12.5))
+	binary.LittleEndian.PutUint32(pos[4:8], math.Float32bits(40))
+	tr.HandleResponse(map[byte]any{253: int16(operationcodes.Join), 8: "3004", 9: pos})
+	tr.HandleEvent(event(eventcodes.NewMob, map[byte]any{0: int32(1), 1: int16(2)}))
+	tr.HandleRequest(map[byte]any{253: int16(operationcodes.Move), 1: []float32{3, 4}})
+
+	s := tr.Snapshot()
+	if s.Cluster != "3004" || s.X != 3 || s.Y != 4 || len(s.Entities) != 1 {
+		t.Fatalf("snapshot = %+v", s)
+	}
+
+	tr.HandleResponse(map[byte]any{253: int16(operationcodes.ChangeCluster), 0: "3004"})
+	if len(tr.Snapshot().Entities) != 1 {
+		t.Error("same cluster must not reset")
+	}
+	tr.HandleResponse(map[byte]any{253: int16(operationcodes.ChangeCluster), 0: "1000"})
+	s = tr.Snapshot()
+	if s.Cluster != "1000" || len(s.Entities) != 0 {
+		t.Errorf("after a cluster change: %+v", s)
+	}
+}
+
+func TestTrackerIsBounded(t *testing.T) {
+	tr := New()
+	for i := range MaxEntities + 10 {
+		tr.HandleEvent(event(eventcodes.NewMob, map[byte]any{0: int32(i)}))
+	}
+	if n := len(tr.Snapshot().Entities); n != MaxEntities {
+		t.Errorf("tracked %d entities, want %d", n, MaxEntities)
+	}
+}
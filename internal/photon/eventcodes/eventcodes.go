@@ -692,3 +692,697 @@ const (
 	LosingCarriableObjectFinished = 684
 	NotifyPlatformAccountConfirmed = 685
 )
+
+// names keeps the first JS name for each code.
+var names = map[int]string{
+	Unused: "Unused",
+	Leave: "Leave",
+	JoinFinished: "JoinFinished",
+	Move: "Move",
+	Teleport: "Teleport",
+	ChangeEquipment: "ChangeEquipment",
+	HealthUpdate: "HealthUpdate",
+	HealthUpdates: "HealthUpdates",
+	EnergyUpdate: "EnergyUpdate",
+	DamageShieldUpdate: "DamageShieldUpdate",
+	CraftingFocusUpdate: "CraftingFocusUpdate",
+	ActiveSpellEffectsUpdate: "ActiveSpellEffectsUpdate",
+	ResetCooldowns: "ResetCooldowns",
+	Attack: "Attack",
+	CastStart: "CastStart",
+	ChannelingUpdate: "ChannelingUpdate",
+	CastCancel: "CastCancel",
+	CastTimeUpdate: "CastTimeUpdate",
+	CastFinished: "CastFinished",
+	CastSpell: "CastSpell",
+	CastSpells: "CastSpells",
+	CastHit: "CastHit",
+	CastHits: "CastHits",
+	StoredTargetsUpdate: "StoredTargetsUpdate",
+	ChannelingEnded: "ChannelingEnded",
+	AttackBuilding: "AttackBuilding",
+	InventoryPutItem: "InventoryPutItem",
+	InventoryDeleteItem: "InventoryDeleteItem",
+	InventoryState: "InventoryState",
+	NewCharacter: "NewCharacter",
+	NewEquipmentItem: "NewEquipmentItem",
+	NewSiegeBannerItem: "NewSiegeBannerItem",
+	NewSimpleItem: "NewSimpleItem",
+	NewFurnitureItem: "NewFurnitureItem",
+	NewKillTrophyItem: "NewKillTrophyItem",
+	NewJournalItem: "NewJournalItem",
+	NewLaborerItem: "NewLaborerItem",
+	NewEquipmentItemLegendarySoul: "NewEquipmentItemLegendarySoul",
+	NewSimpleHarvestableObject: "NewSimpleHarvestableObject",
+	NewSimpleHarvestableObjectList: "NewSimpleHarvestableObjectList",
+	NewHarvestableObject: "NewHarvestableObject",
+	NewTreasureDestinationObject: "NewTreasureDestinationObject",
+	TreasureDestinationObjectStatus: "TreasureDestinationObjectStatus",
+	CloseTreasureDestinationObject: "CloseTreasureDestinationObject",
+	NewSilverObject: "NewSilverObject",
+	NewBuilding: "NewBuilding",
+	HarvestableChangeState: "HarvestableChangeState",
+	MobChangeState: "MobChangeState",
+	FactionBuildingInfo: "FactionBuildingInfo",
+	CraftBuildingInfo: "CraftBuildingInfo",
+	RepairBuildingInfo: "RepairBuildingInfo",
+	MeldBuildingInfo: "MeldBuildingInfo",
+	ConstructionSiteInfo: "ConstructionSiteInfo",
+	PlayerBuildingInfo: "PlayerBuildingInfo",
+	FarmBuildingInfo: "FarmBuildingInfo",
+	TutorialBuildingInfo: "TutorialBuildingInfo",
+	LaborerObjectInfo: "LaborerObjectInfo",
+	LaborerObjectJobInfo: "LaborerObjectJobInfo",
+	MarketPlaceBuildingInfo: "MarketPlaceBuildingInfo",
+	HarvestStart: "HarvestStart",
+	HarvestCancel: "HarvestCancel",
+	HarvestFinished: "HarvestFinished",
+	TakeSilver: "TakeSilver",
+	RemoveSilver: "RemoveSilver",
+	ActionOnBuildingStart: "ActionOnBuildingStart",
+	ActionOnBuildingCancel: "ActionOnBuildingCancel",
+	ActionOnBuildingFinished: "ActionOnBuildingFinished",
+	ItemRerollQualityFinished: "ItemRerollQualityFinished",
+	InstallResourceStart: "InstallResourceStart",
+	InstallResourceCancel: "InstallResourceCancel",
+	InstallResourceFinished: "InstallResourceFinished",
+	CraftItemFinished: "CraftItemFinished",
+	LogoutCancel: "LogoutCancel",
+	ChatMessage: "ChatMessage",
+	ChatSay: "ChatSay",
+	ChatWhisper: "ChatWhisper",
+	ChatMuted: "ChatMuted",
+	PlayEmote: "PlayEmote",
+	StopEmote: "StopEmote",
+	SystemMessage: "SystemMessage",
+	UtilityTextMessage: "UtilityTextMessage",
+	UpdateMoney: "UpdateMoney",
+	UpdateFame: "UpdateFame",
+	UpdateLearningPoints: "UpdateLearningPoints",
+	UpdateReSpecPoints: "UpdateReSpecPoints",
+	UpdateCurrency: "UpdateCurrency",
+	UpdateFactionStanding: "UpdateFactionStanding",
+	UpdateStanding: "UpdateStanding",
+	Respawn: "Respawn",
+	ServerDebugLog: "ServerDebugLog",
+	CharacterEquipmentChanged: "CharacterEquipmentChanged",
+	RegenerationHealthChanged: "RegenerationHealthChanged",
+	RegenerationEnergyChanged: "RegenerationEnergyChanged",
+	RegenerationMountHealthChanged: "RegenerationMountHealthChanged",
+	RegenerationCraftingChanged: "RegenerationCraftingChanged",
+	RegenerationHealthEnergyComboChanged: "RegenerationHealthEnergyComboChanged",
+	RegenerationPlayerComboChanged: "RegenerationPlayerComboChanged",
+	DurabilityChanged: "DurabilityChanged",
+	NewLoot: "NewLoot",
+	AttachItemContainer: "AttachItemContainer",
+	DetachItemContainer: "DetachItemContainer",
+	InvalidateItemContainer: "InvalidateItemContainer",
+	LockItemContainer: "LockItemContainer",
+	GuildUpdate: "GuildUpdate",
+	GuildPlayerUpdated: "GuildPlayerUpdated",
+	InvitedToGuild: "InvitedToGuild",
+	GuildMemberWorldUpdate: "GuildMemberWorldUpdate",
+	UpdateMatchDetails: "UpdateMatchDetails",
+	ObjectEvent: "ObjectEvent",
+	NewMonolithObject: "NewMonolithObject",
+	MonolithHasBannersPlacedUpdate: "MonolithHasBannersPlacedUpdate",
+	NewOrbObject: "NewOrbObject",
+	NewCastleObject: "NewCastleObject",
+	NewSpellEffectArea: "NewSpellEffectArea",
+	UpdateSpellEffectArea: "UpdateSpellEffectArea",
+	NewChainSpell: "NewChainSpell",
+	UpdateChainSpell: "UpdateChainSpell",
+	NewTreasureChest: "NewTreasureChest",
+	StartMatch: "StartMatch",
+	StartArenaMatchInfos: "StartArenaMatchInfos",
+	EndArenaMatch: "EndArenaMatch",
+	MatchUpdate: "MatchUpdate",
+	ActiveMatchUpdate: "ActiveMatchUpdate",
+	NewMob: "NewMob",
+	DebugAggroInfo: "DebugAggroInfo",
+	DebugVariablesInfo: "DebugVariablesInfo",
+	DebugReputationInfo: "DebugReputationInfo",
+	DebugDiminishingReturnInfo: "DebugDiminishingReturnInfo",
+	DebugSmartClusterQueueInfo: "DebugSmartClusterQueueInfo",
+	ClaimOrbStart: "ClaimOrbStart",
+	ClaimOrbFinished: "ClaimOrbFinished",
+	ClaimOrbCancel: "ClaimOrbCancel",
+	OrbUpdate: "OrbUpdate",
+	OrbClaimed: "OrbClaimed",
+	OrbReset: "OrbReset",
+	NewWarCampObject: "NewWarCampObject",
+	NewMatchLootChestObject: "NewMatchLootChestObject",
+	NewArenaExit: "NewArenaExit",
+	GuildMemberTerritoryUpdate: "GuildMemberTerritoryUpdate",
+	InvitedMercenaryToMatch: "InvitedMercenaryToMatch",
+	ClusterInfoUpdate: "ClusterInfoUpdate",
+	ForcedMovement: "ForcedMovement",
+	ForcedMovementCancel: "ForcedMovementCancel",
+	CharacterStats: "CharacterStats",
+	CharacterStatsKillHistory: "CharacterStatsKillHistory",
+	CharacterStatsDeathHistory: "CharacterStatsDeathHistory",
+	CharacterStatsKnockDownHistory: "CharacterStatsKnockDownHistory",
+	CharacterStatsKnockedDownHistory: "CharacterStatsKnockedDownHistory",
+	GuildStats: "GuildStats",
+	KillHistoryDetails: "KillHistoryDetails",
+	ItemKillHistoryDetails: "ItemKillHistoryDetails",
+	FullAchievementInfo: "FullAchievementInfo",
+	FinishedAchievement: "FinishedAchievement",
+	AchievementProgressInfo: "AchievementProgressInfo",
+	FullAchievementProgressInfo: "FullAchievementProgressInfo",
+	FullTrackedAchievementInfo: "FullTrackedAchievementInfo",
+	FullAutoLearnAchievementInfo: "FullAutoLearnAchievementInfo",
+	QuestGiverQuestOffered: "QuestGiverQuestOffered",
+	QuestGiverDebugInfo: "QuestGiverDebugInfo",
+	ConsoleEvent: "ConsoleEvent",
+	TimeSync: "TimeSync",
+	ChangeAvatar: "ChangeAvatar",
+	ChangeMountSkin: "ChangeMountSkin",
+	GameEvent: "GameEvent",
+	KilledPlayer: "KilledPlayer",
+	Died: "Died",
+	KnockedDown: "KnockedDown",
+	Unconcious: "Unconcious",
+	MatchPlayerJoinedEvent: "MatchPlayerJoinedEvent",
+	MatchPlayerStatsEvent: "MatchPlayerStatsEvent",
+	MatchPlayerStatsCompleteEvent: "MatchPlayerStatsCompleteEvent",
+	MatchTimeLineEventEvent: "MatchTimeLineEventEvent",
+	MatchNewCombatRound: "MatchNewCombatRound",
+	MatchEndCombatRound: "MatchEndCombatRound",
+	MatchPlayerMainGearStatsEvent: "MatchPlayerMainGearStatsEvent",
+	MatchPlayerChangedAvatarEvent: "MatchPlayerChangedAvatarEvent",
+	InvitationPlayerTrade: "InvitationPlayerTrade",
+	PlayerTradeStart: "PlayerTradeStart",
+	PlayerTradeCancel: "PlayerTradeCancel",
+	PlayerTradeUpdate: "PlayerTradeUpdate",
+	PlayerTradeFinished: "PlayerTradeFinished",
+	PlayerTradeAcceptChange: "PlayerTradeAcceptChange",
+	MiniMapPing: "MiniMapPing",
+	MarketPlaceNotification: "MarketPlaceNotification",
+	DuellingChallengePlayer: "DuellingChallengePlayer",
+	NewDuellingPost: "NewDuellingPost",
+	DuelStarted: "DuelStarted",
+	DuelEnded: "DuelEnded",
+	DuelDenied: "DuelDenied",
+	DuelRequestCanceled: "DuelRequestCanceled",
+	DuelLeftArea: "DuelLeftArea",
+	DuelReEnteredArea: "DuelReEnteredArea",
+	NewRealEstate: "NewRealEstate",
+	MiniMapOwnedBuildingsPositions: "MiniMapOwnedBuildingsPositions",
+	RealEstateListUpdate: "RealEstateListUpdate",
+	GuildLogoUpdate: "GuildLogoUpdate",
+	GuildLogoChanged: "GuildLogoChanged",
+	PlaceableObjectPlace: "PlaceableObjectPlace",
+	PlaceableObjectPlaceCancel: "PlaceableObjectPlaceCancel",
+	FurnitureObjectBuffProviderInfo: "FurnitureObjectBuffProviderInfo",
+	FurnitureObjectCheatProviderInfo: "FurnitureObjectCheatProviderInfo",
+	FarmableObjectInfo: "FarmableObjectInfo",
+	NewUnreadMails: "NewUnreadMails",
+	MailOperationPossible: "MailOperationPossible",
+	GuildLogoObjectUpdate: "GuildLogoObjectUpdate",
+	StartLogout: "StartLogout",
+	NewChatChannels: "NewChatChannels",
+	JoinedChatChannel: "JoinedChatChannel",
+	LeftChatChannel: "LeftChatChannel",
+	RemovedChatChannel: "RemovedChatChannel",
+	AccessStatus: "AccessStatus",
+	Mounted: "Mounted",
+	MountStart: "MountStart",
+	MountCancel: "MountCancel",
+	NewTravelpoint: "NewTravelpoint",
+	NewIslandAccessPoint: "NewIslandAccessPoint",
+	NewExit: "NewExit",
+	UpdateHome: "UpdateHome",
+	UpdateChatSettings: "UpdateChatSettings",
+	ResurrectionOffer: "ResurrectionOffer",
+	ResurrectionReply: "ResurrectionReply",
+	LootEquipmentChanged: "LootEquipmentChanged",
+	UpdateUnlockedGuildLogos: "UpdateUnlockedGuildLogos",
+	UpdateUnlockedAvatars: "UpdateUnlockedAvatars",
+	UpdateUnlockedAvatarRings: "UpdateUnlockedAvatarRings",
+	UpdateUnlockedBuildings: "UpdateUnlockedBuildings",
+	NewIslandManagement: "NewIslandManagement",
+	NewTeleportStone: "NewTeleportStone",
+	Cloak: "Cloak",
+	PartyInvitation: "PartyInvitation",
+	PartyJoinRequest: "PartyJoinRequest",
+	PartyJoined: "PartyJoined",
+	PartyDisbanded: "PartyDisbanded",
+	PartyPlayerJoined: "PartyPlayerJoined",
+	PartyChangedOrder: "PartyChangedOrder",
+	PartyPlayerLeft: "PartyPlayerLeft",
+	PartyLeaderChanged: "PartyLeaderChanged",
+	PartyLootSettingChangedPlayer: "PartyLootSettingChangedPlayer",
+	PartySilverGained: "PartySilverGained",
+	PartyPlayerUpdated: "PartyPlayerUpdated",
+	PartyInvitationAnswer: "PartyInvitationAnswer",
+	PartyJoinRequestAnswer: "PartyJoinRequestAnswer",
+	PartyMarkedObjectsUpdated: "PartyMarkedObjectsUpdated",
+	PartyOnClusterPartyJoined: "PartyOnClusterPartyJoined",
+	PartySetRoleFlag: "PartySetRoleFlag",
+	PartyInviteOrJoinPlayerEquipmentInfo: "PartyInviteOrJoinPlayerEquipmentInfo",
+	PartyReadyCheckUpdate: "PartyReadyCheckUpdate",
+	PartyFactionWarfareReinforcementSettingChangedPlayer: "PartyFactionWarfareReinforcementSettingChangedPlayer",
+	SetPartyNavigation: "SetPartyNavigation",
+	EndPartyNavigation: "EndPartyNavigation",
+	SpellCooldownUpdate: "SpellCooldownUpdate",
+	NewHellgateExitPortal: "NewHellgateExitPortal",
+	NewExpeditionExit: "NewExpeditionExit",
+	NewExpeditionNarrator: "NewExpeditionNarrator",
+	ExitEnterStart: "ExitEnterStart",
+	ExitEnterCancel: "ExitEnterCancel",
+	ExitEnterFinished: "ExitEnterFinished",
+	NewQuestGiverObject: "NewQuestGiverObject",
+	FullQuestInfo: "FullQuestInfo",
+	QuestProgressInfo: "QuestProgressInfo",
+	QuestGiverInfoForPlayer: "QuestGiverInfoForPlayer",
+	FullExpeditionInfo: "FullExpeditionInfo",
+	ExpeditionQuestProgressInfo: "ExpeditionQuestProgressInfo",
+	InvitedToExpedition: "InvitedToExpedition",
+	ExpeditionRegistrationInfo: "ExpeditionRegistrationInfo",
+	EnteringExpeditionStart: "EnteringExpeditionStart",
+	EnteringExpeditionCancel: "EnteringExpeditionCancel",
+	RewardGranted: "RewardGranted",
+	ArenaRegistrationInfo: "ArenaRegistrationInfo",
+	EnteringArenaStart: "EnteringArenaStart",
+	EnteringArenaCancel: "EnteringArenaCancel",
+	EnteringArenaLockStart: "EnteringArenaLockStart",
+	EnteringArenaLockCancel: "EnteringArenaLockCancel",
+	InvitedToArenaMatch: "InvitedToArenaMatch",
+	UsingHellgateShrine: "UsingHellgateShrine",
+	EnteringHellgateLockStart: "EnteringHellgateLockStart",
+	EnteringHellgateLockCancel: "EnteringHellgateLockCancel",
+	PlayerCounts: "PlayerCounts",
+	InCombatStateUpdate: "InCombatStateUpdate",
+	OtherGrabbedLoot: "OtherGrabbedLoot",
+	TreasureChestUsingStart: "TreasureChestUsingStart",
+	TreasureChestUsingFinished: "TreasureChestUsingFinished",
+	TreasureChestUsingCancel: "TreasureChestUsingCancel",
+	TreasureChestUsingOpeningComplete: "TreasureChestUsingOpeningComplete",
+	TreasureChestForceCloseInventory: "TreasureChestForceCloseInventory",
+	LocalTreasuresUpdate: "LocalTreasuresUpdate",
+	LootChestSpawnpointsUpdate: "LootChestSpawnpointsUpdate",
+	PremiumChanged: "PremiumChanged",
+	PremiumExtended: "PremiumExtended",
+	PremiumLifeTimeRewardGained: "PremiumLifeTimeRewardGained",
+	GoldPurchased: "GoldPurchased",
+	LaborerGotUpgraded: "LaborerGotUpgraded",
+	JournalGotFull: "JournalGotFull",
+	JournalFillError: "JournalFillError",
+	FriendRequest: "FriendRequest",
+	FriendRequestInfos: "FriendRequestInfos",
+	FriendInfos: "FriendInfos",
+	FriendRequestAnswered: "FriendRequestAnswered",
+	FriendOnlineStatus: "FriendOnlineStatus",
+	FriendRequestCanceled: "FriendRequestCanceled",
+	FriendRemoved: "FriendRemoved",
+	FriendUpdated: "FriendUpdated",
+	PartyLootItems: "PartyLootItems",
+	PartyLootItemsRemoved: "PartyLootItemsRemoved",
+	PartyLootItemTypesRemoved: "PartyLootItemTypesRemoved",
+	ReputationUpdate: "ReputationUpdate",
+	DefenseUnitAttackBegin: "DefenseUnitAttackBegin",
+	DefenseUnitAttackEnd: "DefenseUnitAttackEnd",
+	DefenseUnitAttackDamage: "DefenseUnitAttackDamage",
+	UnrestrictedPvpZoneUpdate: "UnrestrictedPvpZoneUpdate",
+	UnrestrictedPvpZoneStatus: "UnrestrictedPvpZoneStatus",
+	ReputationImplicationUpdate: "ReputationImplicationUpdate",
+	NewMountObject: "NewMountObject",
+	MountHealthUpdate: "MountHealthUpdate",
+	MountCooldownUpdate: "MountCooldownUpdate",
+	NewExpeditionAgent: "NewExpeditionAgent",
+	NewExpeditionCheckPoint: "NewExpeditionCheckPoint",
+	ExpeditionStartEvent: "ExpeditionStartEvent",
+	VoteEvent: "VoteEvent",
+	RatingEvent: "RatingEvent",
+	NewArenaAgent: "NewArenaAgent",
+	BoostFarmable: "BoostFarmable",
+	UseFunction: "UseFunction",
+	NewPortalEntrance: "NewPortalEntrance",
+	NewPortalExit: "NewPortalExit",
+	NewRandomDungeonExit: "NewRandomDungeonExit",
+	WaitingQueueUpdate: "WaitingQueueUpdate",
+	PlayerMovementRateUpdate: "PlayerMovementRateUpdate",
+	ObserveStart: "ObserveStart",
+	MinimapZergs: "MinimapZergs",
+	MinimapSmartClusterZergs: "MinimapSmartClusterZergs",
+	PaymentTransactions: "PaymentTransactions",
+	PerformanceStatsUpdate: "PerformanceStatsUpdate",
+	OverloadModeUpdate: "OverloadModeUpdate",
+	DebugDrawEvent: "DebugDrawEvent",
+	RecordCameraMove: "RecordCameraMove",
+	RecordStart: "RecordStart",
+	ClaimPowerCrystalStart: "ClaimPowerCrystalStart",
+	ClaimPowerCrystalCancel: "ClaimPowerCrystalCancel",
+	ClaimPowerCrystalReset: "ClaimPowerCrystalReset",
+	ClaimPowerCrystalFinished: "ClaimPowerCrystalFinished",
+	TerritoryClaimStart: "TerritoryClaimStart",
+	TerritoryClaimCancel: "TerritoryClaimCancel",
+	TerritoryClaimFinished: "TerritoryClaimFinished",
+	TerritoryScheduleResult: "TerritoryScheduleResult",
+	TerritoryUpgradeWithPowerCrystalResult: "TerritoryUpgradeWithPowerCrystalResult",
+	ReturningPowerCrystalStart: "ReturningPowerCrystalStart",
+	ReturningPowerCrystalFinished: "ReturningPowerCrystalFinished",
+	UpdateAccountState: "UpdateAccountState",
+	StartDeterministicRoam: "StartDeterministicRoam",
+	GuildFullAccessTagsUpdated: "GuildFullAccessTagsUpdated",
+	GuildAccessTagUpdated: "GuildAccessTagUpdated",
+	GvgSeasonUpdate: "GvgSeasonUpdate",
+	GvgSeasonCheatCommand: "GvgSeasonCheatCommand",
+	SeasonPointsByKillingBooster: "SeasonPointsByKillingBooster",
+	FishingStart: "FishingStart",
+	FishingCast: "FishingCast",
+	FishingCatch: "FishingCatch",
+	FishingFinished: "FishingFinished",
+	FishingCancel: "FishingCancel",
+	NewFloatObject: "NewFloatObject",
+	NewFishingZoneObject: "NewFishingZoneObject",
+	FishingMiniGame: "FishingMiniGame",
+	SteamAchievementCompleted: "SteamAchievementCompleted",
+	UpdatePuppet: "UpdatePuppet",
+	ChangeFlaggingFinished: "ChangeFlaggingFinished",
+	NewOutpostObject: "NewOutpostObject",
+	OutpostUpdate: "OutpostUpdate",
+	OutpostClaimed: "OutpostClaimed",
+	OverChargeEnd: "OverChargeEnd",
+	OverChargeStatus: "OverChargeStatus",
+	PartyFinderFullUpdate: "PartyFinderFullUpdate",
+	PartyFinderUpdate: "PartyFinderUpdate",
+	PartyFinderApplicantsUpdate: "PartyFinderApplicantsUpdate",
+	PartyFinderEquipmentSnapshot: "PartyFinderEquipmentSnapshot",
+	PartyFinderJoinRequestDeclined: "PartyFinderJoinRequestDeclined",
+	NewUnlockedPersonalSeasonRewards: "NewUnlockedPersonalSeasonRewards",
+	PersonalSeasonPointsGained: "PersonalSeasonPointsGained",
+	PersonalSeasonPastSeasonDataEvent: "PersonalSeasonPastSeasonDataEvent",
+	MatchLootChestOpeningStart: "MatchLootChestOpeningStart",
+	MatchLootChestOpeningFinished: "MatchLootChestOpeningFinished",
+	MatchLootChestOpeningCancel: "MatchLootChestOpeningCancel",
+	NotifyCrystalMatchReward: "NotifyCrystalMatchReward",
+	CrystalRealmFeedback: "CrystalRealmFeedback",
+	NewLocationMarker: "NewLocationMarker",
+	NewTutorialBlocker: "NewTutorialBlocker",
+	NewTileSwitch: "NewTileSwitch",
+	NewInformationProvider: "NewInformationProvider",
+	NewDynamicGuildLogo: "NewDynamicGuildLogo",
+	NewDecoration: "NewDecoration",
+	TutorialUpdate: "TutorialUpdate",
+	TriggerHintBox: "TriggerHintBox",
+	RandomDungeonPositionInfo: "RandomDungeonPositionInfo",
+	NewLootChest: "NewLootChest",
+	UpdateLootChest: "UpdateLootChest",
+	LootChestOpened: "LootChestOpened",
+	UpdateLootProtectedByMobsWithMinimapDisplay: "UpdateLootProtectedByMobsWithMinimapDisplay",
+	NewShrine: "NewShrine",
+	UpdateShrine: "UpdateShrine",
+	UpdateRoom: "UpdateRoom",
+	NewMobSoul: "NewMobSoul",
+	NewHellgateShrine: "NewHellgateShrine",
+	UpdateHellgateShrine: "UpdateHellgateShrine",
+	ActivateHellgateExit: "ActivateHellgateExit",
+	MutePlayerUpdate: "MutePlayerUpdate",
+	ShopTileUpdate: "ShopTileUpdate",
+	ShopUpdate: "ShopUpdate",
+	EasyAntiCheatKick: "EasyAntiCheatKick",
+	BattlEyeServerMessage: "BattlEyeServerMessage",
+	UnlockVanityUnlock: "UnlockVanityUnlock",
+	AvatarUnlocked: "AvatarUnlocked",
+	CustomizationChanged: "CustomizationChanged",
+	BaseVaultInfo: "BaseVaultInfo",
+	GuildVaultInfo: "GuildVaultInfo",
+	BankVaultInfo: "BankVaultInfo",
+	RecoveryVaultPlayerInfo: "RecoveryVaultPlayerInfo",
+	RecoveryVaultGuildInfo: "RecoveryVaultGuildInfo",
+	UpdateWardrobe: "UpdateWardrobe",
+	CastlePhaseChanged: "CastlePhaseChanged",
+	GuildAccountLogEvent: "GuildAccountLogEvent",
+	NewHideoutObject: "NewHideoutObject",
+	NewHideoutManagement: "NewHideoutManagement",
+	NewHideoutExit: "NewHideoutExit",
+	InitHideoutAttackStart: "InitHideoutAttackStart",
+	InitHideoutAttackCancel: "InitHideoutAttackCancel",
+	InitHideoutAttackFinished: "InitHideoutAttackFinished",
+	HideoutManagementUpdate: "HideoutManagementUpdate",
+	HideoutUpgradeWithPowerCrystalResult: "HideoutUpgradeWithPowerCrystalResult",
+	IpChanged: "IpChanged",
+	SmartClusterQueueUpdateInfo: "SmartClusterQueueUpdateInfo",
+	SmartClusterQueueActiveInfo: "SmartClusterQueueActiveInfo",
+	SmartClusterQueueKickWarning: "SmartClusterQueueKickWarning",
+	SmartClusterQueueInvite: "SmartClusterQueueInvite",
+	ReceivedGvgSeasonPoints: "ReceivedGvgSeasonPoints",
+	TowerPowerPointUpdate: "TowerPowerPointUpdate",
+	OpenWorldAttackScheduleStart: "OpenWorldAttackScheduleStart",
+	OpenWorldAttackScheduleFinished: "OpenWorldAttackScheduleFinished",
+	OpenWorldAttackScheduleCancel: "OpenWorldAttackScheduleCancel",
+	OpenWorldAttackConquerStart: "OpenWorldAttackConquerStart",
+	OpenWorldAttackConquerFinished: "OpenWorldAttackConquerFinished",
+	OpenWorldAttackConquerCancel: "OpenWorldAttackConquerCancel",
+	OpenWorldAttackConquerStatus: "OpenWorldAttackConquerStatus",
+	OpenWorldAttackStart: "OpenWorldAttackStart",
+	OpenWorldAttackEnd: "OpenWorldAttackEnd",
+	NewRandomResourceBlocker: "NewRandomResourceBlocker",
+	NewHomeObject: "NewHomeObject",
+	HideoutObjectUpdate: "HideoutObjectUpdate",
+	UpdateInfamy: "UpdateInfamy",
+	MinimapPositionMarkers: "MinimapPositionMarkers",
+	NewTunnelExit: "NewTunnelExit",
+	CorruptedDungeonUpdate: "CorruptedDungeonUpdate",
+	CorruptedDungeonStatus: "CorruptedDungeonStatus",
+	CorruptedDungeonInfamy: "CorruptedDungeonInfamy",
+	HellgateRestrictedAreaUpdate: "HellgateRestrictedAreaUpdate",
+	HellgateInfamy: "HellgateInfamy",
+	HellgateStatus: "HellgateStatus",
+	HellgateStatusUpdate: "HellgateStatusUpdate",
+	HellgateSuspense: "HellgateSuspense",
+	ReplaceSpellSlotWithMultiSpell: "ReplaceSpellSlotWithMultiSpell",
+	NewCorruptedShrine: "NewCorruptedShrine",
+	UpdateCorruptedShrine: "UpdateCorruptedShrine",
+	CorruptedShrineUsageStart: "CorruptedShrineUsageStart",
+	CorruptedShrineUsageCancel: "CorruptedShrineUsageCancel",
+	ExitUsed: "ExitUsed",
+	LinkedToObject: "LinkedToObject",
+	LinkToObjectBroken: "LinkToObjectBroken",
+	EstimatedMarketValueUpdate: "EstimatedMarketValueUpdate",
+	StuckCancel: "StuckCancel",
+	DungonEscapeReady: "DungonEscapeReady",
+	FactionWarfareClusterState: "FactionWarfareClusterState",
+	FactionWarfareHasUnclaimedWeeklyReportsEvent: "FactionWarfareHasUnclaimedWeeklyReportsEvent",
+	SimpleFeedback: "SimpleFeedback",
+	SmartClusterQueueSkipClusterError: "SmartClusterQueueSkipClusterError",
+	XignCodeEvent: "XignCodeEvent",
+	BatchUseItemStart: "BatchUseItemStart",
+	BatchUseItemEnd: "BatchUseItemEnd",
+	RedZonePlayerNotification: "RedZonePlayerNotification",
+	RedZoneEventCheatCleanup: "RedZoneEventCheatCleanup",
+	RedZoneFortressEventChestOpened: "RedZoneFortressEventChestOpened",
+	RedZoneWorldEvent: "RedZoneWorldEvent",
+	FactionWarfareStats: "FactionWarfareStats",
+	UpdateFactionBalanceFactors: "UpdateFactionBalanceFactors",
+	FactionEnlistmentChanged: "FactionEnlistmentChanged",
+	UpdateFactionRank: "UpdateFactionRank",
+	FactionWarfareCampaignRewardsUnlocked: "FactionWarfareCampaignRewardsUnlocked",
+	FeaturedFeatureUpdate: "FeaturedFeatureUpdate",
+	NewPowerCrystalObject: "NewPowerCrystalObject",
+	MinimapCrystalPositionMarker: "MinimapCrystalPositionMarker",
+	CarryPowerCrystalUpdate: "CarryPowerCrystalUpdate",
+	PickupPowerCrystalStart: "PickupPowerCrystalStart",
+	PickupPowerCrystalCancel: "PickupPowerCrystalCancel",
+	PickupPowerCrystalFinished: "PickupPowerCrystalFinished",
+	DoSimpleActionStart: "DoSimpleActionStart",
+	DoSimpleActionCancel: "DoSimpleActionCancel",
+	DoSimpleActionFinished: "DoSimpleActionFinished",
+	NotifyGuestAccountVerified: "NotifyGuestAccountVerified",
+	MightAndFavorReceivedEvent: "MightAndFavorReceivedEvent",
+	WeeklyPvpChallengeRewardStateUpdate: "WeeklyPvpChallengeRewardStateUpdate",
+	NewUnlockedPvpSeasonChallengeRewards: "NewUnlockedPvpSeasonChallengeRewards",
+	StaticDungeonEntrancesDungeonEventStatusUpdates: "StaticDungeonEntrancesDungeonEventStatusUpdates",
+	StaticDungeonDungeonValueUpdate: "StaticDungeonDungeonValueUpdate",
+	StaticDungeonEntranceDungeonEventsAborted: "StaticDungeonEntranceDungeonEventsAborted",
+	InAppPurchaseConfirmedGooglePlay: "InAppPurchaseConfirmedGooglePlay",
+	FeatureSwitchInfo: "FeatureSwitchInfo",
+	PartyJoinRequestAborted: "PartyJoinRequestAborted",
+	PartyInviteAborted: "PartyInviteAborted",
+	PartyStartHuntRequest: "PartyStartHuntRequest",
+	PartyStartHuntRequested: "PartyStartHuntRequested",
+	PartyStartHuntRequestAnswer: "PartyStartHuntRequestAnswer",
+	PartyPlayerLeaveScheduled: "PartyPlayerLeaveScheduled",
+	GuildInviteDeclined: "GuildInviteDeclined",
+	CancelMultiSpellSlots: "CancelMultiSpellSlots",
+	NewVisualEventObject: "NewVisualEventObject",
+	CastleClaimProgress: "CastleClaimProgress",
+	CastleClaimProgressLogo: "CastleClaimProgressLogo",
+	TownPortalUpdateState: "TownPortalUpdateState",
+	TownPortalFailed: "TownPortalFailed",
+	ConsumableVanityChargesAdded: "ConsumableVanityChargesAdded",
+	FestivitiesUpdate: "FestivitiesUpdate",
+	NewBannerObject: "NewBannerObject",
+	NewMistsImmediateReturnExit: "NewMistsImmediateReturnExit",
+	MistsPlayerJoinedInfo: "MistsPlayerJoinedInfo",
+	NewMistsStaticEntrance: "NewMistsStaticEntrance",
+	NewMistsOpenWorldExit: "NewMistsOpenWorldExit",
+	NewTunnelExitTemp: "NewTunnelExitTemp",
+	NewMistsWispSpawn: "NewMistsWispSpawn",
+	MistsWispSpawnStateChange: "MistsWispSpawnStateChange",
+	NewMistsCityEntrance: "NewMistsCityEntrance",
+	NewMistsCityRoadsEntrance: "NewMistsCityRoadsEntrance",
+	MistsCityRoadsEntrancePartyStateUpdate: "MistsCityRoadsEntrancePartyStateUpdate",
+	MistsCityRoadsEntranceClearStateForParty: "MistsCityRoadsEntranceClearStateForParty",
+	MistsEntranceDataChanged: "MistsEntranceDataChanged",
+	NewCagedObject: "NewCagedObject",
+	CagedObjectStateUpdated: "CagedObjectStateUpdated",
+	EntrancePartyBindingCreated: "EntrancePartyBindingCreated",
+	EntrancePartyBindingCleared: "EntrancePartyBindingCleared",
+	EntrancePartyBindingInfos: "EntrancePartyBindingInfos",
+	NewMistsBorderExit: "NewMistsBorderExit",
+	NewMistsDungeonExit: "NewMistsDungeonExit",
+	LocalQuestInfos: "LocalQuestInfos",
+	LocalQuestStarted: "LocalQuestStarted",
+	LocalQuestActive: "LocalQuestActive",
+	LocalQuestInactive: "LocalQuestInactive",
+	LocalQuestProgressUpdate: "LocalQuestProgressUpdate",
+	NewUnrestrictedPvpZone: "NewUnrestrictedPvpZone",
+	TemporaryFlaggingStatusUpdate: "TemporaryFlaggingStatusUpdate",
+	SpellTestPerformanceUpdate: "SpellTestPerformanceUpdate",
+	Transformation: "Transformation",
+	TransformationEnd: "TransformationEnd",
+	UpdateTrustlevel: "UpdateTrustlevel",
+	RevealHiddenTimeStamps: "RevealHiddenTimeStamps",
+	ModifyItemTraitFinished: "ModifyItemTraitFinished",
+	RerollItemTraitValueFinished: "RerollItemTraitValueFinished",
+	HuntQuestProgressInfo: "HuntQuestProgressInfo",
+	HuntStarted: "HuntStarted",
+	HuntFinished: "HuntFinished",
+	HuntAborted: "HuntAborted",
+	HuntMissionStepStateUpdate: "HuntMissionStepStateUpdate",
+	NewHuntTrack: "NewHuntTrack",
+	HuntMissionUpdate: "HuntMissionUpdate",
+	HuntQuestMissionProgressUpdate: "HuntQuestMissionProgressUpdate",
+	HuntTrackUsed: "HuntTrackUsed",
+	HuntTrackUseableAgain: "HuntTrackUseableAgain",
+	MinimapHuntTrackMarkers: "MinimapHuntTrackMarkers",
+	NoTracksFound: "NoTracksFound",
+	HuntQuestAborted: "HuntQuestAborted",
+	InteractWithTrackStart: "InteractWithTrackStart",
+	InteractWithTrackCancel: "InteractWithTrackCancel",
+	InteractWithTrackFinished: "InteractWithTrackFinished",
+	NewDynamicCompound: "NewDynamicCompound",
+	LegendaryItemDestroyed: "LegendaryItemDestroyed",
+	AttunementInfo: "AttunementInfo",
+	TerritoryClaimRaidedRawEnergyCrystalResult: "TerritoryClaimRaidedRawEnergyCrystalResult",
+	CarriedObjectExpiryWarning: "CarriedObjectExpiryWarning",
+	CarriedObjectExpired: "CarriedObjectExpired",
+	TerritoryRaidStart: "TerritoryRaidStart",
+	TerritoryRaidCancel: "TerritoryRaidCancel",
+	TerritoryRaidFinished: "TerritoryRaidFinished",
+	TerritoryRaidResult: "TerritoryRaidResult",
+	TerritoryMonolithActiveRaidStatus: "TerritoryMonolithActiveRaidStatus",
+	TerritoryMonolithActiveRaidCancelled: "TerritoryMonolithActiveRaidCancelled",
+	MonolithEnergyStorageUpdate: "MonolithEnergyStorageUpdate",
+	MonolithNextScheduledOpenWorldAttackUpdate: "MonolithNextScheduledOpenWorldAttackUpdate",
+	MonolithProtectedBuildingsDamageReductionUpdate: "MonolithProtectedBuildingsDamageReductionUpdate",
+	NewBuildingBaseEvent: "NewBuildingBaseEvent",
+	NewFortificationBuilding: "NewFortificationBuilding",
+	NewCastleGateBuilding: "NewCastleGateBuilding",
+	BuildingDurabilityUpdate: "BuildingDurabilityUpdate",
+	MonolithFortificationPointsUpdate: "MonolithFortificationPointsUpdate",
+	FortificationBuildingUpgradeInfo: "FortificationBuildingUpgradeInfo",
+	FortificationBuildingsDamageStateUpdate: "FortificationBuildingsDamageStateUpdate",
+	SiegeNotificationEvent: "SiegeNotificationEvent",
+	UpdateEnemyWarBannerActive: "UpdateEnemyWarBannerActive",
+	TerritoryAnnouncePlayerEjection: "TerritoryAnnouncePlayerEjection",
+	CastleGateSwitchUseStarted: "CastleGateSwitchUseStarted",
+	CastleGateSwitchUseFinished: "CastleGateSwitchUseFinished",
+	FortificationBuildingWillDowngrade: "FortificationBuildingWillDowngrade",
+	BotCommand: "BotCommand",
+	JournalAchievementProgressUpdate: "JournalAchievementProgressUpdate",
+	JournalClaimableRewardUpdate: "JournalClaimableRewardUpdate",
+	KeySync: "KeySync",
+	LocalQuestAreaGone: "LocalQuestAreaGone",
+	DynamicTemplate: "DynamicTemplate",
+	DynamicTemplateForcedStateChange: "DynamicTemplateForcedStateChange",
+	NewOutlandsTeleportationPortal: "NewOutlandsTeleportationPortal",
+	NewOutlandsTeleportationReturnPortal: "NewOutlandsTeleportationReturnPortal",
+	OutlandsTeleportationBindingCleared: "OutlandsTeleportationBindingCleared",
+	OutlandsTeleportationReturnPortalUpdateEvent: "OutlandsTeleportationReturnPortalUpdateEvent",
+	PlayerUsedOutlandsTeleportationPortal: "PlayerUsedOutlandsTeleportationPortal",
+	EncumberedRestricted: "EncumberedRestricted",
+	NewPiledObject: "NewPiledObject",
+	PiledObjectStateChanged: "PiledObjectStateChanged",
+	NewSmugglerCrateDeliveryStation: "NewSmugglerCrateDeliveryStation",
+	KillRewardedNoFame: "KillRewardedNoFame",
+	PickupFromPiledObjectStart: "PickupFromPiledObjectStart",
+	PickupFromPiledObjectCancel: "PickupFromPiledObjectCancel",
+	PickupFromPiledObjectReset: "PickupFromPiledObjectReset",
+	PickupFromPiledObjectFinished: "PickupFromPiledObjectFinished",
+	ArmoryActivityChange: "ArmoryActivityChange",
+	NewKillTrophyFurnitureBuilding: "NewKillTrophyFurnitureBuilding",
+	HellDungeonsPlayerJoinedInfo: "HellDungeonsPlayerJoinedInfo",
+	NewTileSwitchTrigger: "NewTileSwitchTrigger",
+	NewMultiRewardObject: "NewMultiRewardObject",
+	NewHellDungeonSoulShrineObject: "NewHellDungeonSoulShrineObject",
+	HellDungeonSoulShrineStateUpdate: "HellDungeonSoulShrineStateUpdate",
+	NewResurrectionShrine: "NewResurrectionShrine",
+	UpdateResurrectionShrine: "UpdateResurrectionShrine",
+	StandTimeFinished: "StandTimeFinished",
+	EpicAchievementAndStatsUpdate: "EpicAchievementAndStatsUpdate",
+	SpectateTargetAfterDeathUpdate: "SpectateTargetAfterDeathUpdate",
+	SpectateTargetAfterDeathEnded: "SpectateTargetAfterDeathEnded",
+	NewHellDungeonUpwardExit: "NewHellDungeonUpwardExit",
+	NewHellDungeonSoulExit: "NewHellDungeonSoulExit",
+	NewHellDungeonDownwardExit: "NewHellDungeonDownwardExit",
+	NewHellDungeonChestExit: "NewHellDungeonChestExit",
+	NewCorruptedStaticEntrance: "NewCorruptedStaticEntrance",
+	NewHellDungeonStaticEntrance: "NewHellDungeonStaticEntrance",
+	UpdateHellDungeonStaticEntranceState: "UpdateHellDungeonStaticEntranceState",
+	DebugTriggerHellDungeonShutdownStart: "DebugTriggerHellDungeonShutdownStart",
+	FullJournalQuestInfo: "FullJournalQuestInfo",
+	JournalQuestProgressInfo: "JournalQuestProgressInfo",
+	NewHellDungeonRoomShrineObject: "NewHellDungeonRoomShrineObject",
+	HellDungeonRoomShrineStateUpdate: "HellDungeonRoomShrineStateUpdate",
+	SimpleBehaviourBuildingStateUpdate: "SimpleBehaviourBuildingStateUpdate",
+	SetTimeScaling: "SetTimeScaling",
+	StopTimeScaling: "StopTimeScaling",
+	KeyValidation: "KeyValidation",
+	PlayerJoinMapMarkerTimerStates: "PlayerJoinMapMarkerTimerStates",
+	NewMapMarkerTimer: "NewMapMarkerTimer",
+	RemoveMapMarkerTimer: "RemoveMapMarkerTimer",
+	NewFactionFortressObject: "NewFactionFortressObject",
+	FactionFortressAnnouncePlayerEjection: "FactionFortressAnnouncePlayerEjection",
+	RewardFactionWarfareSupply: "RewardFactionWarfareSupply",
+	FactionCaptureAreaProgressUpdate: "FactionCaptureAreaProgressUpdate",
+	FactionFortressClaimed: "FactionFortressClaimed",
+	FactionFortressWeaponCachesSpawned: "FactionFortressWeaponCachesSpawned",
+	FactionFortressWeaponCacheClaimed: "FactionFortressWeaponCacheClaimed",
+	FactionFortressFightStateUpdate: "FactionFortressFightStateUpdate",
+	FactionFortressCutoffFightStateUpdate: "FactionFortressCutoffFightStateUpdate",
+	FactionFortressFightEnded: "FactionFortressFightEnded",
+	NewFactionWarfarePortal: "NewFactionWarfarePortal",
+	FactionPortalTargetUpdate: "FactionPortalTargetUpdate",
+	FactionFortressFightStartedInRemoteClusterEvent: "FactionFortressFightStartedInRemoteClusterEvent",
+	FactionFortressFightFinishedInRemoteClusterEvent: "FactionFortressFightFinishedInRemoteClusterEvent",
+	FactionDuchySupplyWarDefensiveVictoryEvent: "FactionDuchySupplyWarDefensiveVictoryEvent",
+	FactionDuchyReconnectedFromCutoffEvent: "FactionDuchyReconnectedFromCutoffEvent",
+	FactionFortressCutoffFightCancelledByClusterOwnerChangeEvent: "FactionFortressCutoffFightCancelledByClusterOwnerChangeEvent",
+	FactionDuchyEnteredCutoffStateEvent: "FactionDuchyEnteredCutoffStateEvent",
+	LeaveProtectionStateUpdate: "LeaveProtectionStateUpdate",
+	RedZoneEventStandings: "RedZoneEventStandings",
+	NewFactionBattleStandardDeliveryStation: "NewFactionBattleStandardDeliveryStation",
+	NewLoreSnippetObject: "NewLoreSnippetObject",
+	LoreSnippetObjectStateUpdate: "LoreSnippetObjectStateUpdate",
+	LoreSnippedClaimed: "LoreSnippedClaimed",
+	LoreSnippetStatesChangedByCheat: "LoreSnippetStatesChangedByCheat",
+	NewTeleporterNode: "NewTeleporterNode",
+	TeleporterNodeStateChanged: "TeleporterNodeStateChanged",
+	TeleporterConnectionsFullStateUpdate: "TeleporterConnectionsFullStateUpdate",
+	TeleporterConnectionStateChanged: "TeleporterConnectionStateChanged",
+	RetrieveCarriableObjectStart: "RetrieveCarriableObjectStart",
+	RetrieveCarriableObjectCancel: "RetrieveCarriableObjectCancel",
+	RetrieveCarriableObjectReset: "RetrieveCarriableObjectReset",
+	RetrieveCarriableObjectFinished: "RetrieveCarriableObjectFinished",
+	LosingCarriableObjectStart: "LosingCarriableObjectStart",
+	LosingCarriableObjectFinished: "LosingCarriableObjectFinished",
+	NotifyPlatformAccountConfirmed: "NotifyPlatformAccountConfirmed",
+}
+
+// Name returns the JS enum name for an Albion code, or "" when the code is
+// not in the enum.
+func Name(code int) string { return names[code] }
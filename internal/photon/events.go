@@ -46,6 +46,24 @@ func PostProcessResponse(resp *OperationResponse) {
 	}
 }
 
+// AlbionCode reads the Albion code carried under key (252 for events, 253
+// for operations), or -1 when it is missing.
+func AlbionCode(params map[byte]any, key byte) int {
+	switch t := params[key].(type) {
+	case byte:
+		return int(t)
+	case int16:
+		return int(t)
+	case int32:
+		return int(t)
+	case int64:
+		return int(t)
+	case int:
+		return t
+	}
+	return -1
+}
+
 // Mobs/resources send mode=3 with 30 bytes; players send mode=3 too but with
 // XOR-encrypted floats that decode to NaN/Inf without the XorCode. Skip those
 // so json.Marshal downstream does not reject the whole WebSocket batch.
@@ -0,0 +1,70 @@
+package photon
+
+import (
+	"net/netip"
+	"slices"
+	"time"
+)
+
+// ServerPort is the UDP port of the Albion game servers.
+const ServerPort = 5056
+
+// Direction is which way a packet travelled between the game client and the
+// game server.
+type Direction uint8
+
+const (
+	DirUnknown Direction = iota
+	DirToServer
+	DirToClient
+)
+
+func (d Direction) String() string {
+	switch d {
+	case DirToServer:
+		return "client->server"
+	case DirToClient:
+		return "server->client"
+	}
+	return "unknown"
+}
+
+// MarshalText lets Direction read as its name in JSON.
+func (d Direction) MarshalText() ([]byte, error) { return []byte(d.String()), nil }
+
+// PacketMeta describes the UDP packet a message arrived in. It is zero when
+// the caller fed the parser bare payloads; a message reassembled from
+// fragments gets the meta of the fragment that completed it.
+type PacketMeta struct {
+	// Time is when the packet was captured.
+	Time      time.Time
+	Direction Direction
+	// Server and Client are the two ends of the flow, set once Direction is
+	// known.
+	Server netip.AddrPort
+	Client netip.AddrPort
+	// SentTime is the Photon header timestamp: the sender's clock in ms,
+	// so the server's own clock on server->client packets.
+	SentTime uint32
+}
+
+// FlowMeta builds the meta of a packet from src to dst captured at t. The
+// end on one of serverPorts is the game server; with neither or both on
+// one, the direction stays unknown.
+func FlowMeta(t time.Time, src, dst netip.AddrPort, serverPorts ...uint16) PacketMeta {
+	meta := PacketMeta{Time: t}
+	srcServer := slices.Contains(serverPorts, src.Port())
+	dstServer := slices.Contains(serverPorts, dst.Port())
+	switch {
+	case srcServer && !dstServer:
+		meta.Direction, meta.Server, meta.Client = DirToClient, src, dst
+	case dstServer && !srcServer:
+		meta.Direction, meta.Server, meta.Client = DirToServer, dst, src
+	}
+	return meta
+}
+
+// ServerTime is the server's clock on a server->client packet.
+func (m PacketMeta) ServerTime() (uint32, bool) {
+	return m.SentTime, m.Direction == DirToClient
+}
@@ -0,0 +1,58 @@
+package photon
+
+import (
+	"encoding/binary"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlowMeta_Direction(t *testing.T) {
+	server := netip.MustParseAddrPort("5.188.125.10:5056")
+	client := netip.MustParseAddrPort("192.168.1.2:50000")
+	now := time.Now()
+
+	down := FlowMeta(now, server, client, ServerPort)
+	require.Equal(t, DirToClient, down.Direction)
+	require.Equal(t, server, down.Server)
+	require.Equal(t, client, down.Client)
+
+	up := FlowMeta(now, client, server, ServerPort)
+	require.Equal(t, DirToServer, up.Direction)
+	require.Equal(t, server, up.Server)
+
+	both := FlowMeta(now, server, server, ServerPort)
+	require.Equal(t, DirUnknown, both.Direction)
+	require.False(t, both.Server.IsValid())
+	require.Equal(t, now, both.Time)
+}
+
+func TestPhotonParser_ReceivePacketMeta_StampsEveryMessage(t *testing.T) {
+	var got *EventData
+	p := NewPhotonParser(func(e *EventData) { got = e }, nil, nil)
+	pkt := buildReliableEventPacket()
+	binary.BigEndian.PutUint32(pkt[4:], 123456)
+	meta := FlowMeta(time.UnixMilli(1700000000000),
+		netip.MustParseAddrPort("5.188.125.10:5056"), netip.MustParseAddrPort("192.168.1.2:50000"), ServerPort)
+
+	require.True(t, p.ReceivePacketMeta(pkt, meta))
+	require.NotNil(t, got)
+	require.Equal(t, meta.Time, got.Meta.Time)
+	require.Equal(t, DirToClient, got.Meta.Direction)
+	serverTime, ok := got.Meta.ServerTime()
+	require.True(t, ok)
+	require.Equal(t, uint32(123456), serverTime)
+}
+
+func TestPhotonParser_Fragment_TakesTheCompletingPacketsMeta(t *testing.T) {
+	var got *EventData
+	p := NewPhotonParser(func(e *EventData) { got = e }, nil, nil)
+	packets := buildFragmentedEventPackets(2)
+	first, last := time.UnixMilli(1000), time.UnixMilli(2000)
+	p.ReceivePacketMeta(packets[0], PacketMeta{Time: first})
+	p.ReceivePacketMeta(packets[1], PacketMeta{Time: last})
+	require.NotNil(t, got)
+	require.Equal(t, last, got.Meta.Time)
+}
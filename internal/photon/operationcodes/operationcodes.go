@@ -550,3 +550,555 @@ const (
 	SetPartyNavigation = 542
 	EndPartyNavigation = 543
 )
+
+// names keeps the first JS name for each code.
+var names = map[int]string{
+	Unused: "Unused",
+	Ping: "Ping",
+	Join: "Join",
+	VersionedOperation: "VersionedOperation",
+	CreateAccount: "CreateAccount",
+	Login: "Login",
+	CreateGuestAccount: "CreateGuestAccount",
+	CreatePlatformOnlyAccount: "CreatePlatformOnlyAccount",
+	SendCrashLog: "SendCrashLog",
+	SendTraceRoute: "SendTraceRoute",
+	SendVfxStats: "SendVfxStats",
+	SendGamePingInfo: "SendGamePingInfo",
+	CreateCharacter: "CreateCharacter",
+	DeleteCharacter: "DeleteCharacter",
+	SelectCharacter: "SelectCharacter",
+	AcceptPopups: "AcceptPopups",
+	RedeemKeycode: "RedeemKeycode",
+	GetGameServerByCluster: "GetGameServerByCluster",
+	GetShopPurchaseUrl: "GetShopPurchaseUrl",
+	GetReferralSeasonDetails: "GetReferralSeasonDetails",
+	GetReferralLink: "GetReferralLink",
+	GetShopTilesForCategory: "GetShopTilesForCategory",
+	Move: "Move",
+	AttackStart: "AttackStart",
+	CastStart: "CastStart",
+	CastCancel: "CastCancel",
+	TerminateToggleSpell: "TerminateToggleSpell",
+	ChannelingCancel: "ChannelingCancel",
+	AttackBuildingStart: "AttackBuildingStart",
+	InventoryDestroyItem: "InventoryDestroyItem",
+	InventoryMoveItem: "InventoryMoveItem",
+	InventoryRecoverItem: "InventoryRecoverItem",
+	InventoryRecoverAllItems: "InventoryRecoverAllItems",
+	InventorySplitStack: "InventorySplitStack",
+	InventorySplitStackInto: "InventorySplitStackInto",
+	InventoryStack: "InventoryStack",
+	InventoryReorder: "InventoryReorder",
+	InventoryDropAll: "InventoryDropAll",
+	InventoryAddToStacks: "InventoryAddToStacks",
+	InventoryMoveGivenItems: "InventoryMoveGivenItems",
+	GetClusterData: "GetClusterData",
+	ChangeCluster: "ChangeCluster",
+	ConsoleCommand: "ConsoleCommand",
+	ChatMessage: "ChatMessage",
+	ReportClientError: "ReportClientError",
+	RegisterToObject: "RegisterToObject",
+	UnRegisterFromObject: "UnRegisterFromObject",
+	CraftBuildingChangeSettings: "CraftBuildingChangeSettings",
+	CraftBuildingTakeMoney: "CraftBuildingTakeMoney",
+	RepairBuildingChangeSettings: "RepairBuildingChangeSettings",
+	RepairBuildingTakeMoney: "RepairBuildingTakeMoney",
+	ActionBuildingChangeSettings: "ActionBuildingChangeSettings",
+	HarvestStart: "HarvestStart",
+	HarvestCancel: "HarvestCancel",
+	TakeSilver: "TakeSilver",
+	ActionOnBuildingStart: "ActionOnBuildingStart",
+	ActionOnBuildingCancel: "ActionOnBuildingCancel",
+	InstallResourceStart: "InstallResourceStart",
+	InstallResourceCancel: "InstallResourceCancel",
+	InstallSilver: "InstallSilver",
+	BuildingFillNutrition: "BuildingFillNutrition",
+	BuildingChangeRenovationState: "BuildingChangeRenovationState",
+	BuildingBuySkin: "BuildingBuySkin",
+	BuildingClaim: "BuildingClaim",
+	BuildingGiveup: "BuildingGiveup",
+	BuildingNutritionSilverStorageDeposit: "BuildingNutritionSilverStorageDeposit",
+	BuildingNutritionSilverStorageWithdraw: "BuildingNutritionSilverStorageWithdraw",
+	BuildingNutritionSilverRewardSet: "BuildingNutritionSilverRewardSet",
+	ConstructionSiteCreate: "ConstructionSiteCreate",
+	PlaceableObjectPlace: "PlaceableObjectPlace",
+	PlaceableObjectPlaceCancel: "PlaceableObjectPlaceCancel",
+	PlaceableObjectPickup: "PlaceableObjectPickup",
+	FurnitureObjectUse: "FurnitureObjectUse",
+	FarmableHarvest: "FarmableHarvest",
+	FarmableFinishGrownItem: "FarmableFinishGrownItem",
+	FarmableDestroy: "FarmableDestroy",
+	FarmableGetProduct: "FarmableGetProduct",
+	FarmableFill: "FarmableFill",
+	TearDownConstructionSite: "TearDownConstructionSite",
+	AuctionCreateOffer: "AuctionCreateOffer",
+	AuctionCreateRequest: "AuctionCreateRequest",
+	AuctionGetOffers: "AuctionGetOffers",
+	AuctionGetRequests: "AuctionGetRequests",
+	AuctionBuyOffer: "AuctionBuyOffer",
+	AuctionAbortAuction: "AuctionAbortAuction",
+	AuctionModifyAuction: "AuctionModifyAuction",
+	AuctionAbortOffer: "AuctionAbortOffer",
+	AuctionAbortRequest: "AuctionAbortRequest",
+	AuctionSellRequest: "AuctionSellRequest",
+	AuctionGetFinishedAuctions: "AuctionGetFinishedAuctions",
+	AuctionGetFinishedAuctionsCount: "AuctionGetFinishedAuctionsCount",
+	AuctionFetchAuction: "AuctionFetchAuction",
+	AuctionGetMyOpenOffers: "AuctionGetMyOpenOffers",
+	AuctionGetMyOpenRequests: "AuctionGetMyOpenRequests",
+	AuctionGetMyOpenAuctions: "AuctionGetMyOpenAuctions",
+	AuctionGetItemAverageStats: "AuctionGetItemAverageStats",
+	AuctionGetItemAverageValue: "AuctionGetItemAverageValue",
+	AuctionGetLowestOfferPrices: "AuctionGetLowestOfferPrices",
+	ContainerOpen: "ContainerOpen",
+	ContainerClose: "ContainerClose",
+	ContainerManageSubContainer: "ContainerManageSubContainer",
+	Respawn: "Respawn",
+	Suicide: "Suicide",
+	JoinGuild: "JoinGuild",
+	LeaveGuild: "LeaveGuild",
+	CreateGuild: "CreateGuild",
+	InviteToGuild: "InviteToGuild",
+	DeclineGuildInvitation: "DeclineGuildInvitation",
+	KickFromGuild: "KickFromGuild",
+	InstantJoinGuild: "InstantJoinGuild",
+	DuellingChallengePlayer: "DuellingChallengePlayer",
+	DuellingAcceptChallenge: "DuellingAcceptChallenge",
+	DuellingDenyChallenge: "DuellingDenyChallenge",
+	ChangeClusterTax: "ChangeClusterTax",
+	ClaimTerritory: "ClaimTerritory",
+	GiveUpTerritory: "GiveUpTerritory",
+	ChangeTerritoryAccessRights: "ChangeTerritoryAccessRights",
+	GetMonolithInfo: "GetMonolithInfo",
+	GetClaimInfo: "GetClaimInfo",
+	GetAttackInfo: "GetAttackInfo",
+	GetTerritorySeasonPoints: "GetTerritorySeasonPoints",
+	GetAttackSchedule: "GetAttackSchedule",
+	GetMatches: "GetMatches",
+	GetMatchDetails: "GetMatchDetails",
+	JoinMatch: "JoinMatch",
+	LeaveMatch: "LeaveMatch",
+	GetClusterInstanceInfoForStaticCluster: "GetClusterInstanceInfoForStaticCluster",
+	ChangeChatSettings: "ChangeChatSettings",
+	LogoutStart: "LogoutStart",
+	LogoutCancel: "LogoutCancel",
+	ClaimOrbStart: "ClaimOrbStart",
+	ClaimOrbCancel: "ClaimOrbCancel",
+	MatchLootChestOpeningStart: "MatchLootChestOpeningStart",
+	MatchLootChestOpeningCancel: "MatchLootChestOpeningCancel",
+	DepositToGuildAccount: "DepositToGuildAccount",
+	WithdrawalFromAccount: "WithdrawalFromAccount",
+	ChangeGuildPayUpkeepFlag: "ChangeGuildPayUpkeepFlag",
+	ChangeGuildTax: "ChangeGuildTax",
+	GetMyTerritories: "GetMyTerritories",
+	MorganaCommand: "MorganaCommand",
+	GetServerInfo: "GetServerInfo",
+	SubscribeToCluster: "SubscribeToCluster",
+	AnswerMercenaryInvitation: "AnswerMercenaryInvitation",
+	GetCharacterEquipment: "GetCharacterEquipment",
+	GetCharacterSteamAchievements: "GetCharacterSteamAchievements",
+	GetCharacterStats: "GetCharacterStats",
+	GetKillHistoryDetails: "GetKillHistoryDetails",
+	ReSpecAchievement: "ReSpecAchievement",
+	ChangeAvatar: "ChangeAvatar",
+	GetRankings: "GetRankings",
+	GetRank: "GetRank",
+	GetGvgSeasonRankings: "GetGvgSeasonRankings",
+	GetGvgSeasonRank: "GetGvgSeasonRank",
+	GetGvgSeasonHistoryRankings: "GetGvgSeasonHistoryRankings",
+	GetGvgSeasonGuildMemberHistory: "GetGvgSeasonGuildMemberHistory",
+	KickFromGvGMatch: "KickFromGvGMatch",
+	GetCrystalLeagueDailySeasonPoints: "GetCrystalLeagueDailySeasonPoints",
+	GetChestLogs: "GetChestLogs",
+	GetAccessRightLogs: "GetAccessRightLogs",
+	GetGuildAccountLogs: "GetGuildAccountLogs",
+	GetGuildAccountLogsLargeAmount: "GetGuildAccountLogsLargeAmount",
+	InviteToPlayerTrade: "InviteToPlayerTrade",
+	PlayerTradeCancel: "PlayerTradeCancel",
+	PlayerTradeInvitationAccept: "PlayerTradeInvitationAccept",
+	PlayerTradeAddItem: "PlayerTradeAddItem",
+	PlayerTradeRemoveItem: "PlayerTradeRemoveItem",
+	PlayerTradeAcceptTrade: "PlayerTradeAcceptTrade",
+	PlayerTradeSetSilverOrGold: "PlayerTradeSetSilverOrGold",
+	SendMiniMapPing: "SendMiniMapPing",
+	Stuck: "Stuck",
+	BuyRealEstate: "BuyRealEstate",
+	ClaimRealEstate: "ClaimRealEstate",
+	GiveUpRealEstate: "GiveUpRealEstate",
+	ChangeRealEstateOutline: "ChangeRealEstateOutline",
+	GetMailInfos: "GetMailInfos",
+	GetMailCount: "GetMailCount",
+	ReadMail: "ReadMail",
+	SendNewMail: "SendNewMail",
+	DeleteMail: "DeleteMail",
+	MarkMailUnread: "MarkMailUnread",
+	ClaimAttachmentFromMail: "ClaimAttachmentFromMail",
+	ApplyToGuild: "ApplyToGuild",
+	AnswerGuildApplication: "AnswerGuildApplication",
+	RequestGuildFinderFilteredList: "RequestGuildFinderFilteredList",
+	UpdateGuildRecruitmentInfo: "UpdateGuildRecruitmentInfo",
+	RequestGuildRecruitmentInfo: "RequestGuildRecruitmentInfo",
+	RequestGuildFinderNameSearch: "RequestGuildFinderNameSearch",
+	RequestGuildFinderRecommendedList: "RequestGuildFinderRecommendedList",
+	RegisterChatPeer: "RegisterChatPeer",
+	SendChatMessage: "SendChatMessage",
+	SendModeratorMessage: "SendModeratorMessage",
+	JoinChatChannel: "JoinChatChannel",
+	LeaveChatChannel: "LeaveChatChannel",
+	SendWhisperMessage: "SendWhisperMessage",
+	Say: "Say",
+	PlayEmote: "PlayEmote",
+	StopEmote: "StopEmote",
+	GetClusterMapInfo: "GetClusterMapInfo",
+	AccessRightsChangeSettings: "AccessRightsChangeSettings",
+	Mount: "Mount",
+	MountCancel: "MountCancel",
+	BuyJourney: "BuyJourney",
+	SetSaleStatusForEstate: "SetSaleStatusForEstate",
+	ResolveGuildOrPlayerName: "ResolveGuildOrPlayerName",
+	GetRespawnInfos: "GetRespawnInfos",
+	MakeHome: "MakeHome",
+	LeaveHome: "LeaveHome",
+	ResurrectionReply: "ResurrectionReply",
+	AllianceCreate: "AllianceCreate",
+	AllianceDisband: "AllianceDisband",
+	AllianceGetMemberInfos: "AllianceGetMemberInfos",
+	AllianceInvite: "AllianceInvite",
+	AllianceAnswerInvitation: "AllianceAnswerInvitation",
+	AllianceCancelInvitation: "AllianceCancelInvitation",
+	AllianceKickGuild: "AllianceKickGuild",
+	AllianceLeave: "AllianceLeave",
+	AllianceChangeGoldPaymentFlag: "AllianceChangeGoldPaymentFlag",
+	AllianceGetDetailInfo: "AllianceGetDetailInfo",
+	GetIslandInfos: "GetIslandInfos",
+	BuyMyIsland: "BuyMyIsland",
+	BuyGuildIsland: "BuyGuildIsland",
+	UpgradeMyIsland: "UpgradeMyIsland",
+	UpgradeGuildIsland: "UpgradeGuildIsland",
+	TerritoryFillNutrition: "TerritoryFillNutrition",
+	TeleportBack: "TeleportBack",
+	PartyInvitePlayer: "PartyInvitePlayer",
+	PartyRequestJoin: "PartyRequestJoin",
+	PartyAnswerInvitation: "PartyAnswerInvitation",
+	PartyAnswerJoinRequest: "PartyAnswerJoinRequest",
+	PartyLeave: "PartyLeave",
+	PartyKickPlayer: "PartyKickPlayer",
+	PartyMakeLeader: "PartyMakeLeader",
+	PartyChangeLootSetting: "PartyChangeLootSetting",
+	PartyMarkObject: "PartyMarkObject",
+	PartySetRole: "PartySetRole",
+	PartyChangeFactionWarfareRequestReinforcementsSetting: "PartyChangeFactionWarfareRequestReinforcementsSetting",
+	SetGuildCodex: "SetGuildCodex",
+	ExitEnterStart: "ExitEnterStart",
+	ExitEnterCancel: "ExitEnterCancel",
+	QuestGiverRequest: "QuestGiverRequest",
+	GoldMarketGetBuyOffer: "GoldMarketGetBuyOffer",
+	GoldMarketGetBuyOfferFromSilver: "GoldMarketGetBuyOfferFromSilver",
+	GoldMarketGetSellOffer: "GoldMarketGetSellOffer",
+	GoldMarketGetSellOfferFromSilver: "GoldMarketGetSellOfferFromSilver",
+	GoldMarketBuyGold: "GoldMarketBuyGold",
+	GoldMarketSellGold: "GoldMarketSellGold",
+	GoldMarketCreateSellOrder: "GoldMarketCreateSellOrder",
+	GoldMarketCreateBuyOrder: "GoldMarketCreateBuyOrder",
+	GoldMarketGetInfos: "GoldMarketGetInfos",
+	GoldMarketCancelOrder: "GoldMarketCancelOrder",
+	GoldMarketGetAverageInfo: "GoldMarketGetAverageInfo",
+	TreasureChestUsingStart: "TreasureChestUsingStart",
+	TreasureChestUsingCancel: "TreasureChestUsingCancel",
+	UseLootChest: "UseLootChest",
+	UseShrine: "UseShrine",
+	UseHellgateShrine: "UseHellgateShrine",
+	GetSiegeBannerInfo: "GetSiegeBannerInfo",
+	LaborerStartJob: "LaborerStartJob",
+	LaborerTakeJobLoot: "LaborerTakeJobLoot",
+	LaborerDismiss: "LaborerDismiss",
+	LaborerMove: "LaborerMove",
+	LaborerBuyItem: "LaborerBuyItem",
+	LaborerUpgrade: "LaborerUpgrade",
+	BuyPremium: "BuyPremium",
+	RealEstateGetAuctionData: "RealEstateGetAuctionData",
+	RealEstateBidOnAuction: "RealEstateBidOnAuction",
+	FriendInvite: "FriendInvite",
+	FriendAnswerInvitation: "FriendAnswerInvitation",
+	FriendCancelnvitation: "FriendCancelnvitation",
+	FriendRemove: "FriendRemove",
+	EquipmentItemChangeSpell: "EquipmentItemChangeSpell",
+	ExpeditionRegister: "ExpeditionRegister",
+	ExpeditionRegisterCancel: "ExpeditionRegisterCancel",
+	JoinExpedition: "JoinExpedition",
+	DeclineExpeditionInvitation: "DeclineExpeditionInvitation",
+	VoteStart: "VoteStart",
+	VoteDoVote: "VoteDoVote",
+	RatingDoRate: "RatingDoRate",
+	EnteringExpeditionStart: "EnteringExpeditionStart",
+	EnteringExpeditionCancel: "EnteringExpeditionCancel",
+	ActivateExpeditionCheckPoint: "ActivateExpeditionCheckPoint",
+	ArenaRegister: "ArenaRegister",
+	ArenaAddInvite: "ArenaAddInvite",
+	ArenaRegisterCancel: "ArenaRegisterCancel",
+	ArenaLeave: "ArenaLeave",
+	JoinArenaMatch: "JoinArenaMatch",
+	DeclineArenaInvitation: "DeclineArenaInvitation",
+	EnteringArenaStart: "EnteringArenaStart",
+	EnteringArenaCancel: "EnteringArenaCancel",
+	ArenaCustomMatch: "ArenaCustomMatch",
+	UpdateCharacterStatement: "UpdateCharacterStatement",
+	BoostFarmable: "BoostFarmable",
+	GetStrikeHistory: "GetStrikeHistory",
+	UseFunction: "UseFunction",
+	UsePortalEntrance: "UsePortalEntrance",
+	ResetPortalBinding: "ResetPortalBinding",
+	QueryPortalBinding: "QueryPortalBinding",
+	ClaimPaymentTransaction: "ClaimPaymentTransaction",
+	ChangeUseFlag: "ChangeUseFlag",
+	ClientPerformanceStats: "ClientPerformanceStats",
+	ExtendedHardwareStats: "ExtendedHardwareStats",
+	ClientLowMemoryWarning: "ClientLowMemoryWarning",
+	TerritoryClaimStart: "TerritoryClaimStart",
+	TerritoryClaimCancel: "TerritoryClaimCancel",
+	DeliverCarriableObjectStart: "DeliverCarriableObjectStart",
+	DeliverCarriableObjectCancel: "DeliverCarriableObjectCancel",
+	TerritoryUpgradeWithPowerCrystal: "TerritoryUpgradeWithPowerCrystal",
+	RequestAppStoreProducts: "RequestAppStoreProducts",
+	VerifyProductPurchase: "VerifyProductPurchase",
+	QueryGuildPlayerStats: "QueryGuildPlayerStats",
+	QueryAllianceGuildStats: "QueryAllianceGuildStats",
+	TrackAchievements: "TrackAchievements",
+	SetAchievementsAutoLearn: "SetAchievementsAutoLearn",
+	DepositItemToGuildCurrency: "DepositItemToGuildCurrency",
+	WithdrawalItemFromGuildCurrency: "WithdrawalItemFromGuildCurrency",
+	AuctionSellSpecificItemRequest: "AuctionSellSpecificItemRequest",
+	FishingStart: "FishingStart",
+	FishingCasting: "FishingCasting",
+	FishingCast: "FishingCast",
+	FishingCatch: "FishingCatch",
+	FishingPull: "FishingPull",
+	FishingGiveLine: "FishingGiveLine",
+	FishingFinish: "FishingFinish",
+	FishingCancel: "FishingCancel",
+	CreateGuildAccessTag: "CreateGuildAccessTag",
+	DeleteGuildAccessTag: "DeleteGuildAccessTag",
+	RenameGuildAccessTag: "RenameGuildAccessTag",
+	FlagGuildAccessTagGuildPermission: "FlagGuildAccessTagGuildPermission",
+	AssignGuildAccessTag: "AssignGuildAccessTag",
+	RemoveGuildAccessTagFromPlayer: "RemoveGuildAccessTagFromPlayer",
+	ModifyGuildAccessTagEditors: "ModifyGuildAccessTagEditors",
+	RequestPublicAccessTags: "RequestPublicAccessTags",
+	ChangeAccessTagPublicFlag: "ChangeAccessTagPublicFlag",
+	UpdateGuildAccessTag: "UpdateGuildAccessTag",
+	SteamStartMicrotransaction: "SteamStartMicrotransaction",
+	SteamFinishMicrotransaction: "SteamFinishMicrotransaction",
+	RequestXboxPurchaseIntent: "RequestXboxPurchaseIntent",
+	CloseXboxPurchaseIntent: "CloseXboxPurchaseIntent",
+	SteamIdHasActiveAccount: "SteamIdHasActiveAccount",
+	CheckEmailAccountState: "CheckEmailAccountState",
+	LinkAccountToSteamId: "LinkAccountToSteamId",
+	EpicIdHasActiveAccount: "EpicIdHasActiveAccount",
+	LinkAccountToEpicId: "LinkAccountToEpicId",
+	XboxIdHasActiveAccount: "XboxIdHasActiveAccount",
+	InAppConfirmPaymentGooglePlay: "InAppConfirmPaymentGooglePlay",
+	InAppConfirmPaymentAppleAppStore: "InAppConfirmPaymentAppleAppStore",
+	InAppPurchaseRequest: "InAppPurchaseRequest",
+	InAppPurchaseFailed: "InAppPurchaseFailed",
+	CharacterSubscriptionInfo: "CharacterSubscriptionInfo",
+	AccountSubscriptionInfo: "AccountSubscriptionInfo",
+	BuyGvgSeasonBooster: "BuyGvgSeasonBooster",
+	ChangeFlaggingPrepare: "ChangeFlaggingPrepare",
+	OverCharge: "OverCharge",
+	OverChargeEnd: "OverChargeEnd",
+	RequestTrusted: "RequestTrusted",
+	ChangeGuildLogo: "ChangeGuildLogo",
+	PartyFinderRegisterForUpdates: "PartyFinderRegisterForUpdates",
+	PartyFinderUnregisterForUpdates: "PartyFinderUnregisterForUpdates",
+	PartyFinderEnlistNewPartySearch: "PartyFinderEnlistNewPartySearch",
+	PartyFinderDeletePartySearch: "PartyFinderDeletePartySearch",
+	PartyFinderChangePartySearch: "PartyFinderChangePartySearch",
+	PartyFinderChangeRole: "PartyFinderChangeRole",
+	PartyFinderApplyForGroup: "PartyFinderApplyForGroup",
+	PartyFinderAcceptOrDeclineApplyForGroup: "PartyFinderAcceptOrDeclineApplyForGroup",
+	PartyFinderGetEquipmentSnapshot: "PartyFinderGetEquipmentSnapshot",
+	PartyFinderRegisterApplicants: "PartyFinderRegisterApplicants",
+	PartyFinderUnregisterApplicants: "PartyFinderUnregisterApplicants",
+	PartyFinderFulltextSearch: "PartyFinderFulltextSearch",
+	PartyFinderRequestEquipmentSnapshot: "PartyFinderRequestEquipmentSnapshot",
+	GetPersonalSeasonTrackerData: "GetPersonalSeasonTrackerData",
+	GetPersonalSeasonPastRewardData: "GetPersonalSeasonPastRewardData",
+	UseConsumableFromInventory: "UseConsumableFromInventory",
+	ClaimPersonalSeasonReward: "ClaimPersonalSeasonReward",
+	XignCodeMessageToServer: "XignCodeMessageToServer",
+	BattlEyeMessageToServer: "BattlEyeMessageToServer",
+	SetNextTutorialState: "SetNextTutorialState",
+	AddPlayerToMuteList: "AddPlayerToMuteList",
+	RemovePlayerFromMuteList: "RemovePlayerFromMuteList",
+	ProductShopUserEvent: "ProductShopUserEvent",
+	GetVanityUnlocks: "GetVanityUnlocks",
+	BuyVanityUnlocks: "BuyVanityUnlocks",
+	GetMountSkins: "GetMountSkins",
+	SetMountSkin: "SetMountSkin",
+	SetWardrobe: "SetWardrobe",
+	ChangeCustomization: "ChangeCustomization",
+	ChangePlayerIslandData: "ChangePlayerIslandData",
+	GetGuildChallengePoints: "GetGuildChallengePoints",
+	SmartQueueJoin: "SmartQueueJoin",
+	SmartQueueLeave: "SmartQueueLeave",
+	SmartQueueSelectSpawnCluster: "SmartQueueSelectSpawnCluster",
+	UpgradeHideout: "UpgradeHideout",
+	InitHideoutAttackStart: "InitHideoutAttackStart",
+	InitHideoutAttackCancel: "InitHideoutAttackCancel",
+	HideoutFillNutrition: "HideoutFillNutrition",
+	HideoutGetInfo: "HideoutGetInfo",
+	HideoutGetOwnerInfo: "HideoutGetOwnerInfo",
+	HideoutSetTribute: "HideoutSetTribute",
+	HideoutUpgradeWithPowerCrystal: "HideoutUpgradeWithPowerCrystal",
+	HideoutDeclareHQ: "HideoutDeclareHQ",
+	HideoutUndeclareHQ: "HideoutUndeclareHQ",
+	HideoutGetHQRequirements: "HideoutGetHQRequirements",
+	HideoutBoost: "HideoutBoost",
+	HideoutBoostConstruction: "HideoutBoostConstruction",
+	OpenWorldAttackScheduleStart: "OpenWorldAttackScheduleStart",
+	OpenWorldAttackScheduleCancel: "OpenWorldAttackScheduleCancel",
+	OpenWorldAttackConquerStart: "OpenWorldAttackConquerStart",
+	OpenWorldAttackConquerCancel: "OpenWorldAttackConquerCancel",
+	GetOpenWorldAttackDetails: "GetOpenWorldAttackDetails",
+	GetNextOpenWorldAttackScheduleTime: "GetNextOpenWorldAttackScheduleTime",
+	RecoverVaultFromHideout: "RecoverVaultFromHideout",
+	GetGuildEnergyDrainInfo: "GetGuildEnergyDrainInfo",
+	ChannelingUpdate: "ChannelingUpdate",
+	UseCorruptedShrine: "UseCorruptedShrine",
+	RequestEstimatedMarketValue: "RequestEstimatedMarketValue",
+	LogFeedback: "LogFeedback",
+	GetInfamyInfo: "GetInfamyInfo",
+	GetPartySmartClusterQueuePriority: "GetPartySmartClusterQueuePriority",
+	SetPartySmartClusterQueuePriority: "SetPartySmartClusterQueuePriority",
+	ClientAntiAutoClickerInfo: "ClientAntiAutoClickerInfo",
+	ClientBotPatternDetectionInfo: "ClientBotPatternDetectionInfo",
+	ClientAntiGatherClickerInfo: "ClientAntiGatherClickerInfo",
+	LoadoutCreate: "LoadoutCreate",
+	LoadoutRead: "LoadoutRead",
+	LoadoutReadHeaders: "LoadoutReadHeaders",
+	LoadoutUpdate: "LoadoutUpdate",
+	LoadoutDelete: "LoadoutDelete",
+	LoadoutOrderUpdate: "LoadoutOrderUpdate",
+	LoadoutEquip: "LoadoutEquip",
+	BatchUseItemCancel: "BatchUseItemCancel",
+	EnlistFactionWarfare: "EnlistFactionWarfare",
+	GetFactionWarfareWeeklyReport: "GetFactionWarfareWeeklyReport",
+	ClaimFactionWarfareWeeklyReport: "ClaimFactionWarfareWeeklyReport",
+	GetFactionWarfareCampaignData: "GetFactionWarfareCampaignData",
+	ClaimFactionWarfareItemReward: "ClaimFactionWarfareItemReward",
+	SendMemoryConsumption: "SendMemoryConsumption",
+	PickupCarriableObjectStart: "PickupCarriableObjectStart",
+	PickupCarriableObjectCancel: "PickupCarriableObjectCancel",
+	SetSavingChestLogsFlag: "SetSavingChestLogsFlag",
+	GetSavingChestLogsFlag: "GetSavingChestLogsFlag",
+	RegisterGuestAccount: "RegisterGuestAccount",
+	ResendGuestAccountVerificationEmail: "ResendGuestAccountVerificationEmail",
+	DoSimpleActionStart: "DoSimpleActionStart",
+	DoSimpleActionCancel: "DoSimpleActionCancel",
+	GetGvgSeasonContributionByActivity: "GetGvgSeasonContributionByActivity",
+	GetGvgSeasonContributionByCrystalLeague: "GetGvgSeasonContributionByCrystalLeague",
+	GetGuildMightCategoryContribution: "GetGuildMightCategoryContribution",
+	GetGuildMightCategoryOverview: "GetGuildMightCategoryOverview",
+	GetPvpChallengeData: "GetPvpChallengeData",
+	ClaimPvpChallengeWeeklyReward: "ClaimPvpChallengeWeeklyReward",
+	GetPersonalMightStats: "GetPersonalMightStats",
+	GetPvpChallengeSeasonRewards: "GetPvpChallengeSeasonRewards",
+	GetPvpChallengeSeasonRewardItems: "GetPvpChallengeSeasonRewardItems",
+	ClaimPvpChallengeSeasonRewards: "ClaimPvpChallengeSeasonRewards",
+	ClaimPvpChallengeSeasonRewardItems: "ClaimPvpChallengeSeasonRewardItems",
+	AuctionGetLoadoutOffers: "AuctionGetLoadoutOffers",
+	AuctionBuyLoadoutOffer: "AuctionBuyLoadoutOffer",
+	AccountDeletionRequest: "AccountDeletionRequest",
+	AccountReactivationRequest: "AccountReactivationRequest",
+	CreateModeratorNotesForAccount: "CreateModeratorNotesForAccount",
+	GetModeratorNotesForAccount: "GetModeratorNotesForAccount",
+	GetModerationEscalationDefiniton: "GetModerationEscalationDefiniton",
+	EventBasedPopupAddSeen: "EventBasedPopupAddSeen",
+	GetItemKillHistory: "GetItemKillHistory",
+	GetVanityConsumables: "GetVanityConsumables",
+	EquipKillEmote: "EquipKillEmote",
+	ChangeKillEmotePlayOnKnockdownSetting: "ChangeKillEmotePlayOnKnockdownSetting",
+	BuyVanityConsumableCharges: "BuyVanityConsumableCharges",
+	ReclaimVanityItem: "ReclaimVanityItem",
+	GetArenaRankings: "GetArenaRankings",
+	GetCrystalLeagueStatistics: "GetCrystalLeagueStatistics",
+	SendOptionsLog: "SendOptionsLog",
+	SendControlsOptionsLog: "SendControlsOptionsLog",
+	MistsUseImmediateReturnExit: "MistsUseImmediateReturnExit",
+	MistsUseStaticEntrance: "MistsUseStaticEntrance",
+	MistsUseCityRoadsEntrance: "MistsUseCityRoadsEntrance",
+	ChangeNewGuildMemberMail: "ChangeNewGuildMemberMail",
+	GetNewGuildMemberMail: "GetNewGuildMemberMail",
+	ChangeGuildFactionAllegiance: "ChangeGuildFactionAllegiance",
+	GetGuildFactionAllegiance: "GetGuildFactionAllegiance",
+	GuildBannerChange: "GuildBannerChange",
+	GuildGetOptionalStats: "GuildGetOptionalStats",
+	GuildSetOptionalStats: "GuildSetOptionalStats",
+	GetPlayerInfoForStalk: "GetPlayerInfoForStalk",
+	PayGoldForCharacterTypeChange: "PayGoldForCharacterTypeChange",
+	QuickSellAuctionQueryAction: "QuickSellAuctionQueryAction",
+	QuickSellAuctionSellAction: "QuickSellAuctionSellAction",
+	FcmTokenToServer: "FcmTokenToServer",
+	ApnsTokenToServer: "ApnsTokenToServer",
+	DeathRecap: "DeathRecap",
+	AuctionFetchFinishedAuctions: "AuctionFetchFinishedAuctions",
+	AbortAuctionFetchFinishedAuctions: "AbortAuctionFetchFinishedAuctions",
+	RequestLegendaryEvenHistory: "RequestLegendaryEvenHistory",
+	PartyAnswerStartHuntRequest: "PartyAnswerStartHuntRequest",
+	HuntAbort: "HuntAbort",
+	UseFindTrackSpellFromItemPrepare: "UseFindTrackSpellFromItemPrepare",
+	InteractWithTrackStart: "InteractWithTrackStart",
+	InteractWithTrackCancel: "InteractWithTrackCancel",
+	TerritoryRaidStart: "TerritoryRaidStart",
+	TerritoryRaidCancel: "TerritoryRaidCancel",
+	TerritoryClaimRaidedRawEnergyCrystalResult: "TerritoryClaimRaidedRawEnergyCrystalResult",
+	GvGSeasonPlayerGuildParticipationDetails: "GvGSeasonPlayerGuildParticipationDetails",
+	DailyMightBonus: "DailyMightBonus",
+	ClaimDailyMightBonus: "ClaimDailyMightBonus",
+	GetFortificationGroupInfo: "GetFortificationGroupInfo",
+	UpgradeFortificationGroup: "UpgradeFortificationGroup",
+	CancelUpgradeFortificationGroup: "CancelUpgradeFortificationGroup",
+	DowngradeFortificationGroup: "DowngradeFortificationGroup",
+	GetClusterActivityChestEstimates: "GetClusterActivityChestEstimates",
+	PartyReadyCheckBegin: "PartyReadyCheckBegin",
+	PartyReadyCheckUpdate: "PartyReadyCheckUpdate",
+	ClaimAlbionJournalReward: "ClaimAlbionJournalReward",
+	TrackAlbionJournalAchievements: "TrackAlbionJournalAchievements",
+	TrackAlbionJournalAchievementSubCategory: "TrackAlbionJournalAchievementSubCategory",
+	RequestOutlandsTeleportationUsage: "RequestOutlandsTeleportationUsage",
+	PickupFromPiledObjectStart: "PickupFromPiledObjectStart",
+	PickupFromPiledObjectCancel: "PickupFromPiledObjectCancel",
+	AssetOverview: "AssetOverview",
+	AssetOverviewTabs: "AssetOverviewTabs",
+	AssetOverviewTabContent: "AssetOverviewTabContent",
+	AssetOverviewUnfreezeCache: "AssetOverviewUnfreezeCache",
+	AssetOverviewSearch: "AssetOverviewSearch",
+	AssetOverviewSearchTabs: "AssetOverviewSearchTabs",
+	AssetOverviewSearchTabContent: "AssetOverviewSearchTabContent",
+	AssetOverviewRecoverPlayerVault: "AssetOverviewRecoverPlayerVault",
+	ImmortalizeKillTrophy: "ImmortalizeKillTrophy",
+	ArmorySearch: "ArmorySearch",
+	ArmoryItemUsageStatistics: "ArmoryItemUsageStatistics",
+	ArmoryActivityUsageStatistics: "ArmoryActivityUsageStatistics",
+	HellDungeonUseStaticEntrance: "HellDungeonUseStaticEntrance",
+	TravelIslandShowroom: "TravelIslandShowroom",
+	GetXuids: "GetXuids",
+	XboxServiceTicket: "XboxServiceTicket",
+	EvaluatePlatformPerks: "EvaluatePlatformPerks",
+	LinkAccountToXbox: "LinkAccountToXbox",
+	TravelFactionWarfarePortal: "TravelFactionWarfarePortal",
+	RequestRedZoneEventStandings: "RequestRedZoneEventStandings",
+	GetZergDebuffInfo: "GetZergDebuffInfo",
+	RequestLoreSnippetStates: "RequestLoreSnippetStates",
+	RetrieveCarriableObjectStart: "RetrieveCarriableObjectStart",
+	RetrieveCarriableObjectCancel: "RetrieveCarriableObjectCancel",
+	ForfeitCustomMatch: "ForfeitCustomMatch",
+	GetPartyMemberCluster: "GetPartyMemberCluster",
+	SetPartyNavigation: "SetPartyNavigation",
+	EndPartyNavigation: "EndPartyNavigation",
+}
+
+// Name returns the JS enum name for an Albion code, or "" when the code is
+// not in the enum.
+func Name(code int) string { return names[code] }
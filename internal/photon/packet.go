@@ -15,6 +15,8 @@ const (
 )
 
 const (
+	cmdAck            = byte(1)
+	cmdConnect        = byte(2)
 	cmdDisconnect     = byte(4)
 	cmdSendReliable   = byte(6)
 	cmdSendUnreliable = byte(7)
@@ -42,6 +44,14 @@ type segmentedPackage struct {
 
 type PhotonParser struct {
 	pendingSegments map[uint32]*segmentedPackage
+	// meta is the packet being parsed, stamped on every message it yields.
+	meta PacketMeta
+	// cmds collects the commands of that packet for Conns.
+	cmds packetCommands
+
+	// Conns, when set, follows the connections of the packets fed with
+	// ReceivePacketMeta.
+	Conns *ConnTracker
 
 	OnEvent      func(*EventData)
 	OnRequest    func(*OperationRequest)
@@ -63,7 +73,15 @@ func NewPhotonParser(
 	}
 }
 
+// ReceivePacket parses a payload that comes without capture metadata; its
+// messages carry only the header timestamp in Meta.
 func (p *PhotonParser) ReceivePacket(payload []byte) bool {
+	return p.ReceivePacketMeta(payload, PacketMeta{})
+}
+
+// ReceivePacketMeta parses a payload and stamps meta, completed with the
+// header timestamp, on every message in it.
+func (p *PhotonParser) ReceivePacketMeta(payload []byte, meta PacketMeta) bool {
 	if len(payload) < photonHeaderLength {
 		if p.OnParseError != nil {
 			p.OnParseError("payload shorter than photon header", len(payload))
@@ -75,7 +93,15 @@ func (p *PhotonParser) ReceivePacket(payload []byte) bool {
 	offset++
 	commandCount := int(payload[offset])
 	offset++
-	offset += 8 // skip timestamp + challenge
+	meta.SentTime = binary.BigEndian.Uint32(payload[offset:])
+	offset += 4
+	challenge := binary.BigEndian.Uint32(payload[offset:])
+	offset += 4
+	p.meta = meta
+	p.cmds = packetCommands{acks: p.cmds.acks[:0]}
+	if p.Conns != nil {
+		defer p.Conns.observe(meta, challenge, &p.cmds)
+	}
 
 	if flags == 1 {
 		if p.OnEncrypted != nil {
@@ -112,7 +138,16 @@ func (p *PhotonParser) handleCommand(src []byte, offset int) (int, bool) {
 	}
 
 	switch cmdType {
+	case cmdAck:
+		if cmdLen >= 8 {
+			p.cmds.acks = append(p.cmds.acks, binary.BigEndian.Uint32(src[offset+4:]))
+		}
+		return offset + cmdLen, true
+	case cmdConnect:
+		p.cmds.connect = true
+		return offset + cmdLen, true
 	case cmdDisconnect:
+		p.cmds.disconnect = true
 		return offset + cmdLen, true
 	case cmdSendUnreliable:
 		if cmdLen < 4 {
@@ -156,14 +191,17 @@ func (p *PhotonParser) handleSendReliable(src []byte, offset, cmdLen int) int {
 	switch msgType {
 	case msgRequest:
 		if req, err := DeserializeRequest(data); err == nil && p.OnRequest != nil {
+			req.Meta = p.meta
 			p.OnRequest(req)
 		}
 	case msgResponse, msgResponseAlt:
 		if resp, err := DeserializeResponse(data); err == nil && p.OnResponse != nil {
+			resp.Meta = p.meta
 			p.OnResponse(resp)
 		}
 	case msgEvent:
 		if ev, err := DeserializeEvent(data); err == nil && p.OnEvent != nil {
+			ev.Meta = p.meta
 			p.OnEvent(ev)
 		}
 	}
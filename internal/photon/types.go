@@ -41,11 +41,13 @@ func (b ByteArray) MarshalJSON() ([]byte, error) {
 type EventData struct {
 	Code       byte
 	Parameters map[byte]any
+	Meta       PacketMeta
 }
 
 type OperationRequest struct {
 	OperationCode byte
 	Parameters    map[byte]any
+	Meta          PacketMeta
 }
 
 type OperationResponse struct {
@@ -53,4 +55,5 @@ type OperationResponse struct {
 	ReturnCode    int16
 	DebugMessage  string
 	Parameters    map[byte]any
+	Meta          PacketMeta
 }
@@ -1,17 +1,12 @@
-// Package photonscan walks a pcap and hands back every decoded Photon message
-// with its Albion code, so tools can reason about parameters rather than bytes.
+// Package photonscan walks a capture and hands back every decoded Photon
+// message with its Albion code, time and flow, so tools can reason about
+// parameters rather than bytes. Reading the files is pcapiter's job.
 package photonscan
 
 import (
 	"errors"
-	"fmt"
-	"io"
-	"os"
-
-	"github.com/google/gopacket"
-	"github.com/google/gopacket/layers"
-	"github.com/google/gopacket/pcapgo"
 
+	"github.com/nospy/albion-openradar/internal/pcapiter"
 	"github.com/nospy/albion-openradar/internal/photon"
 )
 
@@ -35,10 +30,16 @@ func (k Kind) String() string {
 	return "?"
 }
 
+// Message is one decoded Photon message and the packet that carried it: its
+// capture time, direction, server endpoint and header timestamp. A message
+// spread over several fragments gets the last one's.
 type Message struct {
 	Kind   Kind
 	Code   int
 	Params map[byte]any
+	File   string
+	photon.PacketMeta
+	Flow pcapiter.Flow
 }
 
 // codeKey is the parameter holding the Albion code. The byte on the wire is
@@ -50,60 +51,77 @@ func codeKey(kind Kind) byte {
 	return 253
 }
 
+// Scan decodes every message in a capture: pcap or pcapng, gzipped or not. A
+// read error is returned after the messages before it were visited; a
+// truncated file is not an error, see Summarize.
 func Scan(path string, visit func(Message)) error {
-	f, err := os.Open(path)
-	if err != nil {
-		return err
-	}
-	defer f.Close()
+	_, err := scan(path, visit)
+	return err
+}
 
-	reader, err := pcapgo.NewReader(f)
+// ScanAll scans every capture args name, as files, directories or globs,
+// each with a parser of its own. A file that fails to read does not stop the
+// others; its error is joined into the one returned.
+func ScanAll(args []string, visit func(Message)) ([]Summary, error) {
+	paths, err := pcapiter.Expand(args...)
 	if err != nil {
-		return fmt.Errorf("read %s: %w", path, err)
-	}
-
-	emit := func(kind Kind, params map[byte]any) {
-		visit(Message{Kind: kind, Code: codeOf(params, codeKey(kind)), Params: params})
+		return nil, err
 	}
-
-	parser := photon.NewPhotonParser(
-		func(e *photon.EventData) { emit(KindEvent, e.Parameters) },
-		func(r *photon.OperationRequest) { emit(KindRequest, r.Parameters) },
-		func(r *photon.OperationResponse) { emit(KindResponse, r.Parameters) },
-	)
-
-	for {
-		data, _, err := reader.ReadPacketData()
-		if errors.Is(err, io.EOF) {
-			break
-		}
+	var sums []Summary
+	var errs []error
+	for _, path := range paths {
+		sum, err := scan(path, visit)
+		sums = append(sums, sum)
 		if err != nil {
-			break
+			errs = append(errs, err)
 		}
-		pkt := gopacket.NewPacket(data, reader.LinkType(), gopacket.Default)
-		udp, _ := pkt.Layer(layers.LayerTypeUDP).(*layers.UDP)
-		if udp == nil {
-			continue
-		}
-		parser.ReceivePacket(udp.Payload)
 	}
-	return nil
+	return sums, errors.Join(errs...)
+}
+
+// Summary describes a capture as a whole: the packet counts, span and
+// truncation of pcapiter.Stats, plus the decoded messages.
+type Summary struct {
+	pcapiter.Stats
+	// Messages counts the decoded Photon messages.
+	Messages int
+}
+
+// Summarize reads a whole capture and counts its packets and messages.
+func Summarize(path string) (Summary, error) {
+	return scan(path, nil)
 }
 
-func codeOf(params map[byte]any, key byte) int {
-	switch t := params[key].(type) {
-	case byte:
-		return int(t)
-	case int16:
-		return int(t)
-	case int32:
-		return int(t)
-	case int64:
-		return int(t)
-	case int:
-		return t
+func scan(path string, visit func(Message)) (Summary, error) {
+	var sum Summary
+	var current pcapiter.Packet
+	emit := func(kind Kind, params map[byte]any, meta photon.PacketMeta) {
+		sum.Messages++
+		if visit != nil {
+			visit(Message{
+				Kind:       kind,
+				Code:       photon.AlbionCode(params, codeKey(kind)),
+				Params:     params,
+				File:       path,
+				PacketMeta: meta,
+				Flow:       current.Flow,
+			})
+		}
 	}
-	return -1
+
+	parser := photon.NewPhotonParser(
+		func(e *photon.EventData) { emit(KindEvent, e.Parameters, e.Meta) },
+		func(r *photon.OperationRequest) { emit(KindRequest, r.Parameters, r.Meta) },
+		func(r *photon.OperationResponse) { emit(KindResponse, r.Parameters, r.Meta) },
+	)
+
+	st, err := pcapiter.File(path, func(p pcapiter.Packet) error {
+		current = p
+		parser.ReceivePacketMeta(p.Payload, photon.FlowMeta(p.Timestamp, p.Flow.Src, p.Flow.Dst, photon.ServerPort))
+		return nil
+	})
+	sum.Stats = st
+	return sum, err
 }
 
 // StringsIn flattens a parameter value into the strings it carries, so a
@@ -1,10 +1,19 @@
 package photonscan
 
 import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/google/gopacket/pcapgo"
 	"github.com/stretchr/testify/require"
+
+	"github.com/nospy/albion-openradar/internal/pcapiter"
+	"github.com/nospy/albion-openradar/internal/photon"
 )
 
 func fixture(name string) string {
@@ -64,12 +73,115 @@ func TestScan_ExposesTheParameterTable(t *testing.T) {
 	require.True(t, found, "the player spawn fixture carries event 29")
 }
 
+func TestScan_ReadsGzippedRecordings(t *testing.T) {
+	raw, err := os.ReadFile(fixture("generic_events.pcap"))
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err = zw.Write(raw)
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	gz := filepath.Join(t.TempDir(), "capture.pcap.gz")
+	require.NoError(t, os.WriteFile(gz, buf.Bytes(), 0o644))
+
+	plain, zipped := 0, 0
+	require.NoError(t, Scan(fixture("generic_events.pcap"), func(Message) { plain++ }))
+	require.NoError(t, Scan(gz, func(Message) { zipped++ }))
+	require.NotZero(t, zipped)
+	require.Equal(t, plain, zipped)
+}
+
+func TestScan_ReadsPcapng(t *testing.T) {
+	in, closer, err := pcapiter.Open(fixture("generic_events.pcap"))
+	require.NoError(t, err)
+	defer closer.Close()
+	var buf bytes.Buffer
+	w, err := pcapgo.NewNgWriter(&buf, in.LinkType())
+	require.NoError(t, err)
+	for {
+		data, ci, err := in.ReadPacketData()
+		if err != nil {
+			break
+		}
+		require.NoError(t, w.WritePacket(ci, data))
+	}
+	require.NoError(t, w.Flush())
+	ng := filepath.Join(t.TempDir(), "capture.pcapng")
+	require.NoError(t, os.WriteFile(ng, buf.Bytes(), 0o644))
+
+	classic, err := Summarize(fixture("generic_events.pcap"))
+	require.NoError(t, err)
+	converted, err := Summarize(ng)
+	require.NoError(t, err)
+	require.NotZero(t, converted.Messages)
+	require.Equal(t, classic.Packets, converted.Packets)
+	require.Equal(t, classic.Messages, converted.Messages)
+}
+
+func TestScan_CarriesEachMessagesTimeAndFlow(t *testing.T) {
+	var first Message
+	require.NoError(t, Scan(fixture("generic_events.pcap"), func(m Message) {
+		if first.File == "" {
+			first = m
+		}
+	}))
+
+	require.Equal(t, fixture("generic_events.pcap"), first.File)
+	require.False(t, first.Time.IsZero())
+	require.True(t, first.Flow.Src.IsValid())
+	require.True(t, first.Flow.Src.Port() == 5056 || first.Flow.Dst.Port() == 5056, "flow %s", first.Flow)
+	require.NotEqual(t, photon.DirUnknown, first.Direction)
+	require.Equal(t, uint16(photon.ServerPort), first.Server.Port())
+}
+
+func TestScan_ReturnsAReadErrorInsteadOfStoppingQuietly(t *testing.T) {
+	raw, err := os.ReadFile(fixture("generic_events.pcap"))
+	require.NoError(t, err)
+	// Give the second record an impossible capture length.
+	second := 24 + 16 + int(binary.LittleEndian.Uint32(raw[24+8:]))
+	binary.LittleEndian.PutUint32(raw[second+8:], 0x7fffffff)
+	bad := filepath.Join(t.TempDir(), "bad.pcap")
+	require.NoError(t, os.WriteFile(bad, raw, 0o644))
+
+	seen := 0
+	err = Scan(bad, func(Message) { seen++ })
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "packet 1")
+}
+
+func TestSummarize_FlagsATruncatedCapture(t *testing.T) {
+	raw, err := os.ReadFile(fixture("generic_events.pcap"))
+	require.NoError(t, err)
+	cut := filepath.Join(t.TempDir(), "cut.pcap")
+	require.NoError(t, os.WriteFile(cut, raw[:len(raw)-5], 0o644))
+
+	sum, err := Summarize(cut)
+
+	require.NoError(t, err)
+	require.True(t, sum.Truncated)
+	require.NotZero(t, sum.Packets)
+}
+
 func TestScan_ReportsAMissingFile(t *testing.T) {
 	err := Scan(fixture("does-not-exist.pcap"), func(Message) {})
 
 	require.Error(t, err)
 }
 
+func TestSummarize_CountsPacketsAndMessagesOverTheCaptureSpan(t *testing.T) {
+	messages := 0
+	require.NoError(t, Scan(fixture("operations.pcap"), func(Message) { messages++ }))
+
+	sum, err := Summarize(fixture("operations.pcap"))
+
+	require.NoError(t, err)
+	require.Equal(t, messages, sum.Messages)
+	require.GreaterOrEqual(t, sum.Packets, 1)
+	require.False(t, sum.First.IsZero())
+	require.GreaterOrEqual(t, sum.Duration(), time.Duration(0))
+}
+
 func TestStringsIn_FlattensNestedValues(t *testing.T) {
 	require.Equal(t, []string{"a"}, StringsIn("a"))
 	require.Equal(t, []string{"a", "b"}, StringsIn([]string{"a", "b"}))
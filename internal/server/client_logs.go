@@ -0,0 +1,199 @@
+package server
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"github.com/segmentio/encoding/json"
+
+	"github.com/nospy/albion-openradar/internal/logger"
+)
+
+// Limits on the logs browsers send over the WebSocket. The browser logger
+// flushes every 5s or at 200 entries, so a well-behaved client stays far
+// below them.
+const (
+	// MaxClientMessageBytes caps one incoming WebSocket message; a larger one
+	// closes the connection.
+	MaxClientMessageBytes = 1 << 20
+	// MaxClientLogBatch is the number of entries kept from one message.
+	MaxClientLogBatch = 200
+	// MaxClientLogEntryBytes caps one encoded entry, data included.
+	MaxClientLogEntryBytes = 8 << 10
+	// ClientLogRate and ClientLogBurst bound the entries per second a client
+	// address may write, with a burst for page loads.
+	ClientLogRate  = 20
+	ClientLogBurst = 400
+
+	clientLogIdle     = 10 * time.Minute
+	clientLogWarnEach = time.Minute
+)
+
+// ClientLogEntry is the schema of one browser log entry, as
+// web/scripts/logger.js builds it. Unknown fields are dropped.
+type ClientLogEntry struct {
+	Timestamp string          `json:"timestamp"`
+	Level     string          `json:"level"`
+	Category  string          `json:"category"`
+	Event     string          `json:"event"`
+	Data      json.RawMessage `json:"data,omitempty"`
+	SessionID string          `json:"sessionId,omitempty"`
+	Page      string          `json:"page,omitempty"`
+}
+
+var clientLogLevels = map[string]bool{"DEBUG": true, "INFO": true, "WARN": true, "ERROR": true, "CRITICAL": true}
+
+// valid checks the required fields and their lengths.
+func (e *ClientLogEntry) valid() bool {
+	if !clientLogLevels[e.Level] {
+		return false
+	}
+	if _, err := time.Parse(time.RFC3339, e.Timestamp); err != nil {
+		return false
+	}
+	return shortString(e.Category, 1, 64) && shortString(e.Event, 1, 128) &&
+		shortString(e.SessionID, 0, 64) && shortString(e.Page, 0, 256)
+}
+
+func shortString(s string, minLen, maxLen int) bool {
+	return len(s) >= minLen && len(s) <= maxLen && utf8.ValidString(s)
+}
+
+// ClientLogStats counts client log entries by outcome.
+type ClientLogStats struct {
+	Accepted uint64
+	// Invalid entries failed the schema.
+	Invalid uint64
+	// TooLarge entries were over MaxClientLogEntryBytes.
+	TooLarge uint64
+	// RateLimited entries came faster than ClientLogRate.
+	RateLimited uint64
+	// OverBatch entries were past MaxClientLogBatch in one message.
+	OverBatch uint64
+}
+
+// Rejected sums the rejection counters.
+func (s ClientLogStats) Rejected() uint64 {
+	return s.Invalid + s.TooLarge + s.RateLimited + s.OverBatch
+}
+
+// clientLogGate validates client logs and applies a token bucket per client
+// address, so reconnecting does not reset the budget.
+type clientLogGate struct {
+	mu      sync.Mutex
+	clients map[string]*clientBucket
+	now     func() time.Time
+
+	accepted, invalid, tooLarge, rateLimited, overBatch atomic.Uint64
+}
+
+type clientBucket struct {
+	tokens   float64
+	last     time.Time
+	lastWarn time.Time
+}
+
+func newClientLogGate() *clientLogGate {
+	return &clientLogGate{clients: make(map[string]*clientBucket), now: time.Now}
+}
+
+// filter returns the entries of one message that may be written, re-encoded
+// from the schema so nothing else reaches the disk.
+func (g *clientLogGate) filter(client string, raw []json.RawMessage) []any {
+	var rejected uint64
+	if len(raw) > MaxClientLogBatch {
+		rejected = uint64(len(raw) - MaxClientLogBatch)
+		g.overBatch.Add(rejected)
+		raw = raw[:MaxClientLogBatch]
+	}
+
+	out := make([]any, 0, len(raw))
+	for _, r := range raw {
+		if len(r) > MaxClientLogEntryBytes {
+			g.tooLarge.Add(1)
+			rejected++
+			continue
+		}
+		var e ClientLogEntry
+		if err := json.Unmarshal(r, &e); err != nil || !e.valid() {
+			g.invalid.Add(1)
+			rejected++
+			continue
+		}
+		out = append(out, map[string]any{
+			"timestamp": e.Timestamp,
+			"level":     e.Level,
+			"category":  e.Category,
+			"event":     e.Event,
+			"data":      e.Data,
+			"sessionId": e.SessionID,
+			"page":      e.Page,
+		})
+	}
+
+	allowed := g.take(client, len(out))
+	if limited := len(out) - allowed; limited > 0 {
+		g.rateLimited.Add(uint64(limited))
+		rejected += uint64(limited)
+		out = out[:allowed]
+	}
+	g.accepted.Add(uint64(len(out)))
+	if rejected > 0 && g.warnDue(client) {
+		logger.PrintWarn("WS", "Dropped %d log entries from %s (bad schema, too large or over %d/s)",
+			rejected, client, ClientLogRate)
+	}
+	return out
+}
+
+// take spends up to n tokens of client's bucket and reports how many were
+// available. Buckets idle for clientLogIdle are forgotten.
+func (g *clientLogGate) take(client string, n int) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := g.now()
+	for addr, b := range g.clients {
+		if now.Sub(b.last) > clientLogIdle {
+			delete(g.clients, addr)
+		}
+	}
+	b := g.clients[client]
+	if b == nil {
+		b = &clientBucket{tokens: ClientLogBurst, last: now}
+		g.clients[client] = b
+	}
+	b.tokens = min(b.tokens+now.Sub(b.last).Seconds()*ClientLogRate, ClientLogBurst)
+	b.last = now
+
+	allowed := min(n, int(b.tokens))
+	b.tokens -= float64(allowed)
+	return allowed
+}
+
+// warnDue limits rejection warnings to one per client per clientLogWarnEach.
+func (g *clientLogGate) warnDue(client string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	b := g.clients[client]
+	if b == nil {
+		return false
+	}
+	now := g.now()
+	if now.Sub(b.lastWarn) < clientLogWarnEach {
+		return false
+	}
+	b.lastWarn = now
+	return true
+}
+
+func (g *clientLogGate) stats() ClientLogStats {
+	return ClientLogStats{
+		Accepted:    g.accepted.Load(),
+		Invalid:     g.invalid.Load(),
+		TooLarge:    g.tooLarge.Load(),
+		RateLimited: g.rateLimited.Load(),
+		OverBatch:   g.overBatch.Load(),
+	}
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/require"
+)
+
+func clientEntry(level, category string, data string) json.RawMessage {
+	return json.RawMessage(fmt.Sprintf(
+		`{"timestamp":"2026-10-18T10:00:00.123Z","level":%q,"category":%q,"event":"ev","data":%s,"sessionId":"s1","page":"/","extra":"dropped"}`,
+		level, category, data))
+}
+
+func TestClientLogGate_ValidatesSchemaAndSize(t *testing.T) {
+	g := newClientLogGate()
+	out := g.filter("10.0.0.2", []json.RawMessage{
+		clientEntry("ERROR", "MOBS", `{"id":1}`),
+		clientEntry("LOUD", "MOBS", `{}`),
+		clientEntry("INFO", "", `{}`),
+		json.RawMessage(`{"timestamp":"yesterday","level":"INFO","category":"C","event":"e"}`),
+		json.RawMessage(`[1,2,3]`),
+		clientEntry("INFO", "MOBS", `"`+strings.Repeat("x", MaxClientLogEntryBytes)+`"`),
+	})
+
+	require.Len(t, out, 1)
+	written, err := json.Marshal(out[0])
+	require.NoError(t, err)
+	require.JSONEq(t, `{"timestamp":"2026-10-18T10:00:00.123Z","level":"ERROR","category":"MOBS","event":"ev","data":{"id":1},"sessionId":"s1","page":"/"}`, string(written))
+	require.Equal(t, ClientLogStats{Accepted: 1, Invalid: 4, TooLarge: 1}, g.stats())
+}
+
+func TestClientLogGate_RateLimitsPerClient(t *testing.T) {
+	g := newClientLogGate()
+	now := time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+	g.now = func() time.Time { return now }
+
+	batch := make([]json.RawMessage, MaxClientLogBatch+50)
+	for i := range batch {
+		batch[i] = clientEntry("INFO", "MAP", `{}`)
+	}
+	require.Len(t, g.filter("10.0.0.2", batch), MaxClientLogBatch, "entries past the batch cap are dropped")
+	require.Len(t, g.filter("10.0.0.2", batch), ClientLogBurst-MaxClientLogBatch, "the burst is spent")
+	require.Empty(t, g.filter("10.0.0.2", batch[:10]))
+	require.Len(t, g.filter("10.0.0.3", batch[:10]), 10, "another client has its own budget")
+
+	now = now.Add(time.Second)
+	require.Len(t, g.filter("10.0.0.2", batch[:50]), ClientLogRate, "the bucket refills at ClientLogRate")
+
+	s := g.stats()
+	require.Equal(t, uint64(2*50), s.OverBatch)
+	require.Equal(t, uint64(10+50-ClientLogRate), s.RateLimited)
+	require.Equal(t, uint64(ClientLogBurst+10+ClientLogRate), s.Accepted)
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/nospy/albion-openradar/internal/photon"
+)
+
+// ConnectionAPI serves the Photon connections of the game client.
+type ConnectionAPI struct {
+	conns *photon.ConnTracker
+}
+
+func (a *ConnectionAPI) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/connection", a.handleConnection)
+}
+
+type connBody struct {
+	Server       string     `json:"server"`
+	Client       string     `json:"client"`
+	Active       bool       `json:"active"`
+	Connected    time.Time  `json:"connected"`
+	Disconnected *time.Time `json:"disconnected,omitempty"`
+	LastPacket   time.Time  `json:"lastPacket"`
+	// LastPacketAgeMs is measured on the server, so that a client with a
+	// skewed clock still shows how long the game has been silent.
+	LastPacketAgeMs int64   `json:"lastPacketAgeMs"`
+	PingMs          float64 `json:"pingMs"`
+	ServerTime      uint32  `json:"serverTime"`
+	Packets         uint64  `json:"packets"`
+}
+
+type connectionBody struct {
+	// Current is the game server the client plays on, null when logged out.
+	Current     *connBody  `json:"current"`
+	Connections []connBody `json:"connections"`
+}
+
+// handleConnection reports the current game server, its ping and how long
+// ago it last sent anything, plus every connection seen in the last minutes.
+func (a *ConnectionAPI) handleConnection(w http.ResponseWriter, _ *http.Request) {
+	if a.conns == nil {
+		http.Error(w, "connection tracking not available", http.StatusServiceUnavailable)
+		return
+	}
+	now := time.Now()
+	body := connectionBody{Connections: make([]connBody, 0)}
+	for _, c := range a.conns.Conns() {
+		body.Connections = append(body.Connections, newConnBody(c, now))
+	}
+	if c, ok := a.conns.Current(); ok {
+		cur := newConnBody(c, now)
+		body.Current = &cur
+	}
+	writeJSON(w, http.StatusOK, body)
+}
+
+func newConnBody(c photon.Conn, now time.Time) connBody {
+	b := connBody{
+		Server:          c.Server.String(),
+		Client:          c.Client.String(),
+		Active:          c.Active(),
+		Connected:       c.Connected,
+		LastPacket:      c.LastPacket,
+		LastPacketAgeMs: max(now.Sub(c.LastPacket).Milliseconds(), 0),
+		PingMs:          float64(c.RTT.Microseconds()) / 1000,
+		ServerTime:      c.ServerTime,
+		Packets:         c.Packets,
+	}
+	if !c.Active() {
+		b.Disconnected = &c.Disconnected
+	}
+	return b
+}
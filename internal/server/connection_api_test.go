@@ -0,0 +1,87 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nospy/albion-openradar/internal/photon"
+)
+
+func getConnection(t *testing.T, mux *http.ServeMux) (int, connectionBody) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/connection", nil))
+	var body connectionBody
+	if rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+	}
+	return rec.Code, body
+}
+
+func TestConnectionAPI_NoTrafficYet(t *testing.T) {
+	mux := http.NewServeMux()
+	(&ConnectionAPI{conns: photon.NewConnTracker()}).Register(mux)
+	status, body := getConnection(t, mux)
+	if status != http.StatusOK || body.Current != nil || body.Connections == nil || len(body.Connections) != 0 {
+		t.Fatalf("status %d body %+v", status, body)
+	}
+}
+
+func TestConnectionAPI_Unavailable(t *testing.T) {
+	mux := http.NewServeMux()
+	(&ConnectionAPI{}).Register(mux)
+	if status, _ := getConnection(t, mux); status != http.StatusServiceUnavailable {
+		t.Errorf("got %d, want 503 before SetConnections", status)
+	}
+}
+
+func TestNewConnBody(t *testing.T) {
+	last := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	c := photon.Conn{LastPacket: last, RTT: 42500 * time.Microsecond, ServerTime: 99}
+	b := newConnBody(c, last.Add(3*time.Second))
+	if !b.Active || b.PingMs != 42.5 || b.LastPacketAgeMs != 3000 || b.ServerTime != 99 || b.Disconnected != nil {
+		t.Errorf("got %+v", b)
+	}
+	c.Disconnected = last
+	if b := newConnBody(c, last); b.Active || b.Disconnected == nil {
+		t.Errorf("closed connection: got %+v", b)
+	}
+}
+
+func TestInstancesAPI_ListsClients(t *testing.T) {
+	mux := http.NewServeMux()
+	api := &InstancesAPI{}
+	api.Register(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/instances", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("got %d, want 503 before SetInstances", rec.Code)
+	}
+
+	api.list = func() []ClientInstance { return nil }
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/instances", nil))
+	if got := rec.Body.String(); rec.Code != http.StatusOK || !strings.Contains(got, `"instances":[]`) {
+		t.Fatalf("no clients: %d %s", rec.Code, got)
+	}
+
+	api.list = func() []ClientInstance {
+		return []ClientInstance{{Port: 50000, Cluster: "4000", Primary: true}, {Port: 50001}}
+	}
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/instances", nil))
+	var body instancesBody
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(body.Instances) != 2 || body.Instances[0].Port != 50000 || !body.Instances[0].Primary {
+		t.Fatalf("body %+v", body)
+	}
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/nospy/albion-openradar/internal/photon/codestats"
+)
+
+// DebugAPI serves live decoding diagnostics.
+type DebugAPI struct {
+	codes *codestats.Counter
+}
+
+func (a *DebugAPI) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/debug/codes", a.handleCodes)
+}
+
+type codesBody struct {
+	Since         time.Time        `json:"since"`
+	WindowSeconds int              `json:"windowSeconds"`
+	Codes         []codestats.Stat `json:"codes"`
+}
+
+// handleCodes lists the (kind, code) counters busiest first. Optional query
+// parameters: kind=event|request|response, unknown=1 to keep only codes
+// missing from the enums, limit=N.
+func (a *DebugAPI) handleCodes(w http.ResponseWriter, r *http.Request) {
+	if a.codes == nil {
+		http.Error(w, "code counters not available", http.StatusServiceUnavailable)
+		return
+	}
+	q := r.URL.Query()
+	kind := codestats.Kind(q.Get("kind"))
+	switch kind {
+	case "", codestats.Event, codestats.Request, codestats.Response:
+	default:
+		http.Error(w, "kind must be event, request or response", http.StatusBadRequest)
+		return
+	}
+	limit := 0
+	if s := q.Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			http.Error(w, "limit must be a non-negative integer", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+	unknownOnly := q.Get("unknown") == "1" || q.Get("unknown") == "true"
+
+	codes := make([]codestats.Stat, 0)
+	for _, st := range a.codes.Snapshot() {
+		if kind != "" && st.Kind != kind {
+			continue
+		}
+		if unknownOnly && st.Name != "" {
+			continue
+		}
+		codes = append(codes, st)
+		if limit > 0 && len(codes) == limit {
+			break
+		}
+	}
+	writeJSON(w, http.StatusOK, codesBody{
+		Since:         a.codes.Started(),
+		WindowSeconds: int(codestats.RateWindow / time.Second),
+		Codes:         codes,
+	})
+}
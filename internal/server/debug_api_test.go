@@ -0,0 +1,62 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nospy/albion-openradar/internal/photon/codestats"
+	"github.com/nospy/albion-openradar/internal/photon/eventcodes"
+)
+
+func getCodes(t *testing.T, mux *http.ServeMux, query string) (int, codesBody) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/debug/codes"+query, nil))
+	var body codesBody
+	if rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+	}
+	return rec.Code, body
+}
+
+func TestDebugCodes(t *testing.T) {
+	codes := codestats.New()
+	codes.Add(codestats.Event, eventcodes.Move)
+	codes.Add(codestats.Event, eventcodes.Move)
+	codes.Add(codestats.Event, 9999)
+	codes.Add(codestats.Request, 1)
+	mux := http.NewServeMux()
+	(&DebugAPI{codes: codes}).Register(mux)
+
+	status, body := getCodes(t, mux, "")
+	if status != http.StatusOK || len(body.Codes) != 3 || body.WindowSeconds != 10 {
+		t.Fatalf("status %d body %+v", status, body)
+	}
+	if body.Codes[0].Code != eventcodes.Move || body.Codes[0].Name != "Move" || body.Codes[0].Count != 2 {
+		t.Errorf("busiest first: got %+v", body.Codes[0])
+	}
+
+	_, body = getCodes(t, mux, "?kind=event&unknown=1")
+	if len(body.Codes) != 1 || body.Codes[0].Code != 9999 {
+		t.Errorf("unknown events: got %+v", body.Codes)
+	}
+	_, body = getCodes(t, mux, "?limit=1")
+	if len(body.Codes) != 1 {
+		t.Errorf("limit: got %d codes", len(body.Codes))
+	}
+	if status, _ := getCodes(t, mux, "?kind=events"); status != http.StatusBadRequest {
+		t.Errorf("bad kind: got %d", status)
+	}
+}
+
+func TestDebugCodesUnavailable(t *testing.T) {
+	mux := http.NewServeMux()
+	(&DebugAPI{}).Register(mux)
+	if status, _ := getCodes(t, mux, ""); status != http.StatusServiceUnavailable {
+		t.Errorf("got %d, want 503 before SetCodeStats", status)
+	}
+}
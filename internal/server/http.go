@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"compress/gzip"
 	"context"
+	"crypto/tls"
 	"embed"
 	"fmt"
 	"io/fs"
+	"net"
 	"net/http"
 	"os"
 	"strings"
@@ -14,14 +16,18 @@ import (
 
 	"github.com/nospy/albion-openradar/internal/capture"
 	"github.com/nospy/albion-openradar/internal/logger"
+	"github.com/nospy/albion-openradar/internal/photon"
+	"github.com/nospy/albion-openradar/internal/photon/codestats"
 	"github.com/nospy/albion-openradar/internal/templates"
 )
 
 // HTTPServer serves static files and WebSocket from embedded assets or filesystem
 type HTTPServer struct {
-	port      int
+	listen    ListenAddr
 	mux       *http.ServeMux
 	server    *http.Server
+	listener  net.Listener
+	tlsCert   *TLSCert
 	logger    *logger.Logger
 	wsHandler *WebSocketHandler
 	// Filesystems (can be embed.FS or os.DirFS)
@@ -37,6 +43,12 @@ type HTTPServer struct {
 	devMode     bool
 	networkAPI  *NetworkAPI
 	settingsAPI *SettingsAPI
+	metrics     func(*MetricsWriter)
+	debugAPI    *DebugAPI
+	connAPI     *ConnectionAPI
+	instAPI     *InstancesAPI
+	// recordingsAPI is nil when no capture directory is set.
+	recordingsAPI *RecordingsAPI
 }
 
 // buildID fingerprints the embedded assets. It is empty for an unversioned build,
@@ -61,7 +73,7 @@ func etagSafe(s string) string {
 
 // NewHTTPServer creates a new HTTP server with embedded assets (production mode)
 func NewHTTPServer(
-	port int,
+	listen ListenAddr,
 	images, scripts, data, sounds, styles, tmplFS embed.FS,
 	wsHandler *WebSocketHandler,
 	log *logger.Logger,
@@ -76,23 +88,23 @@ func NewHTTPServer(
 	// Extract subdirectories from embed.FS (they include the folder path)
 	imagesFS, err := fs.Sub(images, "web/images")
 	if err != nil {
-		fmt.Printf("[HTTP] Warning: failed to load images: %v\n", err)
+		logger.PrintWarn("HTTP", "Failed to load images: %v", err)
 	}
 	scriptsFS, err := fs.Sub(scripts, "web/scripts")
 	if err != nil {
-		fmt.Printf("[HTTP] Warning: failed to load scripts: %v\n", err)
+		logger.PrintWarn("HTTP", "Failed to load scripts: %v", err)
 	}
 	dataFS, err := fs.Sub(data, "web/ao-bin-dumps")
 	if err != nil {
-		fmt.Printf("[HTTP] Warning: failed to load data: %v\n", err)
+		logger.PrintWarn("HTTP", "Failed to load data: %v", err)
 	}
 	soundsFS, err := fs.Sub(sounds, "web/sounds")
 	if err != nil {
-		fmt.Printf("[HTTP] Warning: failed to load sounds: %v\n", err)
+		logger.PrintWarn("HTTP", "Failed to load sounds: %v", err)
 	}
 	stylesFS, err := fs.Sub(styles, "web/styles")
 	if err != nil {
-		fmt.Printf("[HTTP] Warning: failed to load styles: %v\n", err)
+		logger.PrintWarn("HTTP", "Failed to load styles: %v", err)
 	}
 
 	// Initialize template engine (required)
@@ -100,10 +112,10 @@ func NewHTTPServer(
 	if err != nil {
 		return nil, fmt.Errorf("failed to load templates: %w", err)
 	}
-	fmt.Println("[HTTP] Template engine initialized (SSR mode)")
+	logger.PrintInfo("HTTP", "Template engine initialized (SSR mode)")
 
 	s := &HTTPServer{
-		port:      port,
+		listen:    listen,
 		mux:       http.NewServeMux(),
 		logger:    log,
 		wsHandler: wsHandler,
@@ -117,16 +129,20 @@ func NewHTTPServer(
 		assetID:   buildID(version, buildTime),
 	}
 	if mgr != nil {
-		s.networkAPI = NewNetworkAPI(mgr, allInterfaces, appDir, capture.LANAddresses)
+		s.networkAPI = NewNetworkAPI(mgr, allInterfaces, appDir, s.LANAddresses)
+		s.networkAPI.serverPort = listen.Port
 	}
 	s.settingsAPI = NewSettingsAPI(appDir, log, recorder, captureDir)
+	if captureDir != "" {
+		s.recordingsAPI = NewRecordingsAPI(captureDir, recorder)
+	}
 	s.setupRoutes()
 	return s, nil
 }
 
 // NewHTTPServerDev creates a new HTTP server reading from filesystem (dev mode)
 func NewHTTPServerDev(
-	port int,
+	listen ListenAddr,
 	appDir string,
 	wsHandler *WebSocketHandler,
 	log *logger.Logger,
@@ -143,10 +159,10 @@ func NewHTTPServerDev(
 	if err != nil {
 		return nil, fmt.Errorf("failed to load templates: %w", err)
 	}
-	fmt.Println("[HTTP] Template engine initialized (dev mode with hot reload)")
+	logger.PrintInfo("HTTP", "Template engine initialized (dev mode with hot reload)")
 
 	s := &HTTPServer{
-		port:      port,
+		listen:    listen,
 		mux:       http.NewServeMux(),
 		logger:    log,
 		wsHandler: wsHandler,
@@ -161,9 +177,13 @@ func NewHTTPServerDev(
 		devMode:   true,
 	}
 	if mgr != nil {
-		s.networkAPI = NewNetworkAPI(mgr, allInterfaces, appDir, capture.LANAddresses)
+		s.networkAPI = NewNetworkAPI(mgr, allInterfaces, appDir, s.LANAddresses)
+		s.networkAPI.serverPort = listen.Port
 	}
 	s.settingsAPI = NewSettingsAPI(appDir, log, recorder, captureDir)
+	if captureDir != "" {
+		s.recordingsAPI = NewRecordingsAPI(captureDir, recorder)
+	}
 	s.setupRoutes()
 	return s, nil
 }
@@ -175,6 +195,8 @@ func (s *HTTPServer) setupRoutes() {
 		s.mux.Handle("/ws", s.wsHandler)
 	}
 
+	s.mux.HandleFunc("GET /metrics", s.handleMetrics)
+
 	// Page routes - SSR with Go templates
 	pageRoutes := map[string]string{
 		"/":           "radar",
@@ -211,6 +233,21 @@ func (s *HTTPServer) setupRoutes() {
 	if s.networkAPI != nil {
 		s.networkAPI.Register(apiMux)
 	}
+	if s.wsHandler != nil {
+		apiMux.HandleFunc("GET /api/stream", s.wsHandler.ServeStream)
+	}
+	s.debugAPI = &DebugAPI{}
+	s.debugAPI.Register(apiMux)
+	s.connAPI = &ConnectionAPI{}
+	s.connAPI.Register(apiMux)
+	s.instAPI = &InstancesAPI{}
+	s.instAPI.Register(apiMux)
+	if s.logger != nil {
+		NewLogsAPI(s.logger).Register(apiMux)
+	}
+	if s.recordingsAPI != nil {
+		s.recordingsAPI.Register(apiMux)
+	}
 	s.mux.Handle("/api/", noStore(apiMux))
 }
 
@@ -258,7 +295,7 @@ func (s *HTTPServer) renderPage(w http.ResponseWriter, r *http.Request, page str
 	}
 
 	if err != nil {
-		s.logger.Error("http", "render", fmt.Sprintf("Failed to render page %s: %v", page, err), nil)
+		s.logger.Error("HTTP", "Failed to render page", map[string]any{"page": page, "error": err.Error()}, nil)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 		return
 	}
@@ -393,9 +430,21 @@ func setContentType(w http.ResponseWriter, path string) {
 	}
 }
 
-// Start starts the HTTP server
-func (s *HTTPServer) Start() error {
-	addr := fmt.Sprintf(":%d", s.port)
+// Listen binds the listen address without serving, so a taken port is
+// reported before any URL is advertised. Start calls it if needed.
+func (s *HTTPServer) Listen() error {
+	if s.listener != nil {
+		return nil
+	}
+	addr := s.listen.String()
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		if isAddrInUse(err) {
+			return fmt.Errorf("%w: %s is held by another program (is OpenRadar already running?); pick another port with -port", ErrAddrInUse, addr)
+		}
+		return fmt.Errorf("listen on %s: %w", addr, err)
+	}
+	s.listener = ln
 	s.server = &http.Server{
 		Addr:              addr,
 		Handler:           s.mux,
@@ -404,7 +453,69 @@ func (s *HTTPServer) Start() error {
 		WriteTimeout:      30 * time.Second,
 		IdleTimeout:       120 * time.Second,
 	}
-	return s.server.ListenAndServe()
+	if s.tlsCert != nil {
+		s.server.TLSConfig = &tls.Config{
+			Certificates: []tls.Certificate{s.tlsCert.Certificate},
+			MinVersion:   tls.VersionTLS12,
+		}
+	}
+	return nil
+}
+
+// Start starts the HTTP server
+func (s *HTTPServer) Start() error {
+	if err := s.Listen(); err != nil {
+		return err
+	}
+	if s.tlsCert != nil {
+		return s.server.ServeTLS(s.listener, "", "")
+	}
+	return s.server.Serve(s.listener)
+}
+
+// UseTLS switches the server to HTTPS and WSS. Call it before Listen.
+func (s *HTTPServer) UseTLS(cert *TLSCert) {
+	s.tlsCert = cert
+	if s.networkAPI != nil {
+		s.networkAPI.serverScheme = "https"
+		s.networkAPI.tlsFingerprint = cert.Fingerprint
+	}
+}
+
+// Scheme returns "https" when TLS is enabled, "http" otherwise.
+func (s *HTTPServer) Scheme() string {
+	if s.tlsCert != nil {
+		return "https"
+	}
+	return "http"
+}
+
+// WSScheme returns the WebSocket scheme matching Scheme.
+func (s *HTTPServer) WSScheme() string {
+	if s.tlsCert != nil {
+		return "wss"
+	}
+	return "ws"
+}
+
+// TLSFingerprint returns the SHA-256 fingerprint of the served certificate,
+// or "" over plain HTTP.
+func (s *HTTPServer) TLSFingerprint() string {
+	if s.tlsCert == nil {
+		return ""
+	}
+	return s.tlsCert.Fingerprint
+}
+
+// Addr returns the configured listen address.
+func (s *HTTPServer) Addr() ListenAddr {
+	return s.listen
+}
+
+// LANAddresses lists the LAN addresses the server can be reached on, empty in
+// loopback-only mode.
+func (s *HTTPServer) LANAddresses() []string {
+	return s.listen.ReachableLAN(capture.LANAddresses())
 }
 
 // Shutdown gracefully shuts down the HTTP server
@@ -420,6 +531,35 @@ func (s *HTTPServer) Shutdown(ctx context.Context) error {
 	return nil
 }
 
+// SetCodeStats backs /api/debug/codes. Call it before Start; until then the
+// route answers 503.
+func (s *HTTPServer) SetCodeStats(codes *codestats.Counter) {
+	s.debugAPI.codes = codes
+}
+
+// SetConnections backs /api/connection. Call it before Start; until then
+// the route answers 503.
+func (s *HTTPServer) SetConnections(conns *photon.ConnTracker) {
+	s.connAPI.conns = conns
+}
+
+// SetInstances backs /api/instances. Call it before Start; until then the
+// route answers 503.
+func (s *HTTPServer) SetInstances(list func() []ClientInstance) {
+	s.instAPI.list = list
+}
+
+// NetworkAPI returns the capture interface API, nil when the server runs
+// without a capture manager.
+func (s *HTTPServer) NetworkAPI() *NetworkAPI {
+	return s.networkAPI
+}
+
+// SettingsAPI returns the logging and recording settings API.
+func (s *HTTPServer) SettingsAPI() *SettingsAPI {
+	return s.settingsAPI
+}
+
 // WebSocketHandler returns the WebSocket handler for broadcasting
 func (s *HTTPServer) WebSocketHandler() *WebSocketHandler {
 	return s.wsHandler
@@ -0,0 +1,45 @@
+package server
+
+import (
+	"net/http"
+	"time"
+)
+
+// ClientInstance is one game client running on the capture PC, told apart by
+// its local UDP port. Stream clients follow one with /ws?instance=<port>.
+type ClientInstance struct {
+	Port      uint16    `json:"port"`
+	FirstSeen time.Time `json:"firstSeen"`
+	LastSeen  time.Time `json:"lastSeen"`
+	Packets   uint64    `json:"packets"`
+	Cluster   string    `json:"cluster"`
+	// Server is the game server the client plays on, "" when logged out.
+	Server string `json:"server"`
+	// Primary is the client the console dashboard follows.
+	Primary bool `json:"primary"`
+}
+
+// InstancesAPI lists the game clients seen in the captured traffic.
+type InstancesAPI struct {
+	list func() []ClientInstance
+}
+
+func (a *InstancesAPI) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/instances", a.handleInstances)
+}
+
+type instancesBody struct {
+	Instances []ClientInstance `json:"instances"`
+}
+
+func (a *InstancesAPI) handleInstances(w http.ResponseWriter, _ *http.Request) {
+	if a.list == nil {
+		http.Error(w, "instance tracking not available", http.StatusServiceUnavailable)
+		return
+	}
+	body := instancesBody{Instances: a.list()}
+	if body.Instances == nil {
+		body.Instances = make([]ClientInstance, 0)
+	}
+	writeJSON(w, http.StatusOK, body)
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"syscall"
+)
+
+// DefaultPort is the port the radar has always served on.
+const DefaultPort = 5001
+
+// ErrAddrInUse is returned by HTTPServer.Listen when another process already
+// holds the listen address.
+var ErrAddrInUse = errors.New("address already in use")
+
+// ListenAddr is where HTTPServer binds. An empty Host (or 0.0.0.0 / ::) listens
+// on every interface; a loopback Host keeps the radar private to this PC.
+type ListenAddr struct {
+	Host string
+	Port int
+}
+
+func (l ListenAddr) String() string {
+	return net.JoinHostPort(l.Host, strconv.Itoa(l.Port))
+}
+
+// Validate rejects ports outside 1-65535 and hosts that are not an IP literal
+// or "localhost".
+func (l ListenAddr) Validate() error {
+	if l.Port < 1 || l.Port > 65535 {
+		return fmt.Errorf("port %d out of range 1-65535", l.Port)
+	}
+	if l.Host != "" && l.Host != "localhost" && net.ParseIP(l.Host) == nil {
+		return fmt.Errorf("listen address %q is not an IP address", l.Host)
+	}
+	return nil
+}
+
+// IsLoopback reports whether only this PC can reach the server.
+func (l ListenAddr) IsLoopback() bool {
+	if l.Host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(l.Host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func (l ListenAddr) isWildcard() bool {
+	if l.Host == "" {
+		return true
+	}
+	ip := net.ParseIP(l.Host)
+	return ip != nil && ip.IsUnspecified()
+}
+
+// LocalHost is the host to put in URLs opened on this PC. A server bound to a
+// single LAN address does not answer on localhost, so that address is used.
+func (l ListenAddr) LocalHost() string {
+	if l.isWildcard() || l.IsLoopback() {
+		return "localhost"
+	}
+	return l.Host
+}
+
+// LocalURL is the server's URL on this PC for scheme ("http", "ws", ...).
+func (l ListenAddr) LocalURL(scheme string) string {
+	return HostURL(scheme, l.LocalHost(), l.Port)
+}
+
+// HostURL is scheme://host:port, with an IPv6 host in brackets.
+func HostURL(scheme, host string, port int) string {
+	return scheme + "://" + net.JoinHostPort(host, strconv.Itoa(port))
+}
+
+// ReachableLAN filters LAN addresses down to the ones the listener accepts
+// connections on: all of them for a wildcard bind, none in loopback-only mode.
+func (l ListenAddr) ReachableLAN(addrs []string) []string {
+	if l.isWildcard() {
+		return addrs
+	}
+	out := make([]string, 0, 1)
+	for _, a := range addrs {
+		if a == l.Host {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// isAddrInUse matches EADDRINUSE, and WSAEADDRINUSE (10048) which Windows
+// reports as a bare Errno that does not compare equal to syscall.EADDRINUSE.
+func isAddrInUse(err error) bool {
+	if errors.Is(err, syscall.EADDRINUSE) {
+		return true
+	}
+	var errno syscall.Errno
+	return errors.As(err, &errno) && errno == 10048
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"errors"
+	"net"
+	"testing"
+)
+
+func TestListenAddr_ReachableLAN(t *testing.T) {
+	lan := []string{"192.168.1.5", "10.0.0.3"}
+	cases := []struct {
+		host string
+		want []string
+	}{
+		{"", lan},
+		{"0.0.0.0", lan},
+		{"::", lan},
+		{"127.0.0.1", []string{}},
+		{"localhost", []string{}},
+		{"10.0.0.3", []string{"10.0.0.3"}},
+	}
+	for _, tc := range cases {
+		got := ListenAddr{Host: tc.host, Port: DefaultPort}.ReachableLAN(lan)
+		if len(got) != len(tc.want) {
+			t.Errorf("host %q: got %v, want %v", tc.host, got, tc.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tc.want[i] {
+				t.Errorf("host %q: got %v, want %v", tc.host, got, tc.want)
+			}
+		}
+	}
+}
+
+func TestListenAddr_LocalHost(t *testing.T) {
+	cases := map[string]string{
+		"":            "localhost",
+		"0.0.0.0":     "localhost",
+		"127.0.0.1":   "localhost",
+		"::1":         "localhost",
+		"192.168.1.5": "192.168.1.5",
+	}
+	for host, want := range cases {
+		if got := (ListenAddr{Host: host}).LocalHost(); got != want {
+			t.Errorf("LocalHost(%q) = %q, want %q", host, got, want)
+		}
+	}
+}
+
+func TestListenAddr_LocalURL(t *testing.T) {
+	cases := map[string]string{
+		"":            "http://localhost:8080",
+		"::1":         "http://localhost:8080",
+		"192.168.1.5": "http://192.168.1.5:8080",
+		"2001:db8::5": "http://[2001:db8::5]:8080",
+	}
+	for host, want := range cases {
+		if got := (ListenAddr{Host: host, Port: 8080}).LocalURL("http"); got != want {
+			t.Errorf("LocalURL(%q) = %q, want %q", host, got, want)
+		}
+	}
+	if got := HostURL("wss", "fd00::7", 5001); got != "wss://[fd00::7]:5001" {
+		t.Errorf("HostURL = %q", got)
+	}
+}
+
+func TestListenAddr_Validate(t *testing.T) {
+	valid := []ListenAddr{{"", 5001}, {"127.0.0.1", 1}, {"::1", 65535}, {"localhost", 8080}}
+	for _, l := range valid {
+		if err := l.Validate(); err != nil {
+			t.Errorf("%+v: unexpected error %v", l, err)
+		}
+	}
+	invalid := []ListenAddr{{"", 0}, {"", 70000}, {"my-pc.lan", 5001}}
+	for _, l := range invalid {
+		if err := l.Validate(); err == nil {
+			t.Errorf("%+v: want error", l)
+		}
+	}
+}
+
+func TestHTTPServer_ListenReportsPortInUse(t *testing.T) {
+	held, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("hold port: %v", err)
+	}
+	defer held.Close()
+	port := held.Addr().(*net.TCPAddr).Port
+
+	s := &HTTPServer{listen: ListenAddr{Host: "127.0.0.1", Port: port}}
+	err = s.Listen()
+	if !errors.Is(err, ErrAddrInUse) {
+		t.Fatalf("Listen on a held port: got %v, want ErrAddrInUse", err)
+	}
+	if s.listener != nil {
+		t.Error("listener must stay nil after a failed bind")
+	}
+}
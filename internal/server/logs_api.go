@@ -0,0 +1,129 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nospy/albion-openradar/internal/logger"
+)
+
+// LogsAPI reads the JSONL session logs back, for bug reports. It is open to
+// the LAN like the rest of the read-only API, as the radar may run on another
+// machine than the browser.
+type LogsAPI struct {
+	logger *logger.Logger
+}
+
+func NewLogsAPI(log *logger.Logger) *LogsAPI {
+	return &LogsAPI{logger: log}
+}
+
+func (a *LogsAPI) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/logs", a.handleQuery)
+	mux.HandleFunc("GET /api/logs/sessions", a.handleSessions)
+}
+
+func (a *LogsAPI) handleSessions(w http.ResponseWriter, _ *http.Request) {
+	sessions, err := a.logger.Sessions()
+	if err != nil {
+		http.Error(w, "list sessions: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, sessions)
+}
+
+// handleQuery streams matching lines as NDJSON. Query parameters: session
+// (default current), source=server|client, level (comma-separated), category,
+// since and until (RFC 3339), q (text search), limit, and download=1 for an
+// attachment.
+func (a *LogsAPI) handleQuery(w http.ResponseWriter, r *http.Request) {
+	q, err := parseLogQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	flusher, _ := w.(http.Flusher)
+	started := false
+	sent := 0
+	start := func() {
+		started = true
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		if r.URL.Query().Get("download") == "1" {
+			id := q.Session
+			if id == "" {
+				id = a.logger.SessionID()
+			}
+			w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="openradar_logs_%s.jsonl"`, id))
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+	err = a.logger.Query(q, func(line []byte) error {
+		if !started {
+			start()
+		}
+		if _, err := w.Write(append(line, '\n')); err != nil {
+			return err
+		}
+		if sent++; flusher != nil && sent%200 == 0 {
+			flusher.Flush()
+		}
+		return nil
+	})
+	switch {
+	case err == nil:
+		if !started {
+			start()
+		}
+	case started:
+		// Headers are gone; the client sees a truncated stream.
+		logger.PrintWarn("HTTP", "Log export stopped: %v", err)
+	case errors.Is(err, logger.ErrUnknownSession):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		http.Error(w, "query logs: "+err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func parseLogQuery(r *http.Request) (logger.Query, error) {
+	v := r.URL.Query()
+	q := logger.Query{
+		Session:  v.Get("session"),
+		Source:   v.Get("source"),
+		Category: v.Get("category"),
+		Text:     v.Get("q"),
+	}
+	switch q.Source {
+	case "", "server", "client":
+	default:
+		return q, errors.New("source must be server or client")
+	}
+	if s := v.Get("level"); s != "" {
+		for _, lv := range strings.Split(s, ",") {
+			if lv = strings.TrimSpace(lv); lv != "" {
+				q.Levels = append(q.Levels, strings.ToUpper(lv))
+			}
+		}
+	}
+	for name, dst := range map[string]*time.Time{"since": &q.Since, "until": &q.Until} {
+		if s := v.Get(name); s != "" {
+			t, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				return q, fmt.Errorf("%s must be an RFC 3339 time", name)
+			}
+			*dst = t
+		}
+	}
+	if s := v.Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			return q, errors.New("limit must be a non-negative integer")
+		}
+		q.Limit = n
+	}
+	return q, nil
+}
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/nospy/albion-openradar/internal/logger"
+)
+
+func TestLogsAPI_QueryAndDownload(t *testing.T) {
+	dir := t.TempDir()
+	log := logger.New(dir, true)
+	t.Cleanup(func() { log.Stop() })
+	log.Warn("NET", "Some interfaces failed to open", nil, nil)
+	log.Info("APP", "Starting servers", nil, nil)
+
+	mux := http.NewServeMux()
+	NewLogsAPI(log).Register(mux)
+	get := func(url string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+		return rec
+	}
+
+	rec := get("/api/logs?level=warn")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "application/x-ndjson", rec.Header().Get("Content-Type"))
+	lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+	require.Len(t, lines, 1)
+	require.Contains(t, lines[0], "Some interfaces failed to open")
+
+	rec = get("/api/logs?download=1&source=server")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Header().Get("Content-Disposition"), "openradar_logs_"+log.SessionID()+".jsonl")
+	require.Equal(t, 2, strings.Count(rec.Body.String(), "\n"))
+
+	require.Equal(t, http.StatusBadRequest, get("/api/logs?since=yesterday").Code)
+	require.Equal(t, http.StatusBadRequest, get("/api/logs?source=disk").Code)
+	require.Equal(t, http.StatusNotFound, get("/api/logs?session=2020-01-01T00-00-00").Code)
+	require.Equal(t, http.StatusNotFound, get("/api/logs?session=..%2F..%2Fnetwork").Code)
+
+	rec = get("/api/logs/sessions")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `"id":"`+log.SessionID()+`","start"`)
+	require.Contains(t, rec.Body.String(), `"current":true`)
+	require.Contains(t, rec.Body.String(), `"lineCount":2`)
+}
+
+func TestLogsAPI_EmptyCurrentSession(t *testing.T) {
+	dir := t.TempDir()
+	log := logger.New(dir, false)
+	t.Cleanup(func() { log.Stop() })
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "sessions"), 0o755))
+
+	mux := http.NewServeMux()
+	NewLogsAPI(log).Register(mux)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/logs", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Empty(t, rec.Body.String())
+}
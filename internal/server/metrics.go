@@ -0,0 +1,89 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Label is one name="value" pair on a metric sample.
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Sample is one value of a metric family.
+type Sample struct {
+	Labels []Label
+	Value  float64
+}
+
+// Value is a sample without labels.
+func Value[T uint64 | int | float64](v T) Sample {
+	return Sample{Value: float64(v)}
+}
+
+// MetricsWriter renders metric families in the Prometheus text exposition
+// format. Each family is written once, with all of its samples, as the
+// format requires.
+type MetricsWriter struct {
+	buf bytes.Buffer
+}
+
+// Counter writes a monotonically increasing family. Names end in _total.
+func (m *MetricsWriter) Counter(name, help string, samples ...Sample) {
+	m.family(name, "counter", help, samples)
+}
+
+// Gauge writes a family whose value can go up and down.
+func (m *MetricsWriter) Gauge(name, help string, samples ...Sample) {
+	m.family(name, "gauge", help, samples)
+}
+
+func (m *MetricsWriter) family(name, typ, help string, samples []Sample) {
+	fmt.Fprintf(&m.buf, "# HELP %s %s\n# TYPE %s %s\n", name, escapeHelp(help), name, typ)
+	for _, s := range samples {
+		m.buf.WriteString(name)
+		if len(s.Labels) > 0 {
+			m.buf.WriteByte('{')
+			for i, l := range s.Labels {
+				if i > 0 {
+					m.buf.WriteByte(',')
+				}
+				fmt.Fprintf(&m.buf, "%s=\"%s\"", l.Name, escapeLabel(l.Value))
+			}
+			m.buf.WriteByte('}')
+		}
+		m.buf.WriteByte(' ')
+		m.buf.WriteString(strconv.FormatFloat(s.Value, 'g', -1, 64))
+		m.buf.WriteByte('\n')
+	}
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string  { return helpEscaper.Replace(s) }
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
+
+// SetMetrics installs the collector behind GET /metrics. Call it before
+// Start; until then the route answers 404.
+func (s *HTTPServer) SetMetrics(collect func(*MetricsWriter)) {
+	s.metrics = collect
+}
+
+func (s *HTTPServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if s.metrics == nil {
+		http.NotFound(w, r)
+		return
+	}
+	var m MetricsWriter
+	s.metrics(&m)
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	_, _ = w.Write(m.buf.Bytes())
+}
@@ -0,0 +1,47 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsWriterExposition(t *testing.T) {
+	var m MetricsWriter
+	m.Counter("openradar_events_total", "Decoded events.",
+		Sample{Labels: []Label{{Name: "code", Value: "29"}}, Value: 12},
+		Sample{Labels: []Label{{Name: "code", Value: "3"}}, Value: 4000000})
+	m.Gauge("openradar_ws_clients", "Connected clients.", Value(2))
+	m.Gauge("openradar_interface_up", "Escaping.",
+		Sample{Labels: []Label{{Name: "interface", Value: `\Device\NPF_{A"B}`}}, Value: 1})
+
+	require.Equal(t, `# HELP openradar_events_total Decoded events.
+# TYPE openradar_events_total counter
+openradar_events_total{code="29"} 12
+openradar_events_total{code="3"} 4e+06
+# HELP openradar_ws_clients Connected clients.
+# TYPE openradar_ws_clients gauge
+openradar_ws_clients 2
+# HELP openradar_interface_up Escaping.
+# TYPE openradar_interface_up gauge
+openradar_interface_up{interface="\\Device\\NPF_{A\"B}"} 1
+`, m.buf.String())
+}
+
+func TestMetricsRoute(t *testing.T) {
+	s := &HTTPServer{}
+	rec := httptest.NewRecorder()
+	s.handleMetrics(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusNotFound, rec.Code, "no collector installed yet")
+
+	s.SetMetrics(func(m *MetricsWriter) { m.Counter("openradar_packets_processed_total", "Parsed.", Value(uint64(7))) })
+	rec = httptest.NewRecorder()
+	s.handleMetrics(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+	body, _ := io.ReadAll(rec.Body)
+	require.Contains(t, string(body), "openradar_packets_processed_total 7\n")
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
@@ -14,6 +15,11 @@ import (
 type NetworkManager interface {
 	State() capture.State
 	Reconfigure([]capture.NetworkInterface) error
+	// SetFilter validates the capture filter and reopens the capturers with
+	// it; a rejected filter wraps capture.ErrInvalidFilter.
+	SetFilter(capture.FilterConfig) error
+	// Open captures on one more interface, leaving the others running.
+	Open(capture.NetworkInterface) error
 }
 
 type LANAddrFn func() []string
@@ -24,10 +30,23 @@ type NetworkAPI struct {
 	all      []capture.NetworkInterface
 	appDir   string
 	lanAddrs LANAddrFn
+	// serverPort and serverScheme are echoed in the state so the settings page
+	// builds LAN links on what is actually served rather than the defaults.
+	serverPort     int
+	serverScheme   string
+	tlsFingerprint string
+
+	// selectMu keeps the watcher from reopening an interface that Select is
+	// in the middle of dropping.
+	selectMu sync.Mutex
+	// reopen, watchFailing and onChange belong to the watcher; see Watch.
+	reopen       map[string]*reopenState
+	watchFailing bool
+	onChange     func(InterfaceChange)
 }
 
 func NewNetworkAPI(mgr NetworkManager, all []capture.NetworkInterface, appDir string, lan LANAddrFn) *NetworkAPI {
-	return &NetworkAPI{mgr: mgr, all: all, appDir: appDir, lanAddrs: lan}
+	return &NetworkAPI{mgr: mgr, all: all, appDir: appDir, lanAddrs: lan, serverPort: DefaultPort, serverScheme: "http"}
 }
 
 func (a *NetworkAPI) Register(mux *http.ServeMux) {
@@ -35,18 +54,32 @@ func (a *NetworkAPI) Register(mux *http.ServeMux) {
 	mux.HandleFunc("POST /api/network/interfaces", a.handleSelect)
 	mux.HandleFunc("GET /api/network/state", a.handleState)
 	mux.HandleFunc("POST /api/network/refresh", a.handleRefresh)
+	mux.HandleFunc("GET /api/network/filter", a.handleGetFilter)
+	mux.HandleFunc("POST /api/network/filter", a.handleSetFilter)
 }
 
-type ifaceRow struct {
+// InterfaceRow is one selectable interface, as listed on the settings page.
+type InterfaceRow struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 	Address     string `json:"address"`
-	Category    string `json:"category"`
-	IsPersisted bool   `json:"isPersisted"`
-	IsAvailable bool   `json:"isAvailable"`
+	// Addresses lists every IPv4 and IPv6 address, Address first.
+	Addresses   []string `json:"addresses"`
+	Category    string   `json:"category"`
+	IsPersisted bool     `json:"isPersisted"`
+	IsAvailable bool     `json:"isAvailable"`
 }
 
+// errUnknownInterface marks names that are not in the enumerated list.
+var errUnknownInterface = errors.New("unknown interface names")
+
 func (a *NetworkAPI) handleList(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, a.Interfaces())
+}
+
+// Interfaces lists the enumerated interfaces, best candidates first, with
+// whether each one is saved in network.json.
+func (a *NetworkAPI) Interfaces() []InterfaceRow {
 	a.mu.RLock()
 	snapshot := make([]capture.NetworkInterface, len(a.all))
 	copy(snapshot, a.all)
@@ -61,24 +94,28 @@ func (a *NetworkAPI) handleList(w http.ResponseWriter, _ *http.Request) {
 	for _, i := range snapshot {
 		available[i.Name] = true
 	}
-	rows := make([]ifaceRow, 0, len(snapshot))
+	rows := make([]InterfaceRow, 0, len(snapshot))
 	for _, i := range capture.RankCandidates(snapshot) {
-		rows = append(rows, ifaceRow{
+		rows = append(rows, InterfaceRow{
 			Name:        i.Name,
 			Description: i.Description,
 			Address:     i.Address,
+			Addresses:   i.AllAddresses(),
 			Category:    string(capture.Categorize(i.Name, i.Description)),
 			IsPersisted: persisted[i.Name],
 			IsAvailable: available[i.Name],
 		})
 	}
-	writeJSON(w, http.StatusOK, rows)
+	return rows
 }
 
 type stateBody struct {
 	CaptureInterfaces []capture.CaptureSummary `json:"captureInterfaces"`
 	IsCapturing       bool                     `json:"isCapturing"`
 	LanAddresses      []string                 `json:"lanAddresses"`
+	ServerPort        int                      `json:"serverPort"`
+	ServerScheme      string                   `json:"serverScheme"`
+	TLSFingerprint    string                   `json:"tlsFingerprint,omitempty"`
 	LastErrors        map[string]string        `json:"lastErrors"`
 	Status            string                   `json:"status"`
 }
@@ -89,6 +126,9 @@ func (a *NetworkAPI) handleState(w http.ResponseWriter, _ *http.Request) {
 		CaptureInterfaces: s.Active,
 		IsCapturing:       len(s.Active) > 0,
 		LanAddresses:      a.lanAddrs(),
+		ServerPort:        a.serverPort,
+		ServerScheme:      a.serverScheme,
+		TLSFingerprint:    a.tlsFingerprint,
 		LastErrors:        s.LastErrors,
 		Status:            string(s.Status),
 	}
@@ -109,15 +149,31 @@ func (a *NetworkAPI) handleSelect(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "invalid body: "+err.Error(), http.StatusBadRequest)
 		return
 	}
+	if err := a.Select(body.Names); err != nil {
+		code := http.StatusInternalServerError
+		if errors.Is(err, errUnknownInterface) {
+			code = http.StatusBadRequest
+		}
+		http.Error(w, err.Error(), code)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// Select captures on the named interfaces and saves them to network.json.
+// Callers check where the request comes from; the TUI always runs on the host.
+func (a *NetworkAPI) Select(names []string) error {
+	a.selectMu.Lock()
+	defer a.selectMu.Unlock()
 	a.mu.RLock()
 	available := make(map[string]capture.NetworkInterface, len(a.all))
 	for _, i := range a.all {
 		available[i.Name] = i
 	}
 	a.mu.RUnlock()
-	desired := make([]capture.NetworkInterface, 0, len(body.Names))
+	desired := make([]capture.NetworkInterface, 0, len(names))
 	var unknown []string
-	for _, name := range body.Names {
+	for _, name := range names {
 		if i, ok := available[name]; ok {
 			desired = append(desired, i)
 		} else {
@@ -125,12 +181,10 @@ func (a *NetworkAPI) handleSelect(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 	if len(unknown) > 0 {
-		http.Error(w, fmt.Sprintf("unknown interface names: %v", unknown), http.StatusBadRequest)
-		return
+		return fmt.Errorf("%w: %v", errUnknownInterface, unknown)
 	}
 	if err := a.mgr.Reconfigure(desired); err != nil {
-		http.Error(w, "reconfigure: "+err.Error(), http.StatusInternalServerError)
-		return
+		return fmt.Errorf("reconfigure: %w", err)
 	}
 	persisted := make([]capture.PersistedInterface, 0, len(desired))
 	for _, i := range desired {
@@ -138,22 +192,75 @@ func (a *NetworkAPI) handleSelect(w http.ResponseWriter, r *http.Request) {
 	}
 	if err := capture.MutateConfig(a.appDir, func(cfg *capture.Config) {
 		cfg.CaptureInterfaces = persisted
+	}); err != nil {
+		return fmt.Errorf("persist: %w", err)
+	}
+	return nil
+}
+
+// filterBody is the saved capture filter, with the BPF expression it makes.
+type filterBody struct {
+	Ports      []uint16 `json:"ports"`
+	ExtraBPF   string   `json:"extraBPF"`
+	Expression string   `json:"expression"`
+	// ReopenError is set when the filter was saved but reopening the
+	// capturers with it failed.
+	ReopenError string `json:"reopenError,omitempty"`
+}
+
+func newFilterBody(f capture.FilterConfig) filterBody {
+	return filterBody{Ports: f.PortsOrDefault(), ExtraBPF: f.ExtraBPF, Expression: f.Expression()}
+}
+
+func (a *NetworkAPI) handleGetFilter(w http.ResponseWriter, _ *http.Request) {
+	cfg, err := capture.ReadConfig(a.appDir)
+	if err != nil {
+		http.Error(w, "read config: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, newFilterBody(cfg.CaptureFilter))
+}
+
+// handleSetFilter applies a new capture filter at once and saves it. The
+// filter is compiled first, so a typo leaves capture as it was. Once it
+// compiled it is in force and saved, even when reopening a capturer fails;
+// that failure comes back in reopenError.
+func (a *NetworkAPI) handleSetFilter(w http.ResponseWriter, r *http.Request) {
+	if !isLoopback(r.RemoteAddr) {
+		http.Error(w, "the capture filter can only be changed from the host PC", http.StatusForbidden)
+		return
+	}
+	var f capture.FilterConfig
+	if err := json.NewDecoder(r.Body).Decode(&f); err != nil {
+		http.Error(w, "invalid body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	f.ExtraBPF = strings.TrimSpace(f.ExtraBPF)
+	reopenErr := a.mgr.SetFilter(f)
+	if errors.Is(reopenErr, capture.ErrInvalidFilter) {
+		http.Error(w, reopenErr.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := capture.MutateConfig(a.appDir, func(cfg *capture.Config) {
+		cfg.CaptureFilter = f
 	}); err != nil {
 		http.Error(w, "persist: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
-	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	body := newFilterBody(f)
+	if reopenErr != nil {
+		body.ReopenError = reopenErr.Error()
+	}
+	writeJSON(w, http.StatusOK, body)
 }
 
 func (a *NetworkAPI) handleRefresh(w http.ResponseWriter, _ *http.Request) {
-	fresh, err := capture.EnumerateInterfaces()
+	fresh, err := enumerateInterfaces()
 	if err != nil {
 		http.Error(w, "enumerate: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
-	a.mu.Lock()
-	a.all = fresh
-	a.mu.Unlock()
+	a.report(a.setInterfaces(fresh))
 	a.handleList(w, nil)
 }
 
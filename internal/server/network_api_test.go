@@ -3,6 +3,7 @@ package server
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -17,6 +18,10 @@ type fakeManager struct {
 	reconfArgs    []capture.NetworkInterface
 	reconfErr     error
 	allInterfaces []capture.NetworkInterface
+	filter        capture.FilterConfig
+	filterErr     error
+	opened        []string
+	openErr       error
 }
 
 func (f *fakeManager) State() capture.State { return f.state }
@@ -25,6 +30,19 @@ func (f *fakeManager) Reconfigure(t []capture.NetworkInterface) error {
 	return f.reconfErr
 }
 
+func (f *fakeManager) Open(i capture.NetworkInterface) error {
+	f.opened = append(f.opened, i.Name)
+	return f.openErr
+}
+
+func (f *fakeManager) SetFilter(c capture.FilterConfig) error {
+	if errors.Is(f.filterErr, capture.ErrInvalidFilter) {
+		return f.filterErr
+	}
+	f.filter = c
+	return f.filterErr
+}
+
 func newTestMux(api *NetworkAPI) *http.ServeMux {
 	mux := http.NewServeMux()
 	api.Register(mux)
@@ -34,7 +52,7 @@ func newTestMux(api *NetworkAPI) *http.ServeMux {
 func TestNetworkAPI_ListReturnsCategorized(t *testing.T) {
 	fm := &fakeManager{
 		allInterfaces: []capture.NetworkInterface{
-			{Name: "n1", Description: "Wi-Fi", Address: "192.168.1.1"},
+			{Name: "n1", Description: "Wi-Fi", Address: "192.168.1.1", Addresses: []string{"192.168.1.1", "fe80::1"}},
 			{Name: "n2", Description: "Realtek PCIe GbE Family Controller", Address: "192.168.1.2"},
 		},
 		state: capture.State{
@@ -60,6 +78,13 @@ func TestNetworkAPI_ListReturnsCategorized(t *testing.T) {
 		if row["category"] == "" {
 			t.Errorf("missing category in %+v", row)
 		}
+		addrs, _ := row["addresses"].([]any)
+		if len(addrs) == 0 || addrs[0] != row["address"] {
+			t.Errorf("addresses %v should start with address %v", row["addresses"], row["address"])
+		}
+		if row["name"] == "n1" && (len(addrs) != 2 || addrs[1] != "fe80::1") {
+			t.Errorf("n1 addresses = %v, want the IPv6 one too", addrs)
+		}
 	}
 }
 
@@ -134,6 +159,9 @@ func TestNetworkAPI_StateShape(t *testing.T) {
 	if body["lanAddresses"] == nil {
 		t.Error("lanAddresses missing")
 	}
+	if body["serverPort"] != float64(DefaultPort) {
+		t.Errorf("serverPort=%v, want %d", body["serverPort"], DefaultPort)
+	}
 	active, ok := body["captureInterfaces"].([]any)
 	if !ok || len(active) != 1 {
 		t.Fatalf("captureInterfaces shape: %T %v", body["captureInterfaces"], body["captureInterfaces"])
@@ -294,3 +322,58 @@ func TestNetworkAPI_RefreshConcurrentSafe(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestNetworkAPI_Filter(t *testing.T) {
+	fm := &fakeManager{}
+	dir := t.TempDir()
+	mux := newTestMux(NewNetworkAPI(fm, nil, dir, func() []string { return nil }))
+	post := func(remote, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/network/filter", strings.NewReader(body))
+		req.RemoteAddr = remote
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/network/filter", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `udp and (port 5056)`) {
+		t.Fatalf("default filter: %d %s", rec.Code, rec.Body.String())
+	}
+
+	if rec := post("192.168.1.9:1234", `{"ports":[5055]}`); rec.Code != http.StatusForbidden {
+		t.Errorf("LAN POST: status %d, want 403", rec.Code)
+	}
+
+	fm.filterErr = capture.ErrInvalidFilter
+	if rec := post("127.0.0.1:1234", `{"extraBPF":"bogus"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("rejected filter: status %d, want 400", rec.Code)
+	}
+	if cfg, _ := capture.ReadConfig(dir); cfg.CaptureFilter.ExtraBPF != "" {
+		t.Error("a rejected filter was saved")
+	}
+
+	fm.filterErr = nil
+	rec = post("127.0.0.1:1234", `{"ports":[5056,5055],"extraBPF":" udp port 4535 "}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d, body=%s", rec.Code, rec.Body.String())
+	}
+	want := capture.FilterConfig{Ports: []uint16{5056, 5055}, ExtraBPF: "udp port 4535"}
+	if fm.filter.ExtraBPF != want.ExtraBPF || len(fm.filter.Ports) != 2 {
+		t.Errorf("applied %+v, want %+v", fm.filter, want)
+	}
+	cfg, err := capture.ReadConfig(dir)
+	if err != nil || cfg.CaptureFilter.ExtraBPF != want.ExtraBPF || len(cfg.CaptureFilter.Ports) != 2 {
+		t.Errorf("saved %+v (%v), want %+v", cfg.CaptureFilter, err, want)
+	}
+
+	// A capturer that will not reopen does not undo the saved filter.
+	fm.filterErr = errors.New("reopen eth: adapter gone")
+	rec = post("127.0.0.1:1234", `{"ports":[5057]}`)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"reopenError":"reopen eth: adapter gone"`) {
+		t.Fatalf("reopen failure: %d %s", rec.Code, rec.Body.String())
+	}
+	if cfg, _ := capture.ReadConfig(dir); len(cfg.CaptureFilter.Ports) != 1 || cfg.CaptureFilter.Ports[0] != 5057 {
+		t.Errorf("saved %+v, want the filter in force", cfg.CaptureFilter)
+	}
+}
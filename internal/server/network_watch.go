@@ -0,0 +1,232 @@
+package server
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/nospy/albion-openradar/internal/capture"
+	"github.com/nospy/albion-openradar/internal/logger"
+)
+
+const (
+	// InterfaceWatchInterval is how often the watcher re-enumerates the
+	// interfaces.
+	InterfaceWatchInterval = 5 * time.Second
+	// reopenBackoffMin and reopenBackoffMax bound the wait between two
+	// attempts at reopening the same interface; it doubles on every attempt.
+	reopenBackoffMin = 5 * time.Second
+	reopenBackoffMax = 2 * time.Minute
+	// reopenStableAfter is how long a reopened capturer must run before its
+	// backoff starts over.
+	reopenStableAfter = time.Minute
+)
+
+// enumerateInterfaces lists the host's interfaces; overridable in tests,
+// restore via t.Cleanup.
+var enumerateInterfaces = capture.EnumerateInterfaces
+
+// InterfaceChange is what one pass of the watcher, or a refresh, found:
+// interfaces that appeared, went away or changed address, and the saved ones
+// it reopened or failed to. Every list holds interface names.
+type InterfaceChange struct {
+	Added    []string          `json:"added,omitempty"`
+	Removed  []string          `json:"removed,omitempty"`
+	Changed  []string          `json:"changed,omitempty"`
+	Reopened []string          `json:"reopened,omitempty"`
+	Failed   map[string]string `json:"failed,omitempty"`
+
+	// labels names the interfaces in the log lines.
+	labels map[string]string
+}
+
+// Empty reports whether nothing changed.
+func (c InterfaceChange) Empty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Changed) == 0 &&
+		len(c.Reopened) == 0 && len(c.Failed) == 0
+}
+
+// reopenState is the backoff of one saved interface that is not captured.
+type reopenState struct {
+	attempts int
+	next     time.Time
+}
+
+func reopenBackoff(attempts int) time.Duration {
+	d := reopenBackoffMin
+	for range attempts - 1 {
+		if d *= 2; d >= reopenBackoffMax {
+			return reopenBackoffMax
+		}
+	}
+	return d
+}
+
+// OnInterfacesChange registers fn to run after a watcher pass or a refresh
+// that changed something. Call before Watch.
+func (a *NetworkAPI) OnInterfacesChange(fn func(InterfaceChange)) {
+	a.onChange = fn
+}
+
+// Watch re-enumerates the interfaces every interval until ctx ends, so a
+// Wi-Fi switch or a VPN reconnect shows up without a manual refresh. With
+// reopen, the interfaces saved in network.json that are not captured (gone
+// at startup, or whose capturer died) are opened again as soon as they are
+// listed, backing off while opening keeps failing. It is off for a -ip run,
+// which does not capture what network.json lists.
+func (a *NetworkAPI) Watch(ctx context.Context, interval time.Duration, reopen bool) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			a.watchOnce(time.Now(), reopen)
+		}
+	}
+}
+
+func (a *NetworkAPI) watchOnce(now time.Time, reopen bool) {
+	fresh, err := enumerateInterfaces()
+	if err != nil {
+		// Once per failure streak, not every pass.
+		if !a.watchFailing {
+			logger.PrintWarn("NET", "Interface watch: %v", err)
+		}
+		a.watchFailing = true
+		return
+	}
+	a.watchFailing = false
+	change := a.setInterfaces(fresh)
+	if reopen {
+		a.reopenSaved(fresh, now, &change)
+	}
+	a.report(change)
+}
+
+// setInterfaces replaces the enumerated list and returns how it differs
+// from the previous one.
+func (a *NetworkAPI) setInterfaces(fresh []capture.NetworkInterface) InterfaceChange {
+	change := InterfaceChange{labels: make(map[string]string)}
+	a.mu.Lock()
+	prev := make(map[string]capture.NetworkInterface, len(a.all))
+	for _, i := range a.all {
+		prev[i.Name] = i
+	}
+	a.all = fresh
+	a.mu.Unlock()
+
+	for _, i := range fresh {
+		change.labels[i.Name] = interfaceLabel(i)
+		old, seen := prev[i.Name]
+		delete(prev, i.Name)
+		switch {
+		case !seen:
+			change.Added = append(change.Added, i.Name)
+		case !slices.Equal(old.AllAddresses(), i.AllAddresses()):
+			change.Changed = append(change.Changed, i.Name)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(prev)) {
+		change.labels[name] = interfaceLabel(prev[name])
+		change.Removed = append(change.Removed, name)
+	}
+	return change
+}
+
+// reopenSaved opens the saved interfaces that are listed but not captured.
+func (a *NetworkAPI) reopenSaved(all []capture.NetworkInterface, now time.Time, change *InterfaceChange) {
+	a.selectMu.Lock()
+	defer a.selectMu.Unlock()
+	cfg, err := capture.ReadConfig(a.appDir)
+	if err != nil {
+		return
+	}
+	available := make(map[string]capture.NetworkInterface, len(all))
+	for _, i := range all {
+		available[i.Name] = i
+	}
+	running := make(map[string]time.Time)
+	for _, s := range a.mgr.State().Active {
+		running[s.Name] = s.StartedAt
+	}
+	if a.reopen == nil {
+		a.reopen = make(map[string]*reopenState)
+	}
+	saved := make(map[string]bool, len(cfg.CaptureInterfaces))
+	for _, p := range cfg.CaptureInterfaces {
+		saved[p.Name] = true
+		iface, ok := available[p.Name]
+		if !ok {
+			continue
+		}
+		if started, ok := running[p.Name]; ok {
+			if now.Sub(started) >= reopenStableAfter {
+				delete(a.reopen, p.Name)
+			}
+			continue
+		}
+		st := a.reopen[p.Name]
+		if st == nil {
+			st = &reopenState{}
+			a.reopen[p.Name] = st
+		}
+		if now.Before(st.next) {
+			continue
+		}
+		st.attempts++
+		st.next = now.Add(reopenBackoff(st.attempts))
+		change.labels[p.Name] = interfaceLabel(iface)
+		if err := a.mgr.Open(iface); err != nil {
+			if change.Failed == nil {
+				change.Failed = make(map[string]string)
+			}
+			change.Failed[p.Name] = err.Error()
+			continue
+		}
+		change.Reopened = append(change.Reopened, p.Name)
+	}
+	for name := range a.reopen {
+		if !saved[name] {
+			delete(a.reopen, name)
+		}
+	}
+}
+
+// report logs a change and hands it to the OnInterfacesChange callback.
+func (a *NetworkAPI) report(c InterfaceChange) {
+	if c.Empty() {
+		return
+	}
+	for _, n := range c.Added {
+		logger.PrintInfo("NET", "Interface appeared: %s", c.labels[n])
+	}
+	for _, n := range c.Removed {
+		logger.PrintWarn("NET", "Interface went away: %s", c.labels[n])
+	}
+	for _, n := range c.Changed {
+		logger.PrintInfo("NET", "Interface address changed: %s", c.labels[n])
+	}
+	for _, n := range c.Reopened {
+		logger.PrintSuccess("NET", "Capture reopened on %s", c.labels[n])
+	}
+	for _, n := range slices.Sorted(maps.Keys(c.Failed)) {
+		logger.PrintWarn("NET", "Could not reopen %s: %s", c.labels[n], c.Failed[n])
+	}
+	if a.onChange != nil {
+		a.onChange(c)
+	}
+}
+
+func interfaceLabel(i capture.NetworkInterface) string {
+	name := i.Description
+	if name == "" {
+		name = i.Name
+	}
+	if i.Address == "" {
+		return name
+	}
+	return name + " (" + i.Address + ")"
+}
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/nospy/albion-openradar/internal/capture"
+)
+
+// withStubEnumerate makes the watcher see *list on every pass.
+func withStubEnumerate(t *testing.T, list *[]capture.NetworkInterface) {
+	t.Helper()
+	prev := enumerateInterfaces
+	enumerateInterfaces = func() ([]capture.NetworkInterface, error) { return *list, nil }
+	t.Cleanup(func() { enumerateInterfaces = prev })
+}
+
+func TestNetworkWatch_ReportsChanges(t *testing.T) {
+	list := []capture.NetworkInterface{
+		{Name: "eth", Description: "Ethernet", Address: "10.0.0.1"},
+		{Name: "wifi", Description: "Wi-Fi", Address: "192.168.1.2"},
+	}
+	withStubEnumerate(t, &list)
+	fm := &fakeManager{}
+	api := NewNetworkAPI(fm, list, t.TempDir(), func() []string { return nil })
+	var got []InterfaceChange
+	api.OnInterfacesChange(func(c InterfaceChange) { got = append(got, c) })
+
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	api.watchOnce(now, false)
+	if len(got) != 0 {
+		t.Fatalf("nothing changed, got %+v", got)
+	}
+
+	list = []capture.NetworkInterface{
+		{Name: "eth", Description: "Ethernet", Address: "10.0.0.7"},
+		{Name: "tun0", Description: "WireGuard", Address: "fd00::2"},
+	}
+	api.watchOnce(now.Add(InterfaceWatchInterval), false)
+	if len(got) != 1 {
+		t.Fatalf("got %d changes, want 1", len(got))
+	}
+	c := got[0]
+	if !slices.Equal(c.Added, []string{"tun0"}) || !slices.Equal(c.Removed, []string{"wifi"}) || !slices.Equal(c.Changed, []string{"eth"}) {
+		t.Errorf("added %v, removed %v, changed %v", c.Added, c.Removed, c.Changed)
+	}
+	if rows := api.Interfaces(); len(rows) != 2 {
+		t.Errorf("the listed interfaces were not replaced: %+v", rows)
+	}
+	if len(fm.opened) != 0 {
+		t.Errorf("opened %v without reopen", fm.opened)
+	}
+}
+
+func TestNetworkWatch_ReopensSavedInterfacesWithBackoff(t *testing.T) {
+	var list []capture.NetworkInterface
+	withStubEnumerate(t, &list)
+	dir := t.TempDir()
+	if err := capture.MutateConfig(dir, func(c *capture.Config) {
+		c.CaptureInterfaces = []capture.PersistedInterface{{Name: "tun0"}, {Name: "eth"}}
+	}); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	fm := &fakeManager{state: capture.State{Active: []capture.CaptureSummary{{Name: "eth", StartedAt: start}}}}
+	api := NewNetworkAPI(fm, nil, dir, func() []string { return nil })
+	var got []InterfaceChange
+	api.OnInterfacesChange(func(c InterfaceChange) { got = append(got, c) })
+
+	// tun0 is gone: nothing to open.
+	list = []capture.NetworkInterface{{Name: "eth", Description: "Ethernet", Address: "10.0.0.1"}}
+	api.watchOnce(start, true)
+	if len(fm.opened) != 0 {
+		t.Fatalf("opened %v while tun0 was missing", fm.opened)
+	}
+
+	// It comes back but will not open yet.
+	list = append(list, capture.NetworkInterface{Name: "tun0", Description: "WireGuard", Address: "10.8.0.2"})
+	fm.openErr = errors.New("adapter not ready")
+	api.watchOnce(start, true)
+	if !slices.Equal(fm.opened, []string{"tun0"}) {
+		t.Fatalf("opened %v, want tun0 only (eth is running)", fm.opened)
+	}
+	last := got[len(got)-1]
+	if !slices.Equal(last.Added, []string{"tun0"}) || last.Failed["tun0"] != "adapter not ready" {
+		t.Errorf("change %+v, want tun0 added and failed", last)
+	}
+
+	for _, pass := range []struct {
+		after time.Duration
+		open  bool
+	}{
+		{time.Second, false},
+		{reopenBackoffMin, true},
+		{reopenBackoffMin + time.Second, false},
+		{3 * reopenBackoffMin, true}, // backoff doubled to 10s
+	} {
+		n := len(fm.opened)
+		api.watchOnce(start.Add(pass.after), true)
+		if opened := len(fm.opened) > n; opened != pass.open {
+			t.Errorf("after %v: attempted %v, want %v", pass.after, opened, pass.open)
+		}
+	}
+
+	fm.openErr = nil
+	api.watchOnce(start.Add(time.Hour), true)
+	last = got[len(got)-1]
+	if !slices.Equal(last.Reopened, []string{"tun0"}) {
+		t.Errorf("change %+v, want tun0 reopened", last)
+	}
+}
+
+func TestReopenBackoffIsCapped(t *testing.T) {
+	if d := reopenBackoff(1); d != reopenBackoffMin {
+		t.Errorf("first wait %v, want %v", d, reopenBackoffMin)
+	}
+	if d := reopenBackoff(50); d != reopenBackoffMax {
+		t.Errorf("wait after 50 attempts %v, want the cap %v", d, reopenBackoffMax)
+	}
+}
+
+func TestStreamFilter_AcceptsNetworkKind(t *testing.T) {
+	f, err := newStreamFilter([]string{"network"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !f.match(batchEntry{kind: "network", code: -1}) || f.match(batchEntry{kind: "event"}) {
+		t.Error("a network filter should let network messages through, and only those")
+	}
+}
@@ -0,0 +1,427 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nospy/albion-openradar/internal/anonymize"
+	"github.com/nospy/albion-openradar/internal/capture"
+	"github.com/nospy/albion-openradar/internal/logger"
+	"github.com/nospy/albion-openradar/internal/photonscan"
+)
+
+// recordingNameRe matches the files the capturers write: start stamp,
+// interface ("all" for a pcapng), rotation part, the suffix of a saved packet
+// buffer and of an anonymized copy, the format, and .gz once the retention
+// sweep compressed it.
+var recordingNameRe = regexp.MustCompile(`^capture_(\d{4}-\d{2}-\d{2}T\d{2}-\d{2}-\d{2})_([^/\\]+?)(?:_(\d+))?(_ring)?(_anon)?\.(pcap|pcapng)(\.gz)?$`)
+
+const recordingStampLayout = "2006-01-02T15-04-05"
+
+// Recording is one pcap file in the capture directory.
+type Recording struct {
+	Name       string    `json:"name"`
+	Size       int64     `json:"size"`
+	Modified   time.Time `json:"modified"`
+	Start      time.Time `json:"start"`
+	Interface  string    `json:"interface"`
+	Part       int       `json:"part"`
+	Format     string    `json:"format"`
+	Compressed bool      `json:"compressed"`
+	Anonymized bool      `json:"anonymized"`
+	// Buffered files were saved from the in-memory packet buffer.
+	Buffered bool `json:"buffered"`
+	// Active files are still being written and are not scanned.
+	Active          bool    `json:"active"`
+	Packets         int     `json:"packets"`
+	Messages        int     `json:"messages"`
+	DurationSeconds float64 `json:"durationSeconds"`
+	// Truncated files end mid-record, as after a crash; they still scan.
+	Truncated bool   `json:"truncated,omitempty"`
+	ScanError string `json:"scanError,omitempty"`
+}
+
+// RecordingsAPI lists, serves, deletes and anonymizes the pcap recordings.
+// Listing and downloading are open to the LAN like the log export; deleting
+// and anonymizing are limited to the host PC.
+type RecordingsAPI struct {
+	dir      string
+	recorder Recorder
+
+	mu    sync.Mutex
+	cache map[string]recordingScan
+	// anonMu runs one anonymization at a time; each holds a whole capture in
+	// memory.
+	anonMu sync.Mutex
+}
+
+// recordingScan caches a photonscan summary until the file changes.
+type recordingScan struct {
+	size    int64
+	modTime time.Time
+	sum     photonscan.Summary
+	err     error
+}
+
+func NewRecordingsAPI(dir string, recorder Recorder) *RecordingsAPI {
+	return &RecordingsAPI{dir: dir, recorder: recorder, cache: make(map[string]recordingScan)}
+}
+
+func (a *RecordingsAPI) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/recordings", a.handleList)
+	mux.HandleFunc("POST /api/recordings/flush", a.handleFlush)
+	mux.HandleFunc("GET /api/recordings/{name}", a.handleDownload)
+	mux.HandleFunc("DELETE /api/recordings/{name}", a.handleDelete)
+	mux.HandleFunc("POST /api/recordings/{name}/anonymize", a.handleAnonymize)
+}
+
+type recordingsBody struct {
+	Dir        string      `json:"dir"`
+	Recording  bool        `json:"recording"`
+	TotalBytes int64       `json:"totalBytes"`
+	Files      []Recording `json:"files"`
+}
+
+func (a *RecordingsAPI) handleList(w http.ResponseWriter, _ *http.Request) {
+	files, err := a.List()
+	if err != nil {
+		http.Error(w, "list recordings: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	body := recordingsBody{Dir: a.dir, Files: files}
+	if a.recorder != nil {
+		body.Recording = a.recorder.IsRecording()
+	}
+	for _, f := range files {
+		body.TotalBytes += f.Size
+	}
+	writeJSON(w, http.StatusOK, body)
+}
+
+// List returns the recordings, newest first. Closed files are scanned once
+// per size and modification time.
+func (a *RecordingsAPI) List() ([]Recording, error) {
+	entries, err := os.ReadDir(a.dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	active := a.activeFiles()
+
+	seen := make(map[string]bool, len(entries))
+	out := make([]Recording, 0, len(entries))
+	for _, e := range entries {
+		rec, ok := parseRecordingName(e.Name())
+		if !ok || e.IsDir() {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		rec.Size = info.Size()
+		rec.Modified = info.ModTime()
+		rec.Active = active[e.Name()]
+		seen[e.Name()] = true
+		if !rec.Active {
+			a.fillScan(&rec, info)
+		}
+		out = append(out, rec)
+	}
+	a.mu.Lock()
+	for name := range a.cache {
+		if !seen[name] {
+			delete(a.cache, name)
+		}
+	}
+	a.mu.Unlock()
+	slices.SortFunc(out, func(x, y Recording) int {
+		if c := y.Start.Compare(x.Start); c != 0 {
+			return c
+		}
+		return strings.Compare(y.Name, x.Name)
+	})
+	return out, nil
+}
+
+// fillScan sets the packet, message and duration fields from the cache,
+// scanning the file when it changed. The scan runs outside a.mu, so a long
+// one does not hold up the other recordings requests.
+func (a *RecordingsAPI) fillScan(rec *Recording, info os.FileInfo) {
+	a.mu.Lock()
+	c, ok := a.cache[rec.Name]
+	a.mu.Unlock()
+	if !ok || c.size != info.Size() || !c.modTime.Equal(info.ModTime()) {
+		sum, err := photonscan.Summarize(filepath.Join(a.dir, rec.Name))
+		c = recordingScan{size: info.Size(), modTime: info.ModTime(), sum: sum, err: err}
+		a.mu.Lock()
+		a.cache[rec.Name] = c
+		a.mu.Unlock()
+	}
+	if c.err != nil {
+		rec.ScanError = c.err.Error()
+		return
+	}
+	rec.Packets = c.sum.Packets
+	rec.Messages = c.sum.Messages
+	rec.DurationSeconds = c.sum.Duration().Seconds()
+	rec.Truncated = c.sum.Truncated
+}
+
+func parseRecordingName(name string) (Recording, bool) {
+	m := recordingNameRe.FindStringSubmatch(name)
+	if m == nil {
+		return Recording{}, false
+	}
+	start, err := time.ParseInLocation(recordingStampLayout, m[1], time.Local)
+	if err != nil {
+		return Recording{}, false
+	}
+	part := 1
+	if m[3] != "" {
+		part, _ = strconv.Atoi(m[3])
+	}
+	return Recording{
+		Name:       name,
+		Start:      start,
+		Interface:  m[2],
+		Part:       part,
+		Buffered:   m[4] != "",
+		Anonymized: m[5] != "",
+		Format:     m[6],
+		Compressed: m[7] != "",
+	}, true
+}
+
+// activeFiles names the files the capturers are writing.
+func (a *RecordingsAPI) activeFiles() map[string]bool {
+	out := make(map[string]bool)
+	if a.recorder == nil {
+		return out
+	}
+	for _, p := range a.recorder.RecordingFiles() {
+		out[filepath.Base(p)] = true
+	}
+	return out
+}
+
+// file resolves the {name} path value to a recording in the capture
+// directory, writing the error response when it is not one.
+func (a *RecordingsAPI) file(w http.ResponseWriter, r *http.Request) (Recording, string, bool) {
+	name := r.PathValue("name")
+	rec, ok := parseRecordingName(name)
+	if !ok || filepath.Base(name) != name {
+		http.Error(w, "not a recording name", http.StatusBadRequest)
+		return rec, "", false
+	}
+	path := filepath.Join(a.dir, name)
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		http.Error(w, "recording not found", http.StatusNotFound)
+		return rec, "", false
+	}
+	rec.Size = info.Size()
+	rec.Modified = info.ModTime()
+	rec.Active = a.activeFiles()[name]
+	return rec, path, true
+}
+
+// handleDownload serves a recording. One that is not anonymized holds
+// nicknames, account ids and hardware strings, so only the host PC gets it.
+func (a *RecordingsAPI) handleDownload(w http.ResponseWriter, r *http.Request) {
+	rec, path, ok := a.file(w, r)
+	if !ok {
+		return
+	}
+	if !rec.Anonymized && !isLoopback(r.RemoteAddr) {
+		http.Error(w, "only anonymized recordings can be downloaded from another device", http.StatusForbidden)
+		return
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		http.Error(w, "open recording: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer f.Close()
+
+	ctype := "application/vnd.tcpdump.pcap"
+	if rec.Format == string(capture.FormatPcapng) {
+		ctype = "application/x-pcapng"
+	}
+	if rec.Compressed {
+		ctype = "application/gzip"
+	}
+	w.Header().Set("Content-Type", ctype)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, rec.Name))
+	if rec.Active {
+		// The size grows while we send; serve what is there now.
+		w.Header().Set("Content-Length", strconv.FormatInt(rec.Size, 10))
+		w.WriteHeader(http.StatusOK)
+		_, _ = io.CopyN(w, f, rec.Size)
+		return
+	}
+	http.ServeContent(w, r, rec.Name, rec.Modified, f)
+}
+
+// handleFlush saves the in-memory packet buffers, one file per interface.
+// Each call writes up to the buffer size per interface, so like deleting it
+// is limited to the host PC.
+func (a *RecordingsAPI) handleFlush(w http.ResponseWriter, r *http.Request) {
+	if !isLoopback(r.RemoteAddr) {
+		http.Error(w, "the packet buffer can only be saved from the host PC", http.StatusForbidden)
+		return
+	}
+	if a.recorder == nil {
+		http.Error(w, "packet buffer not available", http.StatusServiceUnavailable)
+		return
+	}
+	paths, err := a.recorder.FlushRing(a.dir)
+	if errors.Is(err, capture.ErrRingDisabled) {
+		http.Error(w, "packet buffer is disabled; give it a size in the settings first", http.StatusConflict)
+		return
+	}
+	if errors.Is(err, capture.ErrRingEmpty) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil && len(paths) == 0 {
+		http.Error(w, "save packet buffer: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err != nil {
+		logger.PrintWarn("PCAP", "Packet buffer partly saved: %v", err)
+	}
+	files := make([]Recording, 0, len(paths))
+	for _, p := range paths {
+		rec, ok := parseRecordingName(filepath.Base(p))
+		if !ok {
+			continue
+		}
+		if info, err := os.Stat(p); err == nil {
+			rec.Size = info.Size()
+			rec.Modified = info.ModTime()
+		}
+		files = append(files, rec)
+	}
+	logger.PrintInfo("PCAP", "Saved the packet buffer to %d file(s) on request", len(files))
+	writeJSON(w, http.StatusOK, map[string]any{"files": files})
+}
+
+func (a *RecordingsAPI) handleDelete(w http.ResponseWriter, r *http.Request) {
+	if !isLoopback(r.RemoteAddr) {
+		http.Error(w, "recordings can only be deleted from the host PC", http.StatusForbidden)
+		return
+	}
+	rec, path, ok := a.file(w, r)
+	if !ok {
+		return
+	}
+	if rec.Active {
+		http.Error(w, "recording is still being written; stop recording first", http.StatusConflict)
+		return
+	}
+	if err := os.Remove(path); err != nil {
+		http.Error(w, "delete recording: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	logger.PrintInfo("PCAP", "Deleted recording %s", rec.Name)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type anonymizeBody struct {
+	// Scrubs are extra strings to replace, on top of the identity fields.
+	Scrubs []string `json:"scrubs"`
+	// NoIdentity keeps the payloads as they are; only addresses and times
+	// change.
+	NoIdentity bool `json:"noIdentity"`
+}
+
+type anonymizeResult struct {
+	Recording    Recording `json:"recording"`
+	Read         int       `json:"read"`
+	Written      int       `json:"written"`
+	Values       int       `json:"values"`
+	Replacements int       `json:"replacements"`
+}
+
+// handleAnonymize writes <name>_anon.pcap next to the recording, replacing an
+// earlier copy; a pcapng recording gets a classic pcap copy. The values found
+// are counted but not echoed back.
+func (a *RecordingsAPI) handleAnonymize(w http.ResponseWriter, r *http.Request) {
+	if !isLoopback(r.RemoteAddr) {
+		http.Error(w, "recordings can only be anonymized from the host PC", http.StatusForbidden)
+		return
+	}
+	rec, path, ok := a.file(w, r)
+	if !ok {
+		return
+	}
+	if rec.Anonymized {
+		http.Error(w, "recording is already anonymized", http.StatusBadRequest)
+		return
+	}
+	if rec.Active {
+		http.Error(w, "recording is still being written; stop recording first", http.StatusConflict)
+		return
+	}
+	var body anonymizeBody
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+			http.Error(w, "invalid body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if body.NoIdentity && len(body.Scrubs) > 0 {
+		http.Error(w, "noIdentity cannot be combined with scrubs", http.StatusBadRequest)
+		return
+	}
+
+	a.anonMu.Lock()
+	defer a.anonMu.Unlock()
+
+	opts := anonymize.Options{Scrubs: body.Scrubs}
+	if !body.NoIdentity {
+		identity, err := anonymize.IdentityValues(path)
+		if err != nil {
+			http.Error(w, "read recording: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		opts.Identity = identity
+	}
+
+	outName := strings.TrimSuffix(strings.TrimSuffix(rec.Name, ".gz"), "."+rec.Format) + "_anon.pcap"
+	outPath := filepath.Join(a.dir, outName)
+	tmp := outPath + ".tmp"
+	res, err := anonymize.File(path, tmp, opts)
+	if err == nil {
+		err = os.Rename(tmp, outPath)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		http.Error(w, "anonymize recording: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	out := anonymizeResult{Read: res.Read, Written: res.Written, Values: len(res.Counts)}
+	for _, n := range res.Counts {
+		out.Replacements += n
+	}
+	out.Recording, _ = parseRecordingName(outName)
+	if info, err := os.Stat(outPath); err == nil {
+		out.Recording.Size = info.Size()
+		out.Recording.Modified = info.ModTime()
+	}
+	logger.PrintInfo("PCAP", "Anonymized %s to %s (%d packets, %d replacements)",
+		rec.Name, outName, res.Written, out.Replacements)
+	writeJSON(w, http.StatusOK, out)
+}
@@ -0,0 +1,166 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/nospy/albion-openradar/internal/capture"
+)
+
+// recordingsFixture copies a corpus pcap into a capture directory under
+// recording names.
+func recordingsFixture(t *testing.T, names ...string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("..", "photon", "testdata", "players", "spawn.pcap"))
+	require.NoError(t, err)
+	dir := t.TempDir()
+	for _, n := range names {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, n), data, 0o644))
+	}
+	return dir
+}
+
+func serveRecordings(api *RecordingsAPI, method, url, remote string) *httptest.ResponseRecorder {
+	mux := http.NewServeMux()
+	api.Register(mux)
+	req := httptest.NewRequest(method, url, nil)
+	if remote != "" {
+		req.RemoteAddr = remote
+	}
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRecordingsAPI_ListScansClosedFilesOnly(t *testing.T) {
+	const closed = "capture_2026-03-01T10-00-00_eth0_2.pcap"
+	const active = "capture_2026-03-02T10-00-00_Wi-Fi.pcap"
+	dir := recordingsFixture(t, closed, active, "notes.txt")
+	rec := &fakeRecorder{recording: true, files: []string{filepath.Join(dir, active)}}
+	api := NewRecordingsAPI(dir, rec)
+
+	resp := serveRecordings(api, http.MethodGet, "/api/recordings", "")
+	require.Equal(t, http.StatusOK, resp.Code)
+	var body recordingsBody
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+
+	require.True(t, body.Recording)
+	require.Len(t, body.Files, 2)
+	require.Equal(t, active, body.Files[0].Name, "newest first")
+	require.True(t, body.Files[0].Active)
+	require.Zero(t, body.Files[0].Packets)
+	require.Equal(t, "Wi-Fi", body.Files[0].Interface)
+
+	require.Equal(t, "eth0", body.Files[1].Interface)
+	require.Equal(t, 2, body.Files[1].Part)
+	require.NotZero(t, body.Files[1].Packets)
+	require.NotZero(t, body.Files[1].Messages)
+	require.Equal(t, body.Files[0].Size+body.Files[1].Size, body.TotalBytes)
+}
+
+func TestRecordingsAPI_DownloadServesTheFileAsAnAttachment(t *testing.T) {
+	const name = "capture_2026-03-01T10-00-00_eth0.pcap"
+	const anon = "capture_2026-03-01T10-00-00_eth0_anon.pcap"
+	dir := recordingsFixture(t, name, anon)
+	api := NewRecordingsAPI(dir, nil)
+
+	resp := serveRecordings(api, http.MethodGet, "/api/recordings/"+name, "127.0.0.1:1234")
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Contains(t, resp.Header().Get("Content-Disposition"), name)
+	want, _ := os.ReadFile(filepath.Join(dir, name))
+	require.Equal(t, want, resp.Body.Bytes())
+
+	require.Equal(t, http.StatusForbidden, serveRecordings(api, http.MethodGet, "/api/recordings/"+name, "192.168.1.42:5555").Code,
+		"a raw recording stays on the host PC")
+	require.Equal(t, http.StatusOK, serveRecordings(api, http.MethodGet, "/api/recordings/"+anon, "192.168.1.42:5555").Code)
+
+	require.Equal(t, http.StatusBadRequest, serveRecordings(api, http.MethodGet, "/api/recordings/network.json", "").Code)
+	require.Equal(t, http.StatusBadRequest, serveRecordings(api, http.MethodGet, "/api/recordings/..%2Fcapture_2026-03-01T10-00-00_eth0.pcap", "").Code)
+	require.Equal(t, http.StatusNotFound, serveRecordings(api, http.MethodGet, "/api/recordings/capture_2020-01-01T00-00-00_eth0.pcap", "").Code)
+}
+
+func TestRecordingsAPI_DeleteIsHostOnlyAndSparesTheActiveFile(t *testing.T) {
+	const closed = "capture_2026-03-01T10-00-00_eth0.pcap"
+	const active = "capture_2026-03-02T10-00-00_eth0.pcap"
+	dir := recordingsFixture(t, closed, active)
+	api := NewRecordingsAPI(dir, &fakeRecorder{files: []string{filepath.Join(dir, active)}})
+
+	require.Equal(t, http.StatusForbidden, serveRecordings(api, http.MethodDelete, "/api/recordings/"+closed, "192.168.1.42:5555").Code)
+	require.Equal(t, http.StatusConflict, serveRecordings(api, http.MethodDelete, "/api/recordings/"+active, "127.0.0.1:1234").Code)
+	require.Equal(t, http.StatusNoContent, serveRecordings(api, http.MethodDelete, "/api/recordings/"+closed, "127.0.0.1:1234").Code)
+
+	_, err := os.Stat(filepath.Join(dir, closed))
+	require.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(dir, active))
+	require.NoError(t, err)
+}
+
+func TestRecordingsAPI_AnonymizeWritesACopyNextToTheRecording(t *testing.T) {
+	const name = "capture_2026-03-01T10-00-00_eth0.pcap"
+	dir := recordingsFixture(t, name)
+	api := NewRecordingsAPI(dir, nil)
+	url := "/api/recordings/" + name + "/anonymize"
+
+	require.Equal(t, http.StatusForbidden, serveRecordings(api, http.MethodPost, url, "192.168.1.42:5555").Code)
+
+	resp := serveRecordings(api, http.MethodPost, url, "127.0.0.1:1234")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var out anonymizeResult
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	require.Equal(t, "capture_2026-03-01T10-00-00_eth0_anon.pcap", out.Recording.Name)
+	require.True(t, out.Recording.Anonymized)
+	require.NotZero(t, out.Written)
+	require.FileExists(t, filepath.Join(dir, out.Recording.Name))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	for _, e := range entries {
+		require.False(t, strings.HasSuffix(e.Name(), ".tmp"), "left %s behind", e.Name())
+	}
+
+	again := serveRecordings(api, http.MethodPost, "/api/recordings/"+out.Recording.Name+"/anonymize", "127.0.0.1:1234")
+	require.Equal(t, http.StatusBadRequest, again.Code)
+}
+
+func TestRecordingsAPI_FlushSavesTheBufferFromTheHostOnly(t *testing.T) {
+	const saved = "capture_2026-03-01T10-00-00_eth0_ring.pcap"
+	dir := recordingsFixture(t, saved)
+	rec := &fakeRecorder{flushed: []string{filepath.Join(dir, saved)}}
+	api := NewRecordingsAPI(dir, rec)
+
+	lan := serveRecordings(api, http.MethodPost, "/api/recordings/flush", "192.168.1.42:5555")
+	require.Equal(t, http.StatusForbidden, lan.Code)
+
+	resp := serveRecordings(api, http.MethodPost, "/api/recordings/flush", "127.0.0.1:5555")
+	require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var body struct{ Files []Recording }
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.Len(t, body.Files, 1)
+	require.True(t, body.Files[0].Buffered)
+	require.Equal(t, "eth0", body.Files[0].Interface)
+	require.NotZero(t, body.Files[0].Size)
+
+	rec.flushed, rec.flushErr = nil, capture.ErrRingEmpty
+	require.Equal(t, http.StatusConflict, serveRecordings(api, http.MethodPost, "/api/recordings/flush", "127.0.0.1:5555").Code)
+
+	rec.flushErr = capture.ErrRingDisabled
+	off := serveRecordings(api, http.MethodPost, "/api/recordings/flush", "127.0.0.1:5555")
+	require.Equal(t, http.StatusConflict, off.Code)
+	require.Contains(t, off.Body.String(), "disabled")
+}
+
+func TestParseRecordingName_KnowsThePcapngRecording(t *testing.T) {
+	rec, ok := parseRecordingName("capture_2026-03-01T10-00-00_all_2.pcapng.gz")
+	require.True(t, ok)
+	require.Equal(t, "all", rec.Interface)
+	require.Equal(t, 2, rec.Part)
+	require.Equal(t, "pcapng", rec.Format)
+	require.True(t, rec.Compressed)
+}
@@ -1,7 +1,10 @@
 package server
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/segmentio/encoding/json"
 
@@ -14,13 +17,20 @@ type Recorder interface {
 	StartRecording(dir string) error
 	StopRecording() error
 	IsRecording() bool
+	// RecordingFiles lists the pcap files being written.
+	RecordingFiles() []string
+	// FlushRing saves the buffered packets to dir and lists the files written.
+	FlushRing(dir string) ([]string, error)
 }
 
 type SettingsAPI struct {
-	appDir     string
-	logger     *logger.Logger
-	recorder   Recorder
-	captureDir string
+	appDir      string
+	logger      *logger.Logger
+	recorder    Recorder
+	captureDir  string
+	onRetention func(capture.RetentionConfig)
+	onRing      func(capture.RingBufferConfig)
+	onFormat    func(capture.RecordingFormat)
 }
 
 // NewSettingsAPI creates a SettingsAPI. recorder may be nil (recording calls are skipped).
@@ -33,6 +43,24 @@ func NewSettingsAPI(appDir string, log *logger.Logger, recorder Recorder, captur
 	}
 }
 
+// OnRetentionChange registers fn to apply a saved retention config at once,
+// rather than at the next periodic sweep.
+func (a *SettingsAPI) OnRetentionChange(fn func(capture.RetentionConfig)) {
+	a.onRetention = fn
+}
+
+// OnRingBufferChange registers fn to resize the packet buffers when their
+// settings are saved.
+func (a *SettingsAPI) OnRingBufferChange(fn func(capture.RingBufferConfig)) {
+	a.onRing = fn
+}
+
+// OnRecordingFormatChange registers fn to switch the recording format when
+// it is saved, restarting a running recording.
+func (a *SettingsAPI) OnRecordingFormatChange(fn func(capture.RecordingFormat)) {
+	a.onFormat = fn
+}
+
 func (a *SettingsAPI) Register(mux *http.ServeMux) {
 	mux.HandleFunc("GET /api/settings/logging", a.handleGet)
 	mux.HandleFunc("POST /api/settings/logging", a.handlePost)
@@ -44,12 +72,31 @@ func (a *SettingsAPI) handleGet(w http.ResponseWriter, _ *http.Request) {
 		http.Error(w, "read config: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
-	writeJSON(w, http.StatusOK, cfg.Logging)
+	writeJSON(w, http.StatusOK, withDefaults(cfg.Logging))
 }
 
+// withDefaults fills in the default retention, packet buffer and recording
+// format so the settings page always shows the settings in force.
+func withDefaults(c capture.LoggingConfig) capture.LoggingConfig {
+	r := c.RetentionOrDefault()
+	c.Retention = &r
+	ring := c.RingBufferOrDefault()
+	c.RingBuffer = &ring
+	c.RecordingFormat = c.RecordingFormatOrDefault()
+	return c
+}
+
+// loggingPatch changes only the fields it carries. Levels is merged into the
+// saved levels; an empty level removes that tag's entry. Retention replaces
+// both policies and RingBuffer all its fields. RecordingFormat is "pcap" or
+// "pcapng".
 type loggingPatch struct {
-	ServerLogsEnabled *bool `json:"serverLogsEnabled"`
-	PcapRecording     *bool `json:"pcapRecording"`
+	ServerLogsEnabled *bool                     `json:"serverLogsEnabled"`
+	PcapRecording     *bool                     `json:"pcapRecording"`
+	Levels            map[string]string         `json:"levels"`
+	Retention         *capture.RetentionConfig  `json:"retention"`
+	RingBuffer        *capture.RingBufferConfig `json:"ringBuffer"`
+	RecordingFormat   *capture.RecordingFormat  `json:"recordingFormat"`
 }
 
 func (a *SettingsAPI) handlePost(w http.ResponseWriter, r *http.Request) {
@@ -58,6 +105,33 @@ func (a *SettingsAPI) handlePost(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "invalid body: "+err.Error(), http.StatusBadRequest)
 		return
 	}
+	for tag, level := range patch.Levels {
+		if level == "" {
+			continue
+		}
+		if _, err := logger.ParseLevel(level); err != nil {
+			http.Error(w, fmt.Sprintf("levels.%s: %v", tag, err), http.StatusBadRequest)
+			return
+		}
+	}
+	if r := patch.Retention; r != nil {
+		if err := errors.Join(r.Logs.Validate(), r.Captures.Validate()); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if r := patch.RingBuffer; r != nil {
+		if err := r.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if f := patch.RecordingFormat; f != nil {
+		if err := f.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
 
 	var newLogging capture.LoggingConfig
 	if err := capture.MutateConfig(a.appDir, func(cfg *capture.Config) {
@@ -67,6 +141,28 @@ func (a *SettingsAPI) handlePost(w http.ResponseWriter, r *http.Request) {
 		if patch.PcapRecording != nil {
 			cfg.Logging.PcapRecording = *patch.PcapRecording
 		}
+		for tag, level := range patch.Levels {
+			if tag != logger.DefaultLevelKey {
+				tag = strings.ToUpper(tag)
+			}
+			if level == "" {
+				delete(cfg.Logging.Levels, tag)
+				continue
+			}
+			if cfg.Logging.Levels == nil {
+				cfg.Logging.Levels = make(map[string]string)
+			}
+			cfg.Logging.Levels[tag] = strings.ToLower(level)
+		}
+		if patch.Retention != nil {
+			cfg.Logging.Retention = patch.Retention
+		}
+		if patch.RingBuffer != nil {
+			cfg.Logging.RingBuffer = patch.RingBuffer
+		}
+		if patch.RecordingFormat != nil {
+			cfg.Logging.RecordingFormat = *patch.RecordingFormat
+		}
 		newLogging = cfg.Logging
 	}); err != nil {
 		http.Error(w, "write config: "+err.Error(), http.StatusInternalServerError)
@@ -77,22 +173,61 @@ func (a *SettingsAPI) handlePost(w http.ResponseWriter, r *http.Request) {
 		a.logger.SetEnabled(*patch.ServerLogsEnabled)
 	}
 
-	if patch.PcapRecording != nil && a.recorder != nil {
-		if *patch.PcapRecording {
-			if err := a.recorder.StartRecording(a.captureDir); err != nil {
-				logger.PrintWarn("PKT", "pcap recording could not start: %v", err)
-				_ = capture.MutateConfig(a.appDir, func(cfg *capture.Config) {
-					cfg.Logging.PcapRecording = false
-				})
-				http.Error(w, "pcap recording failed: "+err.Error(), http.StatusInternalServerError)
-				return
-			}
-		} else {
-			if err := a.recorder.StopRecording(); err != nil {
-				logger.PrintWarn("PKT", "pcap recording could not stop: %v", err)
-			}
+	if patch.Levels != nil {
+		_ = logger.SetLevels(newLogging.Levels) // validated above
+	}
+
+	if patch.Retention != nil && a.onRetention != nil {
+		a.onRetention(*patch.Retention)
+	}
+
+	if patch.RingBuffer != nil && a.onRing != nil {
+		a.onRing(*patch.RingBuffer)
+	}
+
+	if patch.RecordingFormat != nil && a.onFormat != nil {
+		a.onFormat(*patch.RecordingFormat)
+	}
+
+	if patch.PcapRecording != nil {
+		if err := a.applyRecording(*patch.PcapRecording); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
 		}
 	}
 
-	writeJSON(w, http.StatusOK, newLogging)
+	writeJSON(w, http.StatusOK, withDefaults(newLogging))
+}
+
+// SetRecording saves the pcap recording flag and starts or stops the
+// recorder, like a POST carrying only pcapRecording.
+func (a *SettingsAPI) SetRecording(on bool) error {
+	if err := capture.MutateConfig(a.appDir, func(cfg *capture.Config) {
+		cfg.Logging.PcapRecording = on
+	}); err != nil {
+		return fmt.Errorf("write config: %w", err)
+	}
+	return a.applyRecording(on)
+}
+
+// applyRecording starts or stops the recorder. A failed start is rolled back
+// in network.json so the next launch does not retry it.
+func (a *SettingsAPI) applyRecording(on bool) error {
+	if a.recorder == nil {
+		return nil
+	}
+	if !on {
+		if err := a.recorder.StopRecording(); err != nil {
+			logger.PrintWarn("PKT", "pcap recording could not stop: %v", err)
+		}
+		return nil
+	}
+	if err := a.recorder.StartRecording(a.captureDir); err != nil {
+		logger.PrintWarn("PKT", "pcap recording could not start: %v", err)
+		_ = capture.MutateConfig(a.appDir, func(cfg *capture.Config) {
+			cfg.Logging.PcapRecording = false
+		})
+		return fmt.Errorf("pcap recording failed: %w", err)
+	}
+	return nil
 }
@@ -3,6 +3,7 @@ package server
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"sync"
@@ -16,6 +17,9 @@ import (
 type fakeRecorder struct {
 	mu        sync.Mutex
 	recording bool
+	files     []string
+	flushed   []string
+	flushErr  error
 	startErr  error
 	stopErr   error
 }
@@ -46,6 +50,18 @@ func (r *fakeRecorder) IsRecording() bool {
 	return r.recording
 }
 
+func (r *fakeRecorder) RecordingFiles() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.files
+}
+
+func (r *fakeRecorder) FlushRing(_ string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.flushed, r.flushErr
+}
+
 func newSettingsTestMux(t *testing.T, dir string) (*http.ServeMux, *logger.Logger) {
 	t.Helper()
 	log := logger.New(t.TempDir(), false)
@@ -284,3 +300,206 @@ func TestSettingsLogging_PostStopsRecording(t *testing.T) {
 		t.Error("recorder.IsRecording() == true after POST pcapRecording=false")
 	}
 }
+
+func TestSettingsSetRecording_RollsBackFailedStart(t *testing.T) {
+	dir := t.TempDir()
+	rec := &fakeRecorder{startErr: errors.New("disk full")}
+	api := NewSettingsAPI(dir, nil, rec, t.TempDir())
+
+	if err := api.SetRecording(true); err == nil {
+		t.Fatal("SetRecording(true) succeeded with a failing recorder")
+	}
+	cfg, err := capture.ReadConfig(dir)
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	if cfg.Logging.PcapRecording {
+		t.Error("failed start left pcapRecording=true in network.json")
+	}
+
+	rec.startErr = nil
+	if err := api.SetRecording(true); err != nil {
+		t.Fatalf("SetRecording(true): %v", err)
+	}
+	cfg, _ = capture.ReadConfig(dir)
+	if !rec.IsRecording() || !cfg.Logging.PcapRecording {
+		t.Errorf("recording=%v persisted=%v, want both true", rec.IsRecording(), cfg.Logging.PcapRecording)
+	}
+}
+
+func TestSettingsLogging_PostMergesLevels(t *testing.T) {
+	dir := t.TempDir()
+	if err := capture.WriteConfig(dir, capture.Config{
+		Logging: capture.LoggingConfig{Levels: map[string]string{"NET": "warn", "WS": "error"}},
+	}); err != nil {
+		t.Fatalf("seed config: %v", err)
+	}
+	mux, _ := newSettingsTestMux(t, dir)
+	prev := logger.Levels()
+	t.Cleanup(func() { _ = logger.SetLevels(prev) })
+
+	post := func(levels map[string]string) int {
+		body, _ := json.Marshal(map[string]any{"levels": levels})
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/settings/logging", bytes.NewReader(body)))
+		return rec.Code
+	}
+
+	if code := post(map[string]string{"pkt": "debug", "WS": ""}); code != http.StatusOK {
+		t.Fatalf("status %d, want 200", code)
+	}
+	cfg, err := capture.ReadConfig(dir)
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	want := map[string]string{"NET": "warn", "PKT": "debug"}
+	if len(cfg.Logging.Levels) != len(want) || cfg.Logging.Levels["NET"] != "warn" || cfg.Logging.Levels["PKT"] != "debug" {
+		t.Errorf("levels = %v, want %v", cfg.Logging.Levels, want)
+	}
+	if got := logger.Levels(); got["PKT"] != "debug" || got["NET"] != "warn" {
+		t.Errorf("levels not applied: %v", got)
+	}
+
+	if code := post(map[string]string{"NET": "verbose"}); code != http.StatusBadRequest {
+		t.Errorf("unknown level: status %d, want 400", code)
+	}
+	if cfg, _ := capture.ReadConfig(dir); cfg.Logging.Levels["NET"] != "warn" {
+		t.Errorf("a rejected patch must not be saved, got %v", cfg.Logging.Levels)
+	}
+}
+
+func TestSettingsLogging_Retention(t *testing.T) {
+	dir := t.TempDir()
+	log := logger.New(t.TempDir(), false)
+	t.Cleanup(func() { log.Stop() })
+	api := NewSettingsAPI(dir, log, nil, "")
+	var applied []capture.RetentionConfig
+	api.OnRetentionChange(func(r capture.RetentionConfig) { applied = append(applied, r) })
+	mux := http.NewServeMux()
+	api.Register(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/settings/logging", nil))
+	var got capture.LoggingConfig
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Retention == nil || *got.Retention != capture.DefaultRetention {
+		t.Errorf("GET without a saved retention must show the defaults, got %+v", got.Retention)
+	}
+
+	want := capture.RetentionConfig{
+		Logs:     capture.RetentionPolicy{MaxFileMB: 10, MaxAgeDays: 3},
+		Captures: capture.RetentionPolicy{MaxTotalMB: 100, Compress: true},
+	}
+	body, _ := json.Marshal(map[string]any{"retention": want})
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/settings/logging", bytes.NewReader(body)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d, want 200", rec.Code)
+	}
+	cfg, err := capture.ReadConfig(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Logging.Retention == nil || *cfg.Logging.Retention != want {
+		t.Errorf("saved retention = %+v, want %+v", cfg.Logging.Retention, want)
+	}
+	if len(applied) != 1 || applied[0] != want {
+		t.Errorf("hook got %+v", applied)
+	}
+
+	body, _ = json.Marshal(map[string]any{"retention": map[string]any{"logs": map[string]int{"maxAgeDays": -1}}})
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/settings/logging", bytes.NewReader(body)))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("negative limit: status %d, want 400", rec.Code)
+	}
+}
+
+func TestSettingsLogging_RingBuffer(t *testing.T) {
+	dir := t.TempDir()
+	api := NewSettingsAPI(dir, nil, nil, "")
+	var applied []capture.RingBufferConfig
+	api.OnRingBufferChange(func(r capture.RingBufferConfig) { applied = append(applied, r) })
+	mux := http.NewServeMux()
+	api.Register(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/settings/logging", nil))
+	var got capture.LoggingConfig
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.RingBuffer == nil || *got.RingBuffer != capture.DefaultRingBuffer {
+		t.Errorf("GET without saved buffer settings must show the defaults, got %+v", got.RingBuffer)
+	}
+
+	want := capture.RingBufferConfig{Seconds: 300, MaxMB: 64}
+	body, _ := json.Marshal(map[string]any{"ringBuffer": want})
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/settings/logging", bytes.NewReader(body)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d, want 200", rec.Code)
+	}
+	cfg, err := capture.ReadConfig(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Logging.RingBuffer == nil || *cfg.Logging.RingBuffer != want {
+		t.Errorf("saved buffer settings = %+v, want %+v", cfg.Logging.RingBuffer, want)
+	}
+	if len(applied) != 1 || applied[0] != want {
+		t.Errorf("hook got %+v", applied)
+	}
+
+	body, _ = json.Marshal(map[string]any{"ringBuffer": map[string]int{"seconds": -5}})
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/settings/logging", bytes.NewReader(body)))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("negative seconds: status %d, want 400", rec.Code)
+	}
+}
+
+func TestSettingsLogging_RecordingFormat(t *testing.T) {
+	dir := t.TempDir()
+	api := NewSettingsAPI(dir, nil, nil, "")
+	var applied []capture.RecordingFormat
+	api.OnRecordingFormatChange(func(f capture.RecordingFormat) { applied = append(applied, f) })
+	mux := http.NewServeMux()
+	api.Register(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/settings/logging", nil))
+	var got capture.LoggingConfig
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.RecordingFormat != capture.FormatPcap {
+		t.Errorf("GET without a saved format must show pcap, got %q", got.RecordingFormat)
+	}
+
+	body := []byte(`{"recordingFormat":"pcapng"}`)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/settings/logging", bytes.NewReader(body)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d, want 200", rec.Code)
+	}
+	cfg, err := capture.ReadConfig(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Logging.RecordingFormat != capture.FormatPcapng {
+		t.Errorf("saved format = %q, want pcapng", cfg.Logging.RecordingFormat)
+	}
+	if len(applied) != 1 || applied[0] != capture.FormatPcapng {
+		t.Errorf("hook got %v", applied)
+	}
+
+	body = []byte(`{"recordingFormat":"erf"}`)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/settings/logging", bytes.NewReader(body)))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown format: status %d, want 400", rec.Code)
+	}
+}
@@ -0,0 +1,144 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/nospy/albion-openradar/internal/logger"
+)
+
+const (
+	sseBuffer    = 64 // batches queued per stream before it counts as slow
+	sseKeepAlive = 15 * time.Second
+)
+
+type sseFrame struct {
+	seq  uint64
+	id   string
+	data []byte
+}
+
+// sseClient is one /api/stream subscriber. frames is closed when the
+// handler drops it (slow reader or shutdown).
+type sseClient struct {
+	filter streamFilter
+	frames chan sseFrame
+}
+
+// ServeStream serves the batched messages as Server-Sent Events, for clients
+// that cannot hold a WebSocket open. Query parameters kinds and codes take
+// comma-separated lists and, with instance, narrow the stream the same way a
+// WebSocket "filter" message does. A reconnect carrying Last-Event-ID first replays
+// the batches it missed, or gets a "reset" event when they are gone.
+func (ws *WebSocketHandler) ServeStream(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseStreamFilter(r.URL.Query().Get("kinds"), r.URL.Query().Get("codes"))
+	if err == nil {
+		filter.instance, err = parseInstance(r.URL.Query().Get("instance"))
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	// The server's WriteTimeout would cut the stream after 30s.
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	sub := &sseClient{filter: filter, frames: make(chan sseFrame, sseBuffer)}
+	ws.clientsMu.Lock()
+	if ws.closed || ws.clientCountLocked() >= MaxWebSocketClients {
+		ws.clientsMu.Unlock()
+		logger.PrintWarn("WS", "Stream rejected: max clients reached (%d)", MaxWebSocketClients)
+		http.Error(w, "too many clients", http.StatusServiceUnavailable)
+		return
+	}
+	// Registered before the replay is read so no batch falls between the two.
+	ws.sseClients[sub] = struct{}{}
+	clientCount := ws.clientCountLocked()
+	ws.clientsMu.Unlock()
+
+	logger.PrintInfo("WS", "Stream client connected (%d total)", clientCount)
+	defer func() {
+		ws.clientsMu.Lock()
+		ws.removeStreamLocked(sub)
+		clientCount := ws.clientCountLocked()
+		ws.clientsMu.Unlock()
+		logger.PrintInfo("WS", "Stream client disconnected (%d remaining)", clientCount)
+	}()
+
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	var lastSeq uint64
+	if lastID := r.Header.Get("Last-Event-ID"); lastID != "" {
+		missed, ok := ws.replay.since(lastID)
+		if !ok {
+			_, err = io.WriteString(w, "event: reset\ndata: {}\n\n")
+		}
+		for _, b := range missed {
+			if err != nil {
+				break
+			}
+			lastSeq = b.seq
+			data, encErr := newBatchEncoder(b.batch).encode(filter)
+			if encErr != nil || data == nil {
+				continue
+			}
+			err = writeSSE(w, b.id, data)
+		}
+		if err != nil {
+			return
+		}
+	}
+	if rc.Flush() != nil {
+		return
+	}
+
+	keepAlive := time.NewTicker(sseKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			if _, err := io.WriteString(w, ": ping\n\n"); err != nil {
+				return
+			}
+		case f, ok := <-sub.frames:
+			if !ok {
+				return
+			}
+			// Batches flushed while the replay was read can arrive twice.
+			if f.seq <= lastSeq {
+				continue
+			}
+			if err := writeSSE(w, f.id, f.data); err != nil {
+				return
+			}
+		}
+		if rc.Flush() != nil {
+			return
+		}
+	}
+}
+
+func writeSSE(w io.Writer, id string, data []byte) error {
+	_, err := fmt.Fprintf(w, "id: %s\ndata: %s\n\n", id, data)
+	return err
+}
+
+// removeStreamLocked unregisters a stream and ends its handler. clientsMu
+// must be held for writing.
+func (ws *WebSocketHandler) removeStreamLocked(sub *sseClient) {
+	if _, ok := ws.sseClients[sub]; ok {
+		delete(ws.sseClients, sub)
+		close(sub.frames)
+	}
+}
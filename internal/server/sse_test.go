@@ -0,0 +1,170 @@
+package server
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/nospy/albion-openradar/internal/photon"
+)
+
+func TestStreamFilter(t *testing.T) {
+	f, err := parseStreamFilter("event", "3, 29")
+	require.NoError(t, err)
+	require.True(t, f.match(batchEntry{kind: "event", code: 29}))
+	require.False(t, f.match(batchEntry{kind: "event", code: 6}))
+	require.False(t, f.match(batchEntry{kind: "request", code: 3}))
+
+	all, err := parseStreamFilter("", "")
+	require.NoError(t, err)
+	require.True(t, all.match(batchEntry{kind: "response", code: -1}))
+
+	_, err = parseStreamFilter("events", "")
+	require.Error(t, err)
+	_, err = parseStreamFilter("", "move")
+	require.Error(t, err)
+
+	a, _ := newStreamFilter([]string{"request", "event"}, []int{29, 3})
+	b, _ := newStreamFilter([]string{"event", "request", "event"}, []int{3, 29})
+	require.Equal(t, a.key(), b.key(), "equivalent filters must share one encoded batch")
+}
+
+func TestStreamFilter_Instance(t *testing.T) {
+	f := streamFilter{instance: 50000}
+	require.True(t, f.match(batchEntry{kind: "event", instance: 50000}))
+	require.False(t, f.match(batchEntry{kind: "event", instance: 50123}))
+	require.True(t, f.match(batchEntry{kind: "event"}), "messages of no known client reach everyone")
+	require.True(t, streamFilter{}.match(batchEntry{kind: "event", instance: 50123}))
+	require.NotEqual(t, f.key(), streamFilter{}.key())
+
+	n, err := parseInstance("50000")
+	require.NoError(t, err)
+	require.Equal(t, uint16(50000), n)
+	_, err = parseInstance("70000")
+	require.Error(t, err)
+}
+
+func TestBatchEncoderSkipsEmptyFilteredBatch(t *testing.T) {
+	enc := newBatchEncoder([]batchEntry{{kind: "event", code: 3, msg: map[string]any{"code": "event"}}})
+	data, err := enc.encode(streamFilter{})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"type":"batch","messages":[{"code":"event"}]}`, string(data))
+
+	none, _ := newStreamFilter([]string{"response"}, nil)
+	data, err = enc.encode(none)
+	require.NoError(t, err)
+	require.Nil(t, data)
+}
+
+func TestBatchRingSince(t *testing.T) {
+	r := newBatchRing(4)
+	var ids []string
+	for range 6 {
+		_, id := r.push([]batchEntry{{kind: "event"}})
+		ids = append(ids, id)
+	}
+
+	got, ok := r.since(ids[3])
+	require.True(t, ok)
+	require.Len(t, got, 2)
+	require.Equal(t, ids[4], got[0].id)
+	require.Equal(t, ids[5], got[1].id)
+
+	got, ok = r.since(ids[5])
+	require.True(t, ok, "an up-to-date client has nothing to replay")
+	require.Empty(t, got)
+
+	_, ok = r.since(ids[0])
+	require.False(t, ok, "batches older than the ring are gone")
+
+	_, ok = r.since("otherrun-5")
+	require.False(t, ok, "an ID from a previous run must not match")
+}
+
+func TestServeStreamDeliversFilteredBatches(t *testing.T) {
+	ws := NewWebSocketHandler(nil)
+	defer ws.CloseAllClients()
+	srv := httptest.NewServer(http.HandlerFunc(ws.ServeStream))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "?kinds=event&codes=29")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	require.Eventually(t, func() bool { return ws.ClientCount() == 1 }, time.Second, 5*time.Millisecond)
+
+	ws.BroadcastEvent(&photon.EventData{Code: 1, Parameters: map[byte]any{252: int16(6)}})
+	ws.BroadcastRequest(&photon.OperationRequest{OperationCode: 1, Parameters: map[byte]any{253: int16(29)}})
+	ws.BroadcastEvent(&photon.EventData{Code: 1, Parameters: map[byte]any{252: int16(29)}})
+
+	id, data := readSSEFrame(t, bufio.NewReader(resp.Body))
+	require.NotEmpty(t, id)
+	require.Contains(t, data, `"252":29`)
+	require.NotContains(t, data, `"252":6`)
+	require.NotContains(t, data, `"request"`)
+}
+
+func TestServeStreamResumesFromLastEventID(t *testing.T) {
+	ws := NewWebSocketHandler(nil)
+	defer ws.CloseAllClients()
+	srv := httptest.NewServer(http.HandlerFunc(ws.ServeStream))
+	defer srv.Close()
+
+	ws.BroadcastEvent(&photon.EventData{Code: 1, Parameters: map[byte]any{252: int16(1)}})
+	ws.flushBatch()
+	first, _ := ws.replay.since(ws.replay.formatID(0))
+	require.Len(t, first, 1)
+	ws.BroadcastEvent(&photon.EventData{Code: 1, Parameters: map[byte]any{252: int16(2)}})
+	ws.flushBatch()
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	req.Header.Set("Last-Event-ID", first[0].id)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	_, data := readSSEFrame(t, bufio.NewReader(resp.Body))
+	require.Contains(t, data, `"252":2`, "only the batch after Last-Event-ID is replayed")
+
+	req.Header.Set("Last-Event-ID", "stale-1")
+	resp2, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp2.Body.Close()
+	line, err := bufio.NewReader(resp2.Body).ReadString('\n')
+	require.NoError(t, err)
+	require.Equal(t, "event: reset\n", line)
+}
+
+func readSSEFrame(t *testing.T, r *bufio.Reader) (id, data string) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimSuffix(line, "\n")
+			switch {
+			case strings.HasPrefix(line, "id: "):
+				id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "data: "):
+				data = strings.TrimPrefix(line, "data: ")
+			case line == "" && data != "":
+				return
+			}
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("no event received")
+	}
+	return id, data
+}
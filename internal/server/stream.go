@@ -0,0 +1,211 @@
+package server
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/segmentio/encoding/json"
+)
+
+// StreamReplayBatches is how many recent batches are kept for Last-Event-ID
+// resume, a few seconds at full rate.
+const StreamReplayBatches = 256
+
+// batchEntry is one queued message plus what per-client filters match on.
+type batchEntry struct {
+	kind string // "event", "request", "response" or "network"
+	code int    // Albion code from Parameters[252]/[253], -1 if absent
+	// instance is the local UDP port of the game client, 0 if unknown.
+	instance uint16
+	msg      any
+}
+
+// streamFilter narrows a client's batches to the message kinds and Albion
+// codes it asked for, and to one game client when several run on the PC.
+// The zero value lets everything through.
+type streamFilter struct {
+	kinds []string
+	codes []int
+	// instance follows one game client by its local UDP port; 0 follows
+	// them all.
+	instance uint16
+}
+
+func newStreamFilter(kinds []string, codes []int) (streamFilter, error) {
+	var f streamFilter
+	for _, k := range kinds {
+		switch k {
+		case "event", "request", "response", "network":
+			if !slices.Contains(f.kinds, k) {
+				f.kinds = append(f.kinds, k)
+			}
+		default:
+			return streamFilter{}, fmt.Errorf("unknown message kind %q", k)
+		}
+	}
+	for _, c := range codes {
+		if !slices.Contains(f.codes, c) {
+			f.codes = append(f.codes, c)
+		}
+	}
+	slices.Sort(f.kinds)
+	slices.Sort(f.codes)
+	return f, nil
+}
+
+// parseStreamFilter reads the comma-separated kinds and codes query values
+// used by /api/stream.
+func parseStreamFilter(kinds, codes string) (streamFilter, error) {
+	var codeList []int
+	for _, s := range splitList(codes) {
+		c, err := strconv.Atoi(s)
+		if err != nil {
+			return streamFilter{}, fmt.Errorf("invalid code %q", s)
+		}
+		codeList = append(codeList, c)
+	}
+	return newStreamFilter(splitList(kinds), codeList)
+}
+
+// parseInstance reads the instance query value: a local UDP port, or empty
+// for every game client.
+func parseInstance(s string) (uint16, error) {
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseUint(s, 10, 16)
+	if err != nil {
+		return 0, fmt.Errorf("invalid instance %q", s)
+	}
+	return uint16(n), nil
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+func (f streamFilter) match(e batchEntry) bool {
+	if len(f.kinds) > 0 && !slices.Contains(f.kinds, e.kind) {
+		return false
+	}
+	if len(f.codes) > 0 && !slices.Contains(f.codes, e.code) {
+		return false
+	}
+	if f.instance != 0 && e.instance != 0 && e.instance != f.instance {
+		return false
+	}
+	return true
+}
+
+// key identifies filters that select the same messages, so clients sharing
+// a filter share one marshalled batch.
+func (f streamFilter) key() string {
+	return fmt.Sprint(f.kinds, f.codes, f.instance)
+}
+
+// batchEncoder marshals a batch once per distinct client filter.
+type batchEncoder struct {
+	batch []batchEntry
+	cache map[string][]byte
+}
+
+func newBatchEncoder(batch []batchEntry) *batchEncoder {
+	return &batchEncoder{batch: batch, cache: make(map[string][]byte)}
+}
+
+// encode returns the batch message for a filter, or nil when the filter
+// leaves nothing to send.
+func (e *batchEncoder) encode(f streamFilter) ([]byte, error) {
+	key := f.key()
+	if data, ok := e.cache[key]; ok {
+		return data, nil
+	}
+	var messages []any
+	for _, entry := range e.batch {
+		if f.match(entry) {
+			messages = append(messages, entry.msg)
+		}
+	}
+	var data []byte
+	if len(messages) > 0 {
+		var err error
+		data, err = json.Marshal(&WSBatchMessage{Type: "batch", Messages: messages})
+		if err != nil {
+			return nil, err
+		}
+	}
+	e.cache[key] = data
+	return data, nil
+}
+
+// batchRing keeps the most recent batches under increasing sequence numbers.
+// IDs are prefixed with an epoch so a Last-Event-ID from before a restart is
+// recognised instead of matching an unrelated batch.
+type batchRing struct {
+	mu      sync.Mutex
+	epoch   string
+	batches [][]batchEntry
+	next    uint64 // sequence number of the next pushed batch
+}
+
+type replayBatch struct {
+	seq   uint64
+	id    string
+	batch []batchEntry
+}
+
+func newBatchRing(size int) *batchRing {
+	return &batchRing{
+		epoch:   strconv.FormatInt(time.Now().UnixNano(), 36),
+		batches: make([][]batchEntry, size),
+		next:    1,
+	}
+}
+
+func (r *batchRing) push(batch []batchEntry) (seq uint64, id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	seq = r.next
+	r.batches[seq%uint64(len(r.batches))] = batch
+	r.next++
+	return seq, r.formatID(seq)
+}
+
+// since returns the retained batches after lastID. ok is false when lastID
+// is from another run or older than the ring, i.e. the gap cannot be filled.
+func (r *batchRing) since(lastID string) (batches []replayBatch, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	seq, known := r.parseID(lastID)
+	if !known || seq >= r.next || r.next-seq-1 > uint64(len(r.batches)) {
+		return nil, false
+	}
+	for s := seq + 1; s < r.next; s++ {
+		batches = append(batches, replayBatch{seq: s, id: r.formatID(s), batch: r.batches[s%uint64(len(r.batches))]})
+	}
+	return batches, true
+}
+
+func (r *batchRing) formatID(seq uint64) string {
+	return r.epoch + "-" + strconv.FormatUint(seq, 10)
+}
+
+func (r *batchRing) parseID(id string) (uint64, bool) {
+	epoch, seq, found := strings.Cut(id, "-")
+	if !found || epoch != r.epoch {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(seq, 10, 64)
+	return n, err == nil
+}
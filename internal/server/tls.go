@@ -0,0 +1,167 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	certFilename = "openradar.crt"
+	keyFilename  = "openradar.key"
+
+	certValidity = 825 * 24 * time.Hour // longest lifetime Apple devices accept
+	certRenewal  = 30 * 24 * time.Hour
+)
+
+// TLSCert is the radar's self-signed certificate and the SHA-256 fingerprint
+// users compare against the browser's warning page before accepting it.
+type TLSCert struct {
+	Certificate tls.Certificate
+	Fingerprint string
+	NotAfter    time.Time
+}
+
+// LoadOrCreateCert reuses the certificate persisted in dir while it is valid
+// and covers every host, so phones only accept it once. A new LAN address or
+// an upcoming expiry regenerates it, which changes the fingerprint.
+func LoadOrCreateCert(dir string, hosts []string) (*TLSCert, error) {
+	certPath := filepath.Join(dir, certFilename)
+	keyPath := filepath.Join(dir, keyFilename)
+
+	if c, err := loadCert(certPath, keyPath); err == nil && certCovers(c, hosts, time.Now()) {
+		return newTLSCert(c)
+	}
+
+	certPEM, keyPEM, err := generateCert(hosts, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create cert dir: %w", err)
+	}
+	if err := os.WriteFile(keyPath, keyPEM, 0o600); err != nil {
+		return nil, fmt.Errorf("write key: %w", err)
+	}
+	if err := os.WriteFile(certPath, certPEM, 0o644); err != nil {
+		return nil, fmt.Errorf("write cert: %w", err)
+	}
+	c, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("load generated cert: %w", err)
+	}
+	return newTLSCert(c)
+}
+
+func loadCert(certPath, keyPath string) (tls.Certificate, error) {
+	c, err := tls.LoadX509KeyPair(certPath, keyPath)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	if c.Leaf == nil {
+		return tls.Certificate{}, errors.New("certificate has no leaf")
+	}
+	return c, nil
+}
+
+func certCovers(c tls.Certificate, hosts []string, now time.Time) bool {
+	leaf := c.Leaf
+	if now.Before(leaf.NotBefore) || now.Add(certRenewal).After(leaf.NotAfter) {
+		return false
+	}
+	for _, h := range hosts {
+		if leaf.VerifyHostname(h) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func newTLSCert(c tls.Certificate) (*TLSCert, error) {
+	if c.Leaf == nil {
+		leaf, err := x509.ParseCertificate(c.Certificate[0])
+		if err != nil {
+			return nil, fmt.Errorf("parse cert: %w", err)
+		}
+		c.Leaf = leaf
+	}
+	return &TLSCert{
+		Certificate: c,
+		Fingerprint: Fingerprint(c.Leaf.Raw),
+		NotAfter:    c.Leaf.NotAfter,
+	}, nil
+}
+
+// Fingerprint formats the SHA-256 of a DER certificate the way browsers
+// display it: colon-separated uppercase hex.
+func Fingerprint(der []byte) string {
+	sum := sha256.Sum256(der)
+	parts := make([]string, len(sum))
+	for i, b := range sum {
+		parts[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(parts, ":")
+}
+
+// generateCert issues a self-signed ECDSA certificate for localhost, the
+// loopback addresses, the machine hostname and every given host.
+func generateCert(hosts []string, now time.Time) (certPEM, keyPEM []byte, err error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("generate key: %w", err)
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, fmt.Errorf("generate serial: %w", err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "OpenRadar", Organization: []string{"OpenRadar (self-signed)"}},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(certValidity),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+	if name, err := os.Hostname(); err == nil && name != "" {
+		tmpl.DNSNames = append(tmpl.DNSNames, name)
+	}
+	for _, h := range hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			if !slices.ContainsFunc(tmpl.IPAddresses, ip.Equal) {
+				tmpl.IPAddresses = append(tmpl.IPAddresses, ip)
+			}
+		} else if h != "" && !slices.Contains(tmpl.DNSNames, h) {
+			tmpl.DNSNames = append(tmpl.DNSNames, h)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("create cert: %w", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("marshal key: %w", err)
+	}
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return certPEM, keyPEM, nil
+}
@@ -0,0 +1,122 @@
+package server
+
+import (
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestLoadOrCreateCert_PersistsAndReuses(t *testing.T) {
+	dir := t.TempDir()
+	first, err := LoadOrCreateCert(dir, []string{"192.168.1.5"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := first.Certificate.Leaf.VerifyHostname("192.168.1.5"); err != nil {
+		t.Errorf("LAN IP missing from SANs: %v", err)
+	}
+	if err := first.Certificate.Leaf.VerifyHostname("localhost"); err != nil {
+		t.Errorf("localhost missing from SANs: %v", err)
+	}
+	info, err := os.Stat(filepath.Join(dir, keyFilename))
+	if err != nil {
+		t.Fatalf("key not persisted: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm&0o077 != 0 && os.PathSeparator == '/' {
+		t.Errorf("key perms %v, want owner-only", perm)
+	}
+
+	again, err := LoadOrCreateCert(dir, []string{"192.168.1.5"})
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if again.Fingerprint != first.Fingerprint {
+		t.Error("a valid persisted certificate must be reused, phones would have to re-accept it")
+	}
+}
+
+func TestLoadOrCreateCert_RegeneratesForNewLANAddress(t *testing.T) {
+	dir := t.TempDir()
+	first, err := LoadOrCreateCert(dir, []string{"192.168.1.5"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	second, err := LoadOrCreateCert(dir, []string{"10.0.0.7"})
+	if err != nil {
+		t.Fatalf("regenerate: %v", err)
+	}
+	if second.Fingerprint == first.Fingerprint {
+		t.Error("certificate not regenerated for an address outside its SANs")
+	}
+	if err := second.Certificate.Leaf.VerifyHostname("10.0.0.7"); err != nil {
+		t.Errorf("new LAN IP missing from SANs: %v", err)
+	}
+}
+
+func TestCertCovers_RenewsBeforeExpiry(t *testing.T) {
+	certPEM, keyPEM, err := generateCert(nil, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !certCovers(c, []string{"localhost"}, time.Now()) {
+		t.Error("fresh certificate should cover localhost")
+	}
+	if certCovers(c, []string{"localhost"}, c.Leaf.NotAfter.Add(-certRenewal/2)) {
+		t.Error("certificate inside the renewal window must be replaced")
+	}
+}
+
+func TestFingerprintFormat(t *testing.T) {
+	fp := Fingerprint([]byte("x"))
+	if len(fp) != 32*3-1 {
+		t.Errorf("len=%d, want 95 (32 hex pairs joined by colons)", len(fp))
+	}
+}
+
+func TestHTTPServer_ServesTLS(t *testing.T) {
+	cert, err := LoadOrCreateCert(t.TempDir(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	probe, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := probe.Addr().(*net.TCPAddr).Port
+	probe.Close()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) { _, _ = io.WriteString(w, "ok") })
+	s := &HTTPServer{listen: ListenAddr{Host: "127.0.0.1", Port: port}, mux: mux}
+	s.UseTLS(cert)
+	if err := s.Listen(); err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	go func() { _ = s.Start() }()
+	t.Cleanup(func() { _ = s.server.Close() })
+
+	if s.Scheme() != "https" || s.WSScheme() != "wss" {
+		t.Errorf("schemes = %s/%s, want https/wss", s.Scheme(), s.WSScheme())
+	}
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, //nolint:gosec // self-signed test cert
+	}}
+	resp, err := client.Get("https://127.0.0.1:" + strconv.Itoa(port) + "/")
+	if err != nil {
+		t.Fatalf("GET over TLS: %v", err)
+	}
+	defer resp.Body.Close()
+	if got := Fingerprint(resp.TLS.PeerCertificates[0].Raw); got != cert.Fingerprint {
+		t.Errorf("served cert fingerprint %s, want %s", got, cert.Fingerprint)
+	}
+}
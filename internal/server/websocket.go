@@ -1,6 +1,7 @@
 package server
 
 import (
+	"net"
 	"net/http"
 	"sync"
 	"time"
@@ -20,8 +21,8 @@ const (
 
 // WSBatchMessage represents a batch of messages
 type WSBatchMessage struct {
-	Type     string        `json:"type"`
-	Messages []any `json:"messages"`
+	Type     string `json:"type"`
+	Messages []any  `json:"messages"`
 }
 
 // WSStats holds WebSocket statistics
@@ -34,16 +35,20 @@ type WSStats struct {
 
 // WebSocketHandler manages WebSocket connections and broadcasts
 type WebSocketHandler struct {
-	clients   map[*websocket.Conn]bool
-	clientsMu sync.RWMutex
-	upgrader  websocket.Upgrader
-	logger    *logger.Logger
+	clients    map[*websocket.Conn]streamFilter
+	sseClients map[*sseClient]struct{}
+	clientsMu  sync.RWMutex
+	closed     bool
+	upgrader   websocket.Upgrader
+	logger     *logger.Logger
+	clientLogs *clientLogGate
 
 	// Batching
-	batchBuffer []any
+	batchBuffer []batchEntry
 	batchMu     sync.Mutex
 	batchTicker *time.Ticker
 	stopBatch   chan struct{}
+	replay      *batchRing
 
 	// Stats
 	batchesSent  uint64
@@ -54,10 +59,13 @@ type WebSocketHandler struct {
 // NewWebSocketHandler creates a new WebSocket handler
 func NewWebSocketHandler(log *logger.Logger) *WebSocketHandler {
 	ws := &WebSocketHandler{
-		clients:     make(map[*websocket.Conn]bool),
+		clients:     make(map[*websocket.Conn]streamFilter),
+		sseClients:  make(map[*sseClient]struct{}),
 		logger:      log,
-		batchBuffer: make([]any, 0, MaxBatchSize),
+		clientLogs:  newClientLogGate(),
+		batchBuffer: make([]batchEntry, 0, MaxBatchSize),
 		stopBatch:   make(chan struct{}),
+		replay:      newBatchRing(StreamReplayBatches),
 		upgrader: websocket.Upgrader{
 			CheckOrigin: func(r *http.Request) bool {
 				return true
@@ -91,41 +99,57 @@ func (ws *WebSocketHandler) flushBatch() {
 	}
 	batch := ws.batchBuffer
 	msgCount := uint64(len(batch))
-	ws.batchBuffer = make([]any, 0, MaxBatchSize)
+	ws.batchBuffer = make([]batchEntry, 0, MaxBatchSize)
 	ws.batchMu.Unlock()
 
-	msg := &WSBatchMessage{Type: "batch", Messages: batch}
-	data, err := json.Marshal(msg)
-	if err != nil {
+	enc := newBatchEncoder(batch)
+	if _, err := enc.encode(streamFilter{}); err != nil {
 		logger.PrintWarn("WS", "batch marshal failed: %v (batch size=%d, DROPPED)", err, msgCount)
 		// Try to identify which message failed by marshaling each one individually.
-		for i, m := range batch {
-			if _, err := json.Marshal(m); err != nil {
-				logger.PrintWarn("WS", "  offending message[%d]: %v (type=%T, value=%+v)", i, err, m, m)
+		for i, e := range batch {
+			if _, err := json.Marshal(e.msg); err != nil {
+				logger.PrintWarn("WS", "  offending message[%d]: %v (type=%T, value=%+v)", i, err, e.msg, e.msg)
 			}
 		}
 		return
 	}
+	seq, id := ws.replay.push(batch)
 
-	dataLen := uint64(len(data))
 	var failedClients []*websocket.Conn
-	var sentCount uint64
+	var slowStreams []*sseClient
+	var bytesSent uint64
 
 	ws.clientsMu.RLock()
-	for client := range ws.clients {
+	for client, filter := range ws.clients {
+		data, _ := enc.encode(filter)
+		if data == nil {
+			continue
+		}
 		if err := client.WriteMessage(websocket.TextMessage, data); err != nil {
 			failedClients = append(failedClients, client)
 		} else {
-			sentCount++
+			bytesSent += uint64(len(data))
+		}
+	}
+	for sub := range ws.sseClients {
+		data, _ := enc.encode(sub.filter)
+		if data == nil {
+			continue
+		}
+		select {
+		case sub.frames <- sseFrame{seq: seq, id: id, data: data}:
+			bytesSent += uint64(len(data))
+		default:
+			slowStreams = append(slowStreams, sub)
 		}
 	}
 	ws.clientsMu.RUnlock()
 
 	ws.batchesSent++
 	ws.messagesSent += msgCount
-	ws.bytesSent += dataLen * sentCount
+	ws.bytesSent += bytesSent
 
-	if len(failedClients) > 0 {
+	if len(failedClients) > 0 || len(slowStreams) > 0 {
 		ws.clientsMu.Lock()
 		for _, client := range failedClients {
 			if _, exists := ws.clients[client]; exists {
@@ -133,6 +157,11 @@ func (ws *WebSocketHandler) flushBatch() {
 				delete(ws.clients, client)
 			}
 		}
+		// A stream that cannot keep up is dropped; EventSource reconnects
+		// with Last-Event-ID and catches up from the replay ring.
+		for _, sub := range slowStreams {
+			ws.removeStreamLocked(sub)
+		}
 		ws.clientsMu.Unlock()
 	}
 }
@@ -158,6 +187,13 @@ func (ws *WebSocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 // handleConnection handles new WebSocket connections
 func (ws *WebSocketHandler) handleConnection(w http.ResponseWriter, r *http.Request) {
+	// ?instance= follows one game client from the first batch on.
+	instance, err := parseInstance(r.URL.Query().Get("instance"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	// Upgrade connection first (doesn't require lock)
 	conn, err := ws.upgrader.Upgrade(w, r, nil)
 	if err != nil {
@@ -167,15 +203,16 @@ func (ws *WebSocketHandler) handleConnection(w http.ResponseWriter, r *http.Requ
 
 	// Check limit AND register atomically to fix race condition
 	ws.clientsMu.Lock()
-	if len(ws.clients) >= MaxWebSocketClients {
+	if ws.closed || ws.clientCountLocked() >= MaxWebSocketClients {
 		ws.clientsMu.Unlock()
 		_ = conn.Close()
 		logger.PrintWarn("WS", "Connection rejected: max clients reached (%d)", MaxWebSocketClients)
 		return
 	}
-	ws.clients[conn] = true
-	clientCount := len(ws.clients)
+	ws.clients[conn] = streamFilter{instance: instance}
+	clientCount := ws.clientCountLocked()
 	ws.clientsMu.Unlock()
+	conn.SetReadLimit(MaxClientMessageBytes)
 
 	logger.PrintInfo("WS", "Client connected (%d total)", clientCount)
 
@@ -188,7 +225,7 @@ func (ws *WebSocketHandler) handleMessages(conn *websocket.Conn) {
 	defer func() {
 		ws.clientsMu.Lock()
 		delete(ws.clients, conn)
-		clientCount := len(ws.clients)
+		clientCount := ws.clientCountLocked()
 		ws.clientsMu.Unlock()
 		_ = conn.Close()
 		logger.PrintInfo("WS", "Client disconnected (%d remaining)", clientCount)
@@ -207,25 +244,52 @@ func (ws *WebSocketHandler) handleMessages(conn *websocket.Conn) {
 			break
 		}
 
-		// Parse incoming message (for logs)
+		// Parse incoming message (logs, or a filter for this client's batches)
 		var data struct {
-			Type string        `json:"type"`
-			Logs []any `json:"logs"`
+			Type     string            `json:"type"`
+			Logs     []json.RawMessage `json:"logs"`
+			Kinds    []string          `json:"kinds"`
+			Codes    []int             `json:"codes"`
+			Instance *uint16           `json:"instance"`
 		}
 		if err := json.Unmarshal(message, &data); err == nil {
-			if data.Type == "logs" && len(data.Logs) > 0 && ws.logger != nil {
-				ws.logger.WriteLogs(data.Logs)
+			switch data.Type {
+			case "logs":
+				if len(data.Logs) > 0 && ws.logger != nil {
+					ws.logger.WriteLogs(ws.clientLogs.filter(clientAddr(conn), data.Logs))
+				}
+			case "filter":
+				ws.setFilter(conn, data.Kinds, data.Codes, data.Instance)
 			}
 		}
 	}
 }
 
+// ClientLogStats counts the browser log entries written and rejected.
+func (ws *WebSocketHandler) ClientLogStats() ClientLogStats {
+	return ws.clientLogs.stats()
+}
+
+// clientAddr is the remote host, without the port, so that the log budget
+// is per machine rather than per connection.
+func clientAddr(conn *websocket.Conn) string {
+	host, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+	if err != nil {
+		return conn.RemoteAddr().String()
+	}
+	return host
+}
+
 // CloseAllClients closes all WebSocket connections gracefully
 func (ws *WebSocketHandler) CloseAllClients() {
 	close(ws.stopBatch)
 	ws.flushBatch() // Flush remaining events
 
 	ws.clientsMu.Lock()
+	ws.closed = true
+	for sub := range ws.sseClients {
+		ws.removeStreamLocked(sub)
+	}
 	for client := range ws.clients {
 		_ = client.WriteMessage(
 			websocket.CloseMessage,
@@ -237,20 +301,56 @@ func (ws *WebSocketHandler) CloseAllClients() {
 	ws.clientsMu.Unlock()
 }
 
-// broadcastPayload adds a message to the batch buffer
-func (ws *WebSocketHandler) broadcastPayload(code string, payload any) {
+// setFilter replaces the filter applied to a WebSocket client's batches. A
+// filter that fails to parse leaves the previous one in place, and a nil
+// instance keeps the game client the connection follows.
+func (ws *WebSocketHandler) setFilter(conn *websocket.Conn, kinds []string, codes []int, instance *uint16) {
+	filter, err := newStreamFilter(kinds, codes)
+	if err != nil {
+		logger.PrintWarn("WS", "Ignoring client filter: %v", err)
+		return
+	}
+	ws.clientsMu.Lock()
+	if prev, ok := ws.clients[conn]; ok {
+		filter.instance = prev.instance
+		if instance != nil {
+			filter.instance = *instance
+		}
+		ws.clients[conn] = filter
+	}
+	ws.clientsMu.Unlock()
+}
+
+// broadcastPayload adds a message to the batch buffer. Messages that came
+// from a captured packet also carry its capture time in unix ms, its
+// direction and server endpoint, the game client instance (its local UDP
+// port), and on server->client packets the server's own clock as serverTime.
+func (ws *WebSocketHandler) broadcastPayload(kind string, code int, meta photon.PacketMeta, payload any) {
 	msg := map[string]any{
-		"code":       code,
+		"code":       kind,
 		"dictionary": payload,
 	}
+	var instance uint16
+	if !meta.Time.IsZero() {
+		msg["time"] = meta.Time.UnixMilli()
+	}
+	if meta.Direction != photon.DirUnknown {
+		instance = meta.Client.Port()
+		msg["direction"] = meta.Direction.String()
+		msg["server"] = meta.Server.String()
+		msg["instance"] = instance
+	}
+	if t, ok := meta.ServerTime(); ok {
+		msg["serverTime"] = t
+	}
 	ws.batchMu.Lock()
-	ws.batchBuffer = append(ws.batchBuffer, msg)
+	ws.batchBuffer = append(ws.batchBuffer, batchEntry{kind: kind, code: code, instance: instance, msg: msg})
 	ws.batchMu.Unlock()
 }
 
 // BroadcastEvent broadcasts an event to all clients
 func (ws *WebSocketHandler) BroadcastEvent(event *photon.EventData) {
-	ws.broadcastPayload("event", map[string]any{
+	ws.broadcastPayload("event", photon.AlbionCode(event.Parameters, 252), event.Meta, map[string]any{
 		"code":       event.Code,
 		"parameters": event.Parameters,
 	})
@@ -258,7 +358,7 @@ func (ws *WebSocketHandler) BroadcastEvent(event *photon.EventData) {
 
 // BroadcastRequest broadcasts a request to all clients
 func (ws *WebSocketHandler) BroadcastRequest(req *photon.OperationRequest) {
-	ws.broadcastPayload("request", map[string]any{
+	ws.broadcastPayload("request", photon.AlbionCode(req.Parameters, 253), req.Meta, map[string]any{
 		"operationCode": req.OperationCode,
 		"parameters":    req.Parameters,
 	})
@@ -266,7 +366,7 @@ func (ws *WebSocketHandler) BroadcastRequest(req *photon.OperationRequest) {
 
 // BroadcastResponse broadcasts a response to all clients
 func (ws *WebSocketHandler) BroadcastResponse(resp *photon.OperationResponse) {
-	ws.broadcastPayload("response", map[string]any{
+	ws.broadcastPayload("response", photon.AlbionCode(resp.Parameters, 253), resp.Meta, map[string]any{
 		"operationCode": resp.OperationCode,
 		"returnCode":    resp.ReturnCode,
 		"debugMessage":  resp.DebugMessage,
@@ -274,9 +374,22 @@ func (ws *WebSocketHandler) BroadcastResponse(resp *photon.OperationResponse) {
 	})
 }
 
-// ClientCount returns the number of connected clients
+// BroadcastInterfaces tells every client that the capture interfaces
+// changed, as a "network" message whose parameters are the change.
+func (ws *WebSocketHandler) BroadcastInterfaces(c InterfaceChange) {
+	ws.broadcastPayload("network", -1, photon.PacketMeta{}, map[string]any{
+		"parameters": c,
+	})
+}
+
+// ClientCount returns the number of connected clients, WebSocket and
+// event-stream alike.
 func (ws *WebSocketHandler) ClientCount() int {
 	ws.clientsMu.RLock()
 	defer ws.clientsMu.RUnlock()
-	return len(ws.clients)
+	return ws.clientCountLocked()
+}
+
+func (ws *WebSocketHandler) clientCountLocked() int {
+	return len(ws.clients) + len(ws.sseClients)
 }
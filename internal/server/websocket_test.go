@@ -1,8 +1,11 @@
 package server
 
 import (
+	"net/netip"
 	"testing"
+	"time"
 
+	"github.com/gorilla/websocket"
 	"github.com/segmentio/encoding/json"
 	"github.com/stretchr/testify/require"
 
@@ -43,3 +46,39 @@ func TestBroadcastEvent_ByteArray_BufferShape(t *testing.T) {
 	require.NoError(t, err)
 	require.Contains(t, string(out), `{"type":"Buffer","data":[1,2,255]}`)
 }
+
+func TestBroadcastEvent_CarriesPacketMeta(t *testing.T) {
+	ws := &WebSocketHandler{}
+	meta := photon.FlowMeta(time.UnixMilli(1700000000123),
+		netip.MustParseAddrPort("5.188.125.10:5056"), netip.MustParseAddrPort("192.168.1.2:50000"), photon.ServerPort)
+	meta.SentTime = 987654
+	ws.BroadcastEvent(&photon.EventData{Code: 1, Parameters: map[byte]any{}, Meta: meta})
+	ws.BroadcastEvent(&photon.EventData{Code: 1, Parameters: map[byte]any{}})
+
+	require.Len(t, ws.batchBuffer, 2)
+	out, err := json.Marshal(ws.batchBuffer[0].msg)
+	require.NoError(t, err)
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(out, &got))
+	require.EqualValues(t, 1700000000123, got["time"])
+	require.Equal(t, "server->client", got["direction"])
+	require.Equal(t, "5.188.125.10:5056", got["server"])
+	require.EqualValues(t, 987654, got["serverTime"])
+	require.EqualValues(t, 50000, got["instance"])
+	require.Equal(t, uint16(50000), ws.batchBuffer[0].instance)
+
+	require.NotContains(t, ws.batchBuffer[1].msg, "time", "bare payloads carry no meta")
+}
+
+func TestSetFilter_KeepsTheInstanceWhenAbsent(t *testing.T) {
+	ws := &WebSocketHandler{clients: map[*websocket.Conn]streamFilter{nil: {instance: 50001}}}
+
+	ws.setFilter(nil, []string{"event"}, nil, nil)
+	require.Equal(t, uint16(50001), ws.clients[nil].instance, "a filter without instance keeps the followed client")
+	require.True(t, ws.clients[nil].match(batchEntry{kind: "event", instance: 50001}))
+	require.False(t, ws.clients[nil].match(batchEntry{kind: "request", instance: 50001}))
+
+	zero := uint16(0)
+	ws.setFilter(nil, nil, nil, &zero)
+	require.Zero(t, ws.clients[nil].instance, "instance 0 follows every client again")
+}
@@ -0,0 +1,220 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// InterfaceOption is an enumerated capture interface as the Config tab lists
+// it. Active is true while the Manager captures on it; Error is its last open
+// or read error; Endpoints are the game servers seen on it, most recent
+// first.
+type InterfaceOption struct {
+	Name        string
+	Description string
+	Address     string
+	Category    string
+	Active      bool
+	Error       string
+	Endpoints   []GameEndpoint
+}
+
+// GameEndpoint mirrors internal/capture.GameEndpoint. Region is "" when the
+// region table does not know the address.
+type GameEndpoint struct {
+	Address string
+	Region  string
+}
+
+// maxEndpointLines is how many game servers the Config tab lists under an
+// interface.
+const maxEndpointLines = 3
+
+// label is the description, or the device name when pcap has none (Linux).
+func (o InterfaceOption) label() string {
+	if o.Description != "" {
+		return o.Description
+	}
+	return o.Name
+}
+
+// Controls are the capture actions the dashboard can trigger. They run off
+// the UI loop, so they may block. A nil func disables the matching key.
+type Controls struct {
+	// SelectInterfaces captures on exactly these interfaces and persists them.
+	SelectInterfaces func(names []string) error
+	// SetRecording starts or stops pcap recording and persists the choice.
+	SetRecording func(on bool) error
+	// SaveBuffer writes the in-memory packet buffer to disk and describes
+	// what it wrote.
+	SaveBuffer func() (string, error)
+}
+
+// ControlResultMsg reports how a Controls action ended.
+type ControlResultMsg struct {
+	Action string
+	Err    error
+}
+
+// WithControls enables interface selection in the Config tab and the
+// recording key.
+func (d Dashboard) WithControls(c Controls) Dashboard {
+	d.controls = c
+	return d
+}
+
+// handleConfigKey moves the interface cursor and toggles the selected
+// interface. It reports false for keys the Config tab does not use.
+func (d *Dashboard) handleConfigKey(msg tea.KeyMsg) (bool, tea.Cmd) {
+	if d.controls.SelectInterfaces == nil || len(d.interfaces) == 0 {
+		return false, nil
+	}
+	switch msg.String() {
+	case "up", "k":
+		d.ifaceCursor = max(d.ifaceCursor-1, 0)
+	case "down", "j":
+		d.ifaceCursor = min(d.ifaceCursor+1, len(d.interfaces)-1)
+	case " ", "enter":
+		return true, d.toggleInterface()
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+func (d *Dashboard) toggleInterface() tea.Cmd {
+	d.ifaceCursor = min(d.ifaceCursor, len(d.interfaces)-1)
+	target := &d.interfaces[d.ifaceCursor]
+	target.Active = !target.Active
+
+	var names []string
+	for _, i := range d.interfaces {
+		if i.Active {
+			names = append(names, i.Name)
+		}
+	}
+	action := "Capture stopped on " + target.label()
+	if target.Active {
+		action = "Capture started on " + target.label()
+	}
+	d.controlNote, d.controlErr = "Applying...", false
+
+	selectInterfaces := d.controls.SelectInterfaces
+	return func() tea.Msg {
+		return ControlResultMsg{Action: action, Err: selectInterfaces(names)}
+	}
+}
+
+func (d *Dashboard) toggleRecording() tea.Cmd {
+	if d.controls.SetRecording == nil {
+		return nil
+	}
+	d.recording = !d.recording
+	on := d.recording
+	action := "Recording stopped"
+	if on {
+		action = "Recording started"
+	}
+	d.controlNote, d.controlErr = "Applying...", false
+
+	setRecording := d.controls.SetRecording
+	return func() tea.Msg {
+		return ControlResultMsg{Action: action, Err: setRecording(on)}
+	}
+}
+
+func (d *Dashboard) saveBuffer() tea.Cmd {
+	if d.controls.SaveBuffer == nil {
+		return nil
+	}
+	d.controlNote, d.controlErr = "Saving packet buffer...", false
+
+	saveBuffer := d.controls.SaveBuffer
+	return func() tea.Msg {
+		action, err := saveBuffer()
+		if action == "" {
+			action = "Save packet buffer"
+		}
+		return ControlResultMsg{Action: action, Err: err}
+	}
+}
+
+func (d *Dashboard) applyControlResult(msg ControlResultMsg) {
+	if msg.Err != nil {
+		d.controlNote, d.controlErr = fmt.Sprintf("%s failed: %v", msg.Action, msg.Err), true
+		return
+	}
+	d.controlNote, d.controlErr = msg.Action, false
+}
+
+// awaitingInterfaces is true when the Manager reports no open capture.
+func (d *Dashboard) awaitingInterfaces() bool {
+	return d.captureStatus == "awaiting_interfaces"
+}
+
+func (d *Dashboard) bannerHeight() int {
+	if d.awaitingInterfaces() {
+		return 1
+	}
+	return 0
+}
+
+func (d *Dashboard) renderBanner() string {
+	hint := "Settings -> Network in the browser"
+	if d.controls.SelectInterfaces != nil {
+		hint = "the Config tab (3) or " + hint
+	}
+	return BannerStyle.Width(d.width).Render("⚠ No interface is capturing. Pick one in " + hint + ".")
+}
+
+// formatEndpoint is one game server line under an interface. An unknown
+// region usually means a proxy such as ExitLag, or a table to update.
+func formatEndpoint(e GameEndpoint) string {
+	region := StatValueStyle.Render(e.Region)
+	if e.Region == "" {
+		region = LogWarnStyle.Render("unknown region (proxy, or not in regions.json)")
+	}
+	return StatLabelStyle.Render("↳ game server ") + URLStyle.Render(fmt.Sprintf("%-21s", e.Address)) + " " + region
+}
+
+// renderInterfaceLines is the Config tab's interface picker.
+func (d *Dashboard) renderInterfaceLines() []string {
+	recording := StatLabelStyle.Render("off")
+	if d.recording {
+		recording = StatusOffStyle.Render("● recording")
+	}
+	lines := []string{
+		fmt.Sprintf(" 🔌 %s %s", TitleStyle.Render("Capture interfaces"), StatLabelStyle.Render("(space: toggle)")),
+	}
+	for i, opt := range d.interfaces {
+		cursor := "  "
+		if i == d.ifaceCursor && d.currentTab == TabConfig {
+			cursor = TabActiveStyle.Render("▶ ")
+		}
+		box, style := "[ ]", StatLabelStyle
+		if opt.Active {
+			box, style = "[x]", StatValueStyle
+		}
+		line := fmt.Sprintf(" %s%s %s %s %s", cursor, box,
+			style.Render(fmt.Sprintf("%-24s", truncate(opt.label(), 24))),
+			URLStyle.Render(fmt.Sprintf("%-15s", opt.Address)),
+			StatLabelStyle.Render(opt.Category))
+		if opt.Error != "" {
+			line += " " + LogErrorStyle.Render(truncate(opt.Error, 40))
+		}
+		lines = append(lines, line)
+		for _, e := range opt.Endpoints[:min(len(opt.Endpoints), maxEndpointLines)] {
+			lines = append(lines, "         "+formatEndpoint(e))
+		}
+	}
+	lines = append(lines, fmt.Sprintf(" %s %s %s", StatLabelStyle.Render("Recording:"), recording, StatLabelStyle.Render("(w: toggle, b: save last minutes)")))
+	if d.controlNote != "" {
+		style := LogSuccessStyle
+		if d.controlErr {
+			style = LogErrorStyle
+		}
+		lines = append(lines, " "+style.Render(d.controlNote))
+	}
+	return lines
+}
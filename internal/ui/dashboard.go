@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"net"
 	"strconv"
 	"strings"
 	"time"
@@ -10,6 +11,7 @@ import (
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
 )
 
 const (
@@ -27,6 +29,11 @@ const (
 	TabLogs ViewTab = iota
 	TabStats
 	TabConfig
+	TabCodes
+	TabInspector
+	TabNearby
+
+	tabCount = 6
 )
 
 // Log levels for filtering
@@ -78,10 +85,50 @@ type CaptureSummary struct {
 	Category    string
 }
 
+// ClientInstance mirrors internal/server.ClientInstance: one game client on
+// the capture PC. Primary is the one the Nearby tab shows.
+type ClientInstance struct {
+	Port     uint16
+	Cluster  string
+	Server   string
+	Packets  uint64
+	LastSeen time.Time
+	Primary  bool
+}
+
+// GameServer mirrors the current internal/photon.Conn.
+type GameServer struct {
+	Address    string
+	Ping       time.Duration
+	LastPacket time.Time
+}
+
 type CaptureStateMsg struct {
 	Active       []CaptureSummary
 	LanAddresses []string
 	Status       string
+	// Interfaces and Recording feed the Config tab controls.
+	Interfaces []InterfaceOption
+	Recording  bool
+	// GameServer is nil while the client is not connected to one.
+	GameServer *GameServer
+	// Instances are the game clients seen, one per local UDP port.
+	Instances []ClientInstance
+}
+
+// CodeStat mirrors internal/photon/codestats.Stat.
+type CodeStat struct {
+	Kind     string
+	Code     int
+	Name     string
+	Count    uint64
+	Rate     float64
+	LastSeen time.Time
+}
+
+// CodesMsg carries the per-code counters, busiest first.
+type CodesMsg struct {
+	Codes []CodeStat
 }
 
 type RestartMsg struct{}
@@ -110,12 +157,26 @@ type Dashboard struct {
 	lanServerURL string
 	lanWsURL     string
 	mode         string
+	host         string
 	port         int
+	// tlsFingerprint is set when the server runs HTTPS; URLs switch to
+	// https/wss and the Config tab shows it for manual verification.
+	tlsFingerprint string
 
 	// Capture interfaces and LAN addresses (sourced from Manager.State() poll)
 	captureInterfaces []CaptureSummary
 	lanAddresses      []string
 	captureStatus     string
+	gameServer        *GameServer
+	instances         []ClientInstance
+
+	// Capture controls (Config tab and recording key)
+	controls    Controls
+	interfaces  []InterfaceOption
+	recording   bool
+	ifaceCursor int
+	controlNote string
+	controlErr  bool
 
 	// Status indicators
 	httpRunning    bool
@@ -149,6 +210,15 @@ type Dashboard struct {
 	logBatches    uint64
 	logBufferSize int
 
+	// Per-code counters (Codes tab)
+	codes []CodeStat
+
+	// Decoded message stream (Inspector tab)
+	inspector inspector
+
+	// Entities in the current cluster (Nearby tab)
+	nearby nearby
+
 	// Sparkline history
 	packetsHistory   []uint64
 	memoryHistory    []float64
@@ -174,8 +244,9 @@ type Dashboard struct {
 	height int
 }
 
-// NewDashboard creates a new dashboard model
-func NewDashboard(version string, port int, devMode bool, lanAddresses []string, captures []CaptureSummary) Dashboard {
+// NewDashboard creates a new dashboard model. host is the address local URLs
+// point at, "localhost" unless the server is bound to a single LAN address.
+func NewDashboard(version, host string, port int, devMode bool, lanAddresses []string, captures []CaptureSummary) Dashboard {
 	mode := "Production"
 	if devMode {
 		mode = "Development"
@@ -187,9 +258,8 @@ func NewDashboard(version string, port int, devMode bool, lanAddresses []string,
 
 	d := Dashboard{
 		version:           version,
-		serverURL:         fmt.Sprintf("http://localhost:%d", port),
-		wsURL:             fmt.Sprintf("ws://localhost:%d/ws", port),
 		mode:              mode,
+		host:              host,
 		port:              port,
 		startTime:         time.Now(),
 		logs:              make([]LogEntry, 0, maxLogs),
@@ -201,16 +271,55 @@ func NewDashboard(version string, port int, devMode bool, lanAddresses []string,
 		currentTab:        TabLogs,
 		logFilter:         LevelAll,
 		searchInput:       ti,
+		inspector:         newInspector(),
+		nearby:            newNearby(),
 		captureInterfaces: captures,
 		lanAddresses:      lanAddresses,
 	}
-	if len(lanAddresses) > 0 && lanAddresses[0] != "127.0.0.1" {
-		d.lanServerURL = fmt.Sprintf("http://%s:%d", lanAddresses[0], port)
-		d.lanWsURL = fmt.Sprintf("ws://%s:%d/ws", lanAddresses[0], port)
-	}
+	d.setURLs()
+	return d
+}
+
+// WithTLS switches the displayed URLs to https/wss and records the
+// certificate fingerprint shown in the Config tab.
+func (d Dashboard) WithTLS(fingerprint string) Dashboard {
+	d.tlsFingerprint = fingerprint
+	d.setURLs()
 	return d
 }
 
+func (d *Dashboard) schemes() (httpScheme, wsScheme string) {
+	if d.tlsFingerprint != "" {
+		return "https", "wss"
+	}
+	return "http", "ws"
+}
+
+func (d *Dashboard) setURLs() {
+	httpScheme, wsScheme := d.schemes()
+	d.serverURL = hostURL(httpScheme, d.host, d.port)
+	d.wsURL = hostURL(wsScheme, d.host, d.port) + "/ws"
+	d.setLANURLs(d.lanAddresses)
+}
+
+// hostURL is scheme://host:port, with an IPv6 host in brackets.
+func hostURL(scheme, host string, port int) string {
+	return scheme + "://" + net.JoinHostPort(host, strconv.Itoa(port))
+}
+
+// setLANURLs derives the LAN URLs from the first LAN address, or clears them
+// when there is none or it is already the local URL host.
+func (d *Dashboard) setLANURLs(lanAddresses []string) {
+	if len(lanAddresses) > 0 && lanAddresses[0] != "127.0.0.1" && lanAddresses[0] != d.host {
+		httpScheme, wsScheme := d.schemes()
+		d.lanServerURL = hostURL(httpScheme, lanAddresses[0], d.port)
+		d.lanWsURL = hostURL(wsScheme, lanAddresses[0], d.port) + "/ws"
+	} else {
+		d.lanServerURL = ""
+		d.lanWsURL = ""
+	}
+}
+
 // RestartRequested returns true if user requested a restart
 func (d Dashboard) RestartRequested() bool {
 	return d.restartRequested
@@ -258,6 +367,21 @@ func (d Dashboard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if d.currentTab == TabInspector && msg.String() != "ctrl+c" {
+			if handled, cmd := d.inspector.handleKey(msg); handled {
+				return d, cmd
+			}
+		}
+		if d.currentTab == TabNearby && msg.String() != "ctrl+c" {
+			if handled, cmd := d.nearby.handleKey(msg); handled {
+				return d, cmd
+			}
+		}
+		if d.currentTab == TabConfig {
+			if handled, cmd := d.handleConfigKey(msg); handled {
+				return d, cmd
+			}
+		}
 		switch msg.String() {
 		case "q", "ctrl+c":
 			d.quitting = true
@@ -275,6 +399,10 @@ func (d Dashboard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "p":
 			d.autoScroll = !d.autoScroll
 			return d, nil
+		case "w":
+			return d, d.toggleRecording()
+		case "b":
+			return d, d.saveBuffer()
 		case "c":
 			d.logs = make([]LogEntry, 0, maxLogs)
 			d.viewport.SetContent(d.renderLogs())
@@ -296,8 +424,17 @@ func (d Dashboard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "3":
 			d.currentTab = TabConfig
 			return d, nil
+		case "4":
+			d.currentTab = TabCodes
+			return d, nil
+		case "5":
+			d.currentTab = TabInspector
+			return d, nil
+		case "6":
+			d.currentTab = TabNearby
+			return d, nil
 		case "tab":
-			d.currentTab = (d.currentTab + 1) % 3
+			d.currentTab = (d.currentTab + 1) % tabCount
 			return d, nil
 		}
 
@@ -305,7 +442,7 @@ func (d Dashboard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		d.width = msg.Width
 		d.height = msg.Height
 
-		viewportHeight := d.height - headerHeight - footerHeight - 2
+		viewportHeight := d.viewportHeight()
 		if !d.ready {
 			d.viewport = viewport.New(d.width-2, viewportHeight)
 			d.viewport.SetContent(d.renderLogs())
@@ -382,14 +519,30 @@ func (d Dashboard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		d.captureInterfaces = msg.Active
 		d.lanAddresses = msg.LanAddresses
 		d.captureStatus = msg.Status
-		if len(msg.LanAddresses) > 0 && msg.LanAddresses[0] != "127.0.0.1" {
-			d.lanServerURL = fmt.Sprintf("http://%s:%d", msg.LanAddresses[0], d.port)
-			d.lanWsURL = fmt.Sprintf("ws://%s:%d/ws", msg.LanAddresses[0], d.port)
-		} else {
-			d.lanServerURL = ""
-			d.lanWsURL = ""
+		d.setLANURLs(msg.LanAddresses)
+		if msg.Interfaces != nil {
+			d.interfaces = msg.Interfaces
+			d.ifaceCursor = min(d.ifaceCursor, max(len(d.interfaces)-1, 0))
+		}
+		d.recording = msg.Recording
+		d.gameServer = msg.GameServer
+		d.instances = msg.Instances
+		if d.ready {
+			d.viewport.Height = d.viewportHeight()
 		}
 
+	case ControlResultMsg:
+		d.applyControlResult(msg)
+
+	case CodesMsg:
+		d.codes = msg.Codes
+
+	case InspectorMsg:
+		d.inspector.add(msg)
+
+	case EntitiesMsg:
+		d.nearby.msg = msg
+
 	case TickMsg:
 		cmds = append(cmds, tickCmd())
 	}
@@ -412,6 +565,12 @@ func (d Dashboard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return d, tea.Batch(cmds...)
 }
 
+// viewportHeight is the log viewport height left by the header, the footer
+// and the awaiting-interfaces banner.
+func (d *Dashboard) viewportHeight() int {
+	return d.height - headerHeight - footerHeight - 2 - d.bannerHeight()
+}
+
 func (d *Dashboard) addLog(log LogMsg) {
 	entry := LogEntry{
 		Time:    time.Now(),
@@ -510,10 +669,21 @@ func (d Dashboard) View() string {
 		content = BorderStyle.Width(d.width - 2).Render(d.renderStatsView())
 	case TabConfig:
 		content = BorderStyle.Width(d.width - 2).Render(d.renderConfigView())
+	case TabCodes:
+		content = BorderStyle.Width(d.width - 2).Render(d.renderCodesView())
+	case TabInspector:
+		rows := max(d.height-headerHeight-footerHeight-4-d.bannerHeight(), 1)
+		content = BorderStyle.Width(d.width - 2).Render(d.inspector.render(d.width-4, rows))
+	case TabNearby:
+		rows := max(d.height-headerHeight-footerHeight-4-d.bannerHeight(), 1)
+		content = BorderStyle.Width(d.width - 2).Render(d.nearby.render(rows))
 	}
 
 	footer := d.renderFooter()
 
+	if d.awaitingInterfaces() {
+		return lipgloss.JoinVertical(lipgloss.Left, header, d.renderBanner(), content, footer)
+	}
 	return lipgloss.JoinVertical(lipgloss.Left, header, content, footer)
 }
 
@@ -525,6 +695,9 @@ func (d *Dashboard) renderHeader() string {
 	wsStatus := statusIndicator(d.wsRunning, "WS")
 	captureStatus := statusIndicator(d.captureRunning, "CAP")
 	status := fmt.Sprintf("%s %s %s", httpStatus, wsStatus, captureStatus)
+	if d.recording {
+		status = StatusOffStyle.Render("● REC") + "  " + status
+	}
 
 	// Mode and capture interfaces
 	mode := ModeStyle.Render("Mode: " + d.mode)
@@ -547,7 +720,8 @@ func (d *Dashboard) renderHeader() string {
 	tabs := d.renderTabs()
 
 	left := lipgloss.JoinVertical(lipgloss.Left, title, mode, adapter, startedAt)
-	right := lipgloss.JoinVertical(lipgloss.Right, status, httpURL, wsURL, "")
+	game := TimestampStyle.Render(formatGameServerLine(d.gameServer, time.Now()))
+	right := lipgloss.JoinVertical(lipgloss.Right, status, httpURL, wsURL, game)
 
 	leftWidth := lipgloss.Width(left)
 	rightWidth := lipgloss.Width(right)
@@ -560,7 +734,7 @@ func (d *Dashboard) renderHeader() string {
 }
 
 func (d *Dashboard) renderTabs() string {
-	tabs := []string{"[1] Logs", "[2] Stats", "[3] Config"}
+	tabs := []string{"[1] Logs", "[2] Stats", "[3] Config", "[4] Codes", "[5] Inspector", "[6] Nearby"}
 	rendered := make([]string, len(tabs))
 
 	for i, tab := range tabs {
@@ -630,6 +804,31 @@ func (d *Dashboard) renderFooter() string {
 		"q:quit  r:restart  p:pause  c:clear  f:filter  /:search  tab:switch  ↑↓:scroll",
 	)
 
+	if d.currentTab == TabInspector {
+		bottom := d.inspector.statusLine()
+		if d.inspector.filtering {
+			bottom = d.inspector.input.View()
+		}
+		help = HelpStyle.Render(
+			"q:quit  p:pause  c:clear  f:kind  /:code  esc:reset  enter:expand  tab:switch  ↑↓:select",
+		)
+		return FooterStyle.Width(d.width).Align(lipgloss.Center).Render(
+			lipgloss.JoinVertical(lipgloss.Center, stats1, stats2, bottom, help),
+		)
+	}
+	if d.currentTab == TabNearby {
+		bottom := d.nearby.statusLine()
+		if d.nearby.searching {
+			bottom = d.nearby.input.View()
+		}
+		help = HelpStyle.Render(
+			"q:quit  f:kind  s:sort  /:search  esc:reset  tab:switch  ↑↓:scroll",
+		)
+		return FooterStyle.Width(d.width).Align(lipgloss.Center).Render(
+			lipgloss.JoinVertical(lipgloss.Center, stats1, stats2, bottom, help),
+		)
+	}
+
 	// Search input if active
 	if d.searching {
 		searchBox := d.searchInput.View()
@@ -797,12 +996,31 @@ func (d *Dashboard) renderConfigView() string {
 		cfgLine("HTTP URL:", d.serverURL, URLStyle),
 		cfgLine("WS URL:", d.wsURL, URLStyle),
 		cfgLine("Capture:", formatCaptureLine(d.captureInterfaces), StatValueStyle),
-		cfgLine("LAN:", strings.Join(d.lanAddresses, ", "), StatValueStyle),
+		cfgLine("LAN:", formatLANLine(d.lanAddresses), StatValueStyle),
+	}
+	if len(d.interfaces) > 0 {
+		leftLines = append(leftLines, "")
+		leftLines = append(leftLines, d.renderInterfaceLines()...)
+	}
+	if len(d.instances) > 0 {
+		leftLines = append(leftLines, "", section("🎮", "Game clients"))
+		for _, c := range d.instances {
+			leftLines = append(leftLines, " "+formatInstanceLine(c, time.Now()))
+		}
+	}
+	if d.tlsFingerprint != "" {
+		leftLines = append(leftLines,
+			"",
+			section("🔒", "TLS certificate (SHA-256)"),
+			" "+StatValueStyle.Render(d.tlsFingerprint),
+		)
+	}
+	leftLines = append(leftLines,
 		"",
 		section("ℹ️", "About"),
 		cfgLine("", "OpenRadar - Albion Online", StatLabelStyle),
 		cfgLine("", "Real-time packet radar", StatLabelStyle),
-	}
+	)
 
 	// Right column: Keyboard shortcuts (single column for alignment)
 	rightLines := []string{
@@ -810,12 +1028,15 @@ func (d *Dashboard) renderConfigView() string {
 		keyLine("q", "Quit application"),
 		keyLine("r", "Restart application"),
 		keyLine("p", "Toggle auto-scroll"),
+		keyLine("w", "Toggle pcap recording"),
+		keyLine("b", "Save packet buffer"),
+		keyLine("space", "Toggle interface (Config)"),
 		keyLine("c", "Clear logs"),
 		keyLine("f", "Cycle log filter"),
 		keyLine("/", "Search logs"),
 		keyLine("↑↓", "Scroll logs"),
 		keyLine("g/G", "Go to top/bottom"),
-		keyLine("1-3", "Switch tabs"),
+		keyLine("1-6", "Switch tabs"),
 		keyLine("tab", "Next tab"),
 		"",
 		section("📋", "Log Levels"),
@@ -829,6 +1050,51 @@ func (d *Dashboard) renderConfigView() string {
 	return lipgloss.JoinHorizontal(lipgloss.Top, " ", leftCol, " ", rightCol)
 }
 
+// renderCodesView lists the (kind, code) counters busiest first, as many as
+// fit. Codes missing from the JS enums are highlighted: after a game patch
+// they are usually the renumbered ones.
+func (d *Dashboard) renderCodesView() string {
+	if len(d.codes) == 0 {
+		return TimestampStyle.Render("  No messages decoded yet")
+	}
+
+	rows := max(d.height-headerHeight-footerHeight-6-d.bannerHeight(), 1)
+	shown := d.codes[:min(rows, len(d.codes))]
+
+	header := fmt.Sprintf(" %-9s %6s  %-36s %9s %12s  %s", "Kind", "Code", "Name", "Rate/s", "Total", "Last seen")
+	lines := []string{
+		TitleStyle.Render(fmt.Sprintf(" 🔢 %d codes seen, busiest %d by rate over the last 10s", len(d.codes), len(shown))),
+		StatLabelStyle.Render(header),
+	}
+	now := time.Now()
+	for _, c := range shown {
+		name, nameStyle := c.Name, StatValueStyle
+		if name == "" {
+			name, nameStyle = "(unknown)", LogWarnStyle
+		}
+		rateStyle := StatLabelStyle
+		if c.Rate > 0 {
+			rateStyle = StatValueStyle
+		}
+		lines = append(lines, fmt.Sprintf(" %-9s %6d  %s %s %12s  %s",
+			c.Kind,
+			c.Code,
+			nameStyle.Render(fmt.Sprintf("%-36s", truncate(name, 36))),
+			rateStyle.Render(fmt.Sprintf("%9.1f", c.Rate)),
+			formatNumber(c.Count),
+			TimestampStyle.Render(formatDuration(now.Sub(c.LastSeen))+" ago"),
+		))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// truncate shortens s to n terminal cells, ending it with "…". It counts
+// display width, not bytes, so accented and CJK player names are cut on a
+// character boundary.
+func truncate(s string, n int) string {
+	return runewidth.Truncate(s, n, "…")
+}
+
 // Sparkline rendering
 var sparkChars = []rune{'▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
 
@@ -945,6 +1211,38 @@ func formatBytes(b uint64) string {
 	return fmt.Sprintf("%.1f %cB", float64(b)/float64(div), "KMGTPE"[exp])
 }
 
+func formatGameServerLine(g *GameServer, now time.Time) string {
+	if g == nil {
+		return "Game: not connected"
+	}
+	line := "Game: " + g.Address
+	if g.Ping > 0 {
+		line += fmt.Sprintf("  ping %d ms", g.Ping.Milliseconds())
+	}
+	return line + fmt.Sprintf("  last packet %s ago", now.Sub(g.LastPacket).Truncate(time.Second))
+}
+
+// formatInstanceLine is one game client in the Config tab: its local port,
+// cluster, game server and how long it has been silent.
+func formatInstanceLine(c ClientInstance, now time.Time) string {
+	cluster, server := c.Cluster, c.Server
+	if cluster == "" {
+		cluster = "-"
+	}
+	if server == "" {
+		server = "not connected"
+	}
+	line := fmt.Sprintf("%s %s %s %s",
+		URLStyle.Render(fmt.Sprintf(":%-5d", c.Port)),
+		StatValueStyle.Render(fmt.Sprintf("%-12s", truncate(cluster, 12))),
+		StatLabelStyle.Render(fmt.Sprintf("%-21s", server)),
+		StatLabelStyle.Render(fmt.Sprintf("%s ago", now.Sub(c.LastSeen).Truncate(time.Second))))
+	if c.Primary {
+		line += " " + LogSuccessStyle.Render("● Nearby")
+	}
+	return line
+}
+
 func formatCaptureLine(summaries []CaptureSummary) string {
 	if len(summaries) == 0 {
 		return "(awaiting)"
@@ -956,6 +1254,13 @@ func formatCaptureLine(summaries []CaptureSummary) string {
 	return strings.Join(parts, ", ")
 }
 
+func formatLANLine(addrs []string) string {
+	if len(addrs) == 0 {
+		return "(none)"
+	}
+	return strings.Join(addrs, ", ")
+}
+
 func formatDuration(d time.Duration) string {
 	d = d.Round(time.Second)
 
@@ -1,11 +1,18 @@
 package ui
 
 import (
+	"errors"
+	"slices"
+	"strings"
 	"testing"
+	"time"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
 )
 
 func TestCaptureStateMsgUpdatesFields(t *testing.T) {
-	d := NewDashboard("v0", 5001, true, nil, nil)
+	d := NewDashboard("v0", "localhost", 5001, true, nil, nil)
 	msg := CaptureStateMsg{
 		Active: []CaptureSummary{
 			{Description: "Wi-Fi", Address: "192.168.1.42", Category: "wifi"},
@@ -31,7 +38,7 @@ func TestCaptureStateMsgUpdatesFields(t *testing.T) {
 }
 
 func TestCaptureStateMsgClearsLANUrlsWhenEmpty(t *testing.T) {
-	d := NewDashboard("v0", 5001, true, []string{"192.168.1.42"}, nil)
+	d := NewDashboard("v0", "localhost", 5001, true, []string{"192.168.1.42"}, nil)
 	if d.lanServerURL == "" {
 		t.Fatal("expected non-empty lanServerURL after init with LAN address")
 	}
@@ -78,3 +85,306 @@ func TestFormatCaptureLine(t *testing.T) {
 		})
 	}
 }
+
+func TestNewDashboardSingleAddressBind(t *testing.T) {
+	d := NewDashboard("v0", "192.168.1.42", 8080, false, []string{"192.168.1.42"}, nil)
+	if d.serverURL != "http://192.168.1.42:8080" {
+		t.Errorf("serverURL=%q, want the bound address", d.serverURL)
+	}
+	if d.lanServerURL != "" {
+		t.Errorf("lanServerURL=%q, want empty when it duplicates serverURL", d.lanServerURL)
+	}
+}
+
+func TestNewDashboardIPv6Bind(t *testing.T) {
+	d := NewDashboard("v0", "2001:db8::5", 8080, false, []string{"fd00::7"}, nil)
+	if d.serverURL != "http://[2001:db8::5]:8080" || d.wsURL != "ws://[2001:db8::5]:8080/ws" {
+		t.Errorf("serverURL=%q wsURL=%q, want the IPv6 host in brackets", d.serverURL, d.wsURL)
+	}
+	if d.lanServerURL != "http://[fd00::7]:8080" {
+		t.Errorf("lanServerURL=%q, want the IPv6 host in brackets", d.lanServerURL)
+	}
+}
+
+func TestCodesTab(t *testing.T) {
+	d := NewDashboard("v0", "localhost", 5001, false, nil, nil)
+	d.height = 40
+	updated, _ := d.Update(CodesMsg{Codes: []CodeStat{
+		{Kind: "event", Code: 3, Name: "Move", Count: 1200, Rate: 42.5, LastSeen: time.Now()},
+		{Kind: "event", Code: 9999, Count: 1, LastSeen: time.Now()},
+	}})
+	d = updated.(Dashboard)
+	for range 3 {
+		updated, _ = d.Update(tea.KeyMsg{Type: tea.KeyTab})
+		d = updated.(Dashboard)
+	}
+	if d.currentTab != TabCodes {
+		t.Fatalf("tab cycled to %d, want the Codes tab", d.currentTab)
+	}
+
+	out := d.renderCodesView()
+	for _, want := range []string{"2 codes seen", "Move", "42.5", "1,200", "(unknown)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("codes view missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestInspectorTab(t *testing.T) {
+	d := NewDashboard("v0", "localhost", 5001, false, nil, nil)
+	d.height = 40
+	send := func(msg tea.Msg) {
+		t.Helper()
+		updated, _ := d.Update(msg)
+		d = updated.(Dashboard)
+	}
+	key := func(s string) {
+		t.Helper()
+		if s == "enter" {
+			send(tea.KeyMsg{Type: tea.KeyEnter})
+			return
+		}
+		send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)})
+	}
+
+	now := time.Now()
+	send(InspectorMsg{Messages: []InspectedMessage{
+		{Time: now, Kind: "event", Code: 3, Name: "Move", Params: map[byte]any{0: int32(42), 1: []byte{1, 2}, 252: int16(3)}},
+		{Time: now, Kind: "request", Code: 21, Name: "Move", Params: map[byte]any{253: int16(21)}},
+		{Time: now, Kind: "event", Code: 29, Name: "NewCharacter", Params: map[byte]any{
+			1: "Someone",
+			8: []any{"a", map[string]any{"x": float32(1.5)}},
+		}},
+	}})
+	key("5")
+	if d.currentTab != TabInspector {
+		t.Fatalf("tab = %d, want the Inspector tab", d.currentTab)
+	}
+
+	out := d.inspector.render(120, 30)
+	for _, want := range []string{"3 messages", "Move", "NewCharacter", "0=42 1=[]uint8(2)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("list missing %q:\n%s", want, out)
+		}
+	}
+
+	key("f") // events only
+	if got := len(d.inspector.filtered()); got != 2 {
+		t.Errorf("kind filter kept %d messages, want 2", got)
+	}
+	key("/")
+	for _, r := range "newchar" {
+		key(string(r))
+	}
+	key("enter")
+	if got := d.inspector.filtered(); len(got) != 1 || got[0].Code != 29 {
+		t.Fatalf("name filter kept %+v, want NewCharacter only", got)
+	}
+
+	key("enter") // expand
+	out = d.inspector.render(120, 30)
+	for _, want := range []string{"event 29 NewCharacter", `"Someone"`, "[]interface {} (2)", "[x]", "float32", "1.5"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("parameter tree missing %q:\n%s", want, out)
+		}
+	}
+
+	key("p")
+	send(InspectorMsg{Messages: []InspectedMessage{{Kind: "event", Code: 29, Name: "NewCharacter"}}})
+	if len(d.inspector.msgs) != 3 || d.inspector.skipped != 1 {
+		t.Errorf("paused inspector stored %d messages, skipped %d; want 3 and 1", len(d.inspector.msgs), d.inspector.skipped)
+	}
+	if d.currentTab != TabInspector {
+		t.Error("inspector keys must not switch tabs or reach the logs view")
+	}
+}
+
+func TestConfigTabControls(t *testing.T) {
+	var selected []string
+	var recording []bool
+	d := NewDashboard("v0", "localhost", 5001, false, nil, nil).WithControls(Controls{
+		SelectInterfaces: func(names []string) error { selected = names; return nil },
+		SetRecording:     func(on bool) error { recording = append(recording, on); return errors.New("no space") },
+		SaveBuffer:       func() (string, error) { return "Packet buffer saved to 2 file(s)", nil },
+	})
+	send := func(msg tea.Msg) tea.Cmd {
+		t.Helper()
+		updated, cmd := d.Update(msg)
+		d = updated.(Dashboard)
+		return cmd
+	}
+	run := func(cmd tea.Cmd) {
+		t.Helper()
+		if cmd == nil {
+			t.Fatal("expected a command")
+		}
+		send(cmd())
+	}
+
+	send(tea.WindowSizeMsg{Width: 120, Height: 40})
+	send(CaptureStateMsg{Status: "awaiting_interfaces", Interfaces: []InterfaceOption{
+		{Name: "eth0", Address: "192.168.1.10", Category: "ethernet"},
+		{Name: "wlan0", Address: "192.168.1.42", Category: "wifi", Active: false},
+	}})
+	if !strings.Contains(d.View(), "No interface is capturing") {
+		t.Error("awaiting state must show a banner")
+	}
+	if d.viewport.Height != 40-headerHeight-footerHeight-2-1 {
+		t.Errorf("viewport height %d does not leave room for the banner", d.viewport.Height)
+	}
+
+	send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("3")})
+	send(tea.KeyMsg{Type: tea.KeyDown})
+	run(send(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")}))
+	if len(selected) != 1 || selected[0] != "wlan0" {
+		t.Errorf("selected %v, want [wlan0]", selected)
+	}
+	if d.controlNote != "Capture started on wlan0" {
+		t.Errorf("note %q", d.controlNote)
+	}
+
+	run(send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("w")}))
+	if len(recording) != 1 || !recording[0] {
+		t.Errorf("recording calls %v, want [true]", recording)
+	}
+	if !d.controlErr || !strings.Contains(d.controlNote, "no space") {
+		t.Errorf("failed recording should be reported, got %q", d.controlNote)
+	}
+
+	run(send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")}))
+	if d.controlErr || d.controlNote != "Packet buffer saved to 2 file(s)" {
+		t.Errorf("buffer save note %q", d.controlNote)
+	}
+
+	send(CaptureStateMsg{Status: "running", Interfaces: []InterfaceOption{{Name: "wlan0", Active: true}}})
+	if strings.Contains(d.View(), "No interface is capturing") || d.recording {
+		t.Error("running state clears the banner and the poll corrects the recording flag")
+	}
+}
+
+func TestNearbyTab(t *testing.T) {
+	d := NewDashboard("v0", "localhost", 5001, false, nil, nil)
+	key := func(s string) {
+		t.Helper()
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+		if s == "enter" {
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		}
+		updated, _ := d.Update(msg)
+		d = updated.(Dashboard)
+	}
+	now := time.Now()
+	updated, _ := d.Update(EntitiesMsg{Cluster: "3004", X: 0, Y: 0, Entities: []Entity{
+		{ID: 1, Kind: "resource", Name: "Fiber", Tier: 6, Enchant: 2, Size: 5, X: 30, Y: 40, HasPos: true, LastSeen: now},
+		{ID: 2, Kind: "mob", TypeID: 412, Tier: 0, X: 3, Y: 4, HasPos: true, LastSeen: now},
+		{ID: 3, Kind: "player", Name: "Someone", Guild: "Blue", Alliance: "ALLY", LastSeen: now},
+		{ID: 4, Kind: "resource", Name: "Ore", Tier: 8, Size: 1, X: 100, HasPos: true, LastSeen: now.Add(time.Second)},
+	}})
+	d = updated.(Dashboard)
+	key("6")
+	if d.currentTab != TabNearby {
+		t.Fatalf("tab = %d, want the Nearby tab", d.currentTab)
+	}
+
+	ids := func() []int64 {
+		var out []int64
+		for _, e := range d.nearby.visible() {
+			out = append(out, e.ID)
+		}
+		return out
+	}
+	if got := ids(); !slices.Equal(got, []int64{2, 1, 4, 3}) {
+		t.Errorf("by distance: %v, players without a position last", got)
+	}
+	key("s")
+	if got := ids(); !slices.Equal(got, []int64{4, 1, 2, 3}) {
+		t.Errorf("by tier: %v", got)
+	}
+	key("f")
+	key("f") // resources
+	if got := ids(); !slices.Equal(got, []int64{4, 1}) {
+		t.Errorf("resources only: %v", got)
+	}
+
+	out := d.nearby.render(30)
+	for _, want := range []string{"3004", "resources 2", "players 1", "T6.2", "50m"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("table missing %q:\n%s", want, out)
+		}
+	}
+
+	key("esc")
+	key("/")
+	for _, r := range "ally" {
+		key(string(r))
+	}
+	key("enter")
+	if got := ids(); !slices.Equal(got, []int64{3}) {
+		t.Errorf("search on alliance: %v", got)
+	}
+}
+
+func TestFormatGameServerLine(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	if got := formatGameServerLine(nil, now); got != "Game: not connected" {
+		t.Errorf("nil: got %q", got)
+	}
+	g := &GameServer{Address: "5.188.125.10:5056", Ping: 42500 * time.Microsecond, LastPacket: now.Add(-2500 * time.Millisecond)}
+	if got, want := formatGameServerLine(g, now), "Game: 5.188.125.10:5056  ping 42 ms  last packet 2s ago"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	g.Ping = 0
+	if got := formatGameServerLine(g, now); strings.Contains(got, "ping") {
+		t.Errorf("no RTT sample yet: got %q", got)
+	}
+}
+
+func TestConfigTab_ListsGameServersUnderTheInterface(t *testing.T) {
+	d := NewDashboard("v0", "localhost", 5001, true, nil, nil)
+	d.interfaces = []InterfaceOption{{
+		Name:   "eth0",
+		Active: true,
+		Endpoints: []GameEndpoint{
+			{Address: "193.169.238.10:5056", Region: "Europe"},
+			{Address: "45.9.1.2:5056"},
+		},
+	}}
+	out := strings.Join(d.renderInterfaceLines(), "\n")
+	for _, want := range []string{"193.169.238.10:5056", "Europe", "45.9.1.2:5056", "unknown region"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Config tab lacks %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestConfigTab_ListsGameClients(t *testing.T) {
+	d := NewDashboard("v0", "localhost", 5001, true, nil, nil)
+	d.width, d.height = 160, 40
+	d.instances = []ClientInstance{
+		{Port: 50000, Cluster: "4000", Server: "5.188.125.10:5056", Primary: true, LastSeen: time.Now()},
+		{Port: 50001, LastSeen: time.Now()},
+	}
+	out := d.renderConfigView()
+	for _, want := range []string{"Game clients", ":50000", "4000", "5.188.125.10:5056", ":50001", "not connected", "Nearby"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Config tab lacks %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestTruncateCutsOnCharacters(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		n    int
+		want string
+	}{
+		{"Fireball", 12, "Fireball"},
+		{"Élodie-Sœurette", 8, "Élodie-…"},
+		{"炎の剣士たち", 7, "炎の剣…"},
+	} {
+		got := truncate(tc.in, tc.n)
+		if got != tc.want || !utf8.ValidString(got) {
+			t.Errorf("truncate(%q, %d) = %q, want %q", tc.in, tc.n, got, tc.want)
+		}
+	}
+}
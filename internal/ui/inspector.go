@@ -0,0 +1,423 @@
+package ui
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	maxInspected     = 500 // messages kept by the Inspector tab
+	maxTreeItems     = 64  // slice/map entries shown per level in the parameter tree
+	maxTreeBytes     = 32  // bytes shown in a []byte hex dump
+	inspectorColumns = 50  // width of the time/kind/code/name columns
+)
+
+// InspectedMessage is a decoded Photon message as shown by the Inspector tab.
+// Params is the message's parameter map after post-processing; the tab only
+// reads it.
+type InspectedMessage struct {
+	Time         time.Time
+	Kind         string // "event", "request" or "response"
+	Code         int    // Albion code, parameter 252 for events, 253 otherwise
+	Name         string // empty when the code is missing from the generated enums
+	ReturnCode   int16  // responses only
+	DebugMessage string // responses only
+	Params       map[byte]any
+
+	seq uint64
+}
+
+// InspectorMsg carries the messages decoded since the previous one. Dropped
+// counts messages the sender discarded because the TUI fell behind.
+type InspectorMsg struct {
+	Messages []InspectedMessage
+	Dropped  uint64
+}
+
+var inspectorKinds = []string{"", "event", "request", "response"}
+
+// inspector is the Inspector tab state. The list follows the newest message
+// until the selection is moved; enter expands the selected message.
+type inspector struct {
+	msgs    []InspectedMessage
+	nextSeq uint64
+	paused  bool
+	skipped uint64 // arrived while paused
+	dropped uint64 // discarded by the sender
+
+	kind      int    // index into inspectorKinds
+	codeQuery string // code number, or part of the name
+	filtering bool
+	input     textinput.Model
+
+	selected     uint64 // seq of the selected message, 0 when following
+	expanded     bool
+	detailScroll int
+}
+
+func newInspector() inspector {
+	ti := textinput.New()
+	ti.Placeholder = "Code or name..."
+	ti.CharLimit = 40
+	return inspector{input: ti}
+}
+
+func (in *inspector) add(msg InspectorMsg) {
+	in.dropped += msg.Dropped
+	if in.paused {
+		in.skipped += uint64(len(msg.Messages)) + msg.Dropped
+		return
+	}
+	for _, m := range msg.Messages {
+		in.nextSeq++
+		m.seq = in.nextSeq
+		in.msgs = append(in.msgs, m)
+	}
+	if len(in.msgs) > maxInspected {
+		in.msgs = in.msgs[len(in.msgs)-maxInspected:]
+	}
+}
+
+func (in *inspector) match(m *InspectedMessage) bool {
+	if k := inspectorKinds[in.kind]; k != "" && m.Kind != k {
+		return false
+	}
+	if in.codeQuery == "" {
+		return true
+	}
+	if code, err := strconv.Atoi(in.codeQuery); err == nil {
+		return m.Code == code
+	}
+	return strings.Contains(strings.ToLower(m.Name), strings.ToLower(in.codeQuery))
+}
+
+func (in *inspector) filtered() []InspectedMessage {
+	if in.kind == 0 && in.codeQuery == "" {
+		return in.msgs
+	}
+	out := make([]InspectedMessage, 0, len(in.msgs))
+	for i := range in.msgs {
+		if in.match(&in.msgs[i]) {
+			out = append(out, in.msgs[i])
+		}
+	}
+	return out
+}
+
+// selectedIndex returns the index of the selected message in list, the last
+// one when following or when the selection scrolled out.
+func (in *inspector) selectedIndex(list []InspectedMessage) int {
+	if in.selected != 0 {
+		if i := slices.IndexFunc(list, func(m InspectedMessage) bool { return m.seq == in.selected }); i >= 0 {
+			return i
+		}
+	}
+	return len(list) - 1
+}
+
+func (in *inspector) move(delta int) {
+	list := in.filtered()
+	if len(list) == 0 {
+		return
+	}
+	i := min(max(in.selectedIndex(list)+delta, 0), len(list)-1)
+	if i == len(list)-1 && delta > 0 {
+		in.selected = 0
+		return
+	}
+	in.selected = list[i].seq
+}
+
+// handleKey applies an Inspector tab key. It reports false for keys the tab
+// does not use, which then get their dashboard-wide meaning.
+func (in *inspector) handleKey(msg tea.KeyMsg) (bool, tea.Cmd) {
+	if in.filtering {
+		switch msg.String() {
+		case "enter":
+			in.codeQuery = strings.TrimSpace(in.input.Value())
+			in.filtering = false
+		case "esc":
+			in.filtering = false
+			in.input.SetValue(in.codeQuery)
+		default:
+			var cmd tea.Cmd
+			in.input, cmd = in.input.Update(msg)
+			return true, cmd
+		}
+		return true, nil
+	}
+
+	if in.expanded {
+		switch msg.String() {
+		case "enter", " ", "esc":
+			in.expanded = false
+		case "up", "k":
+			in.detailScroll = max(in.detailScroll-1, 0)
+		case "down", "j":
+			in.detailScroll = min(in.detailScroll+1, in.detailLines()-1)
+		case "pgup":
+			in.detailScroll = max(in.detailScroll-10, 0)
+		case "pgdown":
+			in.detailScroll = max(min(in.detailScroll+10, in.detailLines()-1), 0)
+		case "p":
+			in.togglePause()
+		default:
+			return false, nil
+		}
+		return true, nil
+	}
+
+	switch msg.String() {
+	case "p":
+		in.togglePause()
+	case "c":
+		in.msgs = nil
+		in.selected = 0
+	case "f":
+		in.kind = (in.kind + 1) % len(inspectorKinds)
+	case "/":
+		in.filtering = true
+		in.input.SetValue(in.codeQuery)
+		in.input.Focus()
+		return true, textinput.Blink
+	case "esc":
+		in.codeQuery, in.kind, in.selected = "", 0, 0
+	case "up", "k":
+		in.move(-1)
+	case "down", "j":
+		in.move(1)
+	case "pgup":
+		in.move(-10)
+	case "pgdown":
+		in.move(10)
+	case "g":
+		if list := in.filtered(); len(list) > 0 {
+			in.selected = list[0].seq
+		}
+	case "G":
+		in.selected = 0
+	case "enter", " ":
+		list := in.filtered()
+		if len(list) == 0 {
+			return true, nil
+		}
+		in.selected = list[in.selectedIndex(list)].seq
+		in.expanded = true
+		in.detailScroll = 0
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+func (in *inspector) togglePause() {
+	in.paused = !in.paused
+	if !in.paused {
+		in.skipped = 0
+	}
+}
+
+// detailLines is the length of the selected message's parameter tree.
+func (in *inspector) detailLines() int {
+	list := in.filtered()
+	if len(list) == 0 {
+		return 0
+	}
+	return len(paramTree(list[in.selectedIndex(list)].Params))
+}
+
+func (in *inspector) statusLine() string {
+	parts := []string{StatLabelStyle.Render("Kind: ")}
+	if k := inspectorKinds[in.kind]; k != "" {
+		parts[0] += LogInfoStyle.Render(k)
+	} else {
+		parts[0] += StatLabelStyle.Render("all")
+	}
+	if in.codeQuery != "" {
+		parts = append(parts, URLStyle.Render("Code: "+in.codeQuery))
+	}
+	if in.paused {
+		parts = append(parts, ModeStyle.Render(fmt.Sprintf("PAUSED (%s skipped)", formatNumber(in.skipped))))
+	} else if in.selected == 0 {
+		parts = append(parts, StatLabelStyle.Render("following"))
+	}
+	if in.dropped > 0 {
+		parts = append(parts, LogWarnStyle.Render(fmt.Sprintf("%s dropped", formatNumber(in.dropped))))
+	}
+	return strings.Join(parts, " | ")
+}
+
+// render draws the message list, or the parameter tree of the selected
+// message when expanded, in width x rows cells.
+func (in *inspector) render(width, rows int) string {
+	list := in.filtered()
+	if len(list) == 0 {
+		if in.kind != 0 || in.codeQuery != "" {
+			return TimestampStyle.Render(fmt.Sprintf("  No messages matching the filter (%d buffered)", len(in.msgs)))
+		}
+		return TimestampStyle.Render("  Waiting for decoded messages...")
+	}
+	sel := in.selectedIndex(list)
+	if in.expanded {
+		return in.renderDetail(&list[sel], rows)
+	}
+
+	lines := []string{
+		TitleStyle.Render(fmt.Sprintf(" 🔍 %d messages (%d buffered)", len(list), len(in.msgs))),
+		StatLabelStyle.Render(fmt.Sprintf("   %-12s %-8s %5s  %-16s %s", "Time", "Kind", "Code", "Name", "Parameters")),
+	}
+	visible := max(rows-len(lines), 1)
+	start := max(min(sel-visible/2, len(list)-visible), 0)
+	end := min(start+visible, len(list))
+	summaryWidth := max(width-inspectorColumns, 10)
+	for i := start; i < end; i++ {
+		m := &list[i]
+		name, nameStyle := m.Name, StatValueStyle
+		if name == "" {
+			name, nameStyle = "(unknown)", LogWarnStyle
+		}
+		cursor := "  "
+		if i == sel {
+			cursor = TabActiveStyle.Render("▶ ")
+		}
+		lines = append(lines, fmt.Sprintf(" %s%s %-8s %5d  %s %s",
+			cursor,
+			TimestampStyle.Render(m.Time.Format("15:04:05.000")),
+			m.Kind,
+			m.Code,
+			nameStyle.Render(fmt.Sprintf("%-16s", truncate(name, 16))),
+			truncate(summarizeParams(m.Params), summaryWidth),
+		))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (in *inspector) renderDetail(m *InspectedMessage, rows int) string {
+	name := m.Name
+	if name == "" {
+		name = "(unknown)"
+	}
+	title := fmt.Sprintf(" 🔍 %s %d %s at %s, %d parameters",
+		m.Kind, m.Code, name, m.Time.Format("15:04:05.000"), len(m.Params))
+	if m.Kind == "response" {
+		title += fmt.Sprintf(", return code %d", m.ReturnCode)
+		if m.DebugMessage != "" {
+			title += ": " + m.DebugMessage
+		}
+	}
+
+	tree := paramTree(m.Params)
+	visible := max(rows-1, 1)
+	in.detailScroll = min(in.detailScroll, max(len(tree)-visible, 0))
+	end := min(in.detailScroll+visible, len(tree))
+	return strings.Join(append([]string{TitleStyle.Render(title)}, tree[in.detailScroll:end]...), "\n")
+}
+
+func paramTree(params map[byte]any) []string {
+	var tree []string
+	for _, k := range sortedKeys(params) {
+		tree = appendTree(tree, 1, fmt.Sprintf("[%d]", k), params[k])
+	}
+	return tree
+}
+
+// appendTree renders v as "label type value" and recurses into slices and
+// maps, one line per node.
+func appendTree(lines []string, depth int, label string, v any) []string {
+	indent := strings.Repeat("  ", depth)
+	if v == nil {
+		return append(lines, fmt.Sprintf("%s%s %s", indent, StatLabelStyle.Render(label), TimestampStyle.Render("nil")))
+	}
+	rv := reflect.ValueOf(v)
+	typ := StatLabelStyle.Render(rv.Type().String())
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		if b, ok := v.([]byte); ok {
+			return append(lines, fmt.Sprintf("%s%s %s (%d) %s", indent, label, typ, len(b), hexPreview(b)))
+		}
+		lines = append(lines, fmt.Sprintf("%s%s %s (%d)", indent, label, typ, rv.Len()))
+		n := min(rv.Len(), maxTreeItems)
+		for i := range n {
+			lines = appendTree(lines, depth+1, fmt.Sprintf("[%d]", i), rv.Index(i).Interface())
+		}
+		if rv.Len() > n {
+			lines = append(lines, fmt.Sprintf("%s  %s", indent, TimestampStyle.Render(fmt.Sprintf("… %d more", rv.Len()-n))))
+		}
+		return lines
+	case reflect.Map:
+		lines = append(lines, fmt.Sprintf("%s%s %s (%d)", indent, label, typ, rv.Len()))
+		keys := rv.MapKeys()
+		slices.SortFunc(keys, func(a, b reflect.Value) int {
+			return strings.Compare(fmt.Sprint(a.Interface()), fmt.Sprint(b.Interface()))
+		})
+		n := min(len(keys), maxTreeItems)
+		for _, k := range keys[:n] {
+			lines = appendTree(lines, depth+1, fmt.Sprintf("[%v]", k.Interface()), rv.MapIndex(k).Interface())
+		}
+		if len(keys) > n {
+			lines = append(lines, fmt.Sprintf("%s  %s", indent, TimestampStyle.Render(fmt.Sprintf("… %d more", len(keys)-n))))
+		}
+		return lines
+	default:
+		return append(lines, fmt.Sprintf("%s%s %s %s", indent, label, typ, StatValueStyle.Render(formatScalar(v))))
+	}
+}
+
+// summarizeParams is the one-line "key=value" form of a parameter map,
+// without the code parameters 252 and 253 that the row already shows.
+func summarizeParams(params map[byte]any) string {
+	parts := make([]string, 0, len(params))
+	for _, k := range sortedKeys(params) {
+		if k == 252 || k == 253 {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%d=%s", k, summarizeValue(params[k])))
+	}
+	return strings.Join(parts, " ")
+}
+
+func summarizeValue(v any) string {
+	if v == nil {
+		return "nil"
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		return fmt.Sprintf("%s(%d)", rv.Type().String(), rv.Len())
+	case reflect.Map:
+		return fmt.Sprintf("map(%d)", rv.Len())
+	default:
+		return truncate(formatScalar(v), 24)
+	}
+}
+
+func formatScalar(v any) string {
+	if s, ok := v.(string); ok {
+		return strconv.Quote(s)
+	}
+	return fmt.Sprint(v)
+}
+
+func hexPreview(b []byte) string {
+	s := fmt.Sprintf("% x", b[:min(len(b), maxTreeBytes)])
+	if len(b) > maxTreeBytes {
+		s += " …"
+	}
+	return s
+}
+
+func sortedKeys(params map[byte]any) []byte {
+	keys := make([]byte, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,261 @@
+package ui
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Entity mirrors internal/photon/entities.Entity.
+type Entity struct {
+	ID       int64
+	Kind     string
+	Name     string
+	TypeID   int
+	Tier     int
+	Enchant  int
+	Size     int
+	Guild    string
+	Alliance string
+	X, Y     float32
+	HasPos   bool
+	LastSeen time.Time
+}
+
+// EntitiesMsg carries what the current cluster has announced. X, Y is the
+// local player, used for distances.
+type EntitiesMsg struct {
+	Cluster  string
+	X, Y     float32
+	Entities []Entity
+}
+
+var (
+	nearbyKinds = []string{"", "mob", "resource", "chest", "dungeon", "player"}
+	nearbySorts = []string{"distance", "tier", "kind", "name", "recent"}
+)
+
+// nearby is the Nearby tab state: the latest EntitiesMsg plus the kind
+// filter (f), sort order (s), search (/) and scroll position.
+type nearby struct {
+	msg EntitiesMsg
+
+	kind      int // index into nearbyKinds
+	sort      int // index into nearbySorts
+	query     string
+	searching bool
+	input     textinput.Model
+	scroll    int
+}
+
+func newNearby() nearby {
+	ti := textinput.New()
+	ti.Placeholder = "Name, guild or alliance..."
+	ti.CharLimit = 40
+	return nearby{input: ti}
+}
+
+func (n *nearby) distance(e *Entity) float64 {
+	if !e.HasPos {
+		return math.Inf(1)
+	}
+	return math.Hypot(float64(e.X-n.msg.X), float64(e.Y-n.msg.Y))
+}
+
+func (n *nearby) visible() []Entity {
+	q := strings.ToLower(n.query)
+	out := make([]Entity, 0, len(n.msg.Entities))
+	for _, e := range n.msg.Entities {
+		if k := nearbyKinds[n.kind]; k != "" && e.Kind != k {
+			continue
+		}
+		if q != "" && !strings.Contains(strings.ToLower(e.Name), q) &&
+			!strings.Contains(strings.ToLower(e.Guild), q) &&
+			!strings.Contains(strings.ToLower(e.Alliance), q) {
+			continue
+		}
+		out = append(out, e)
+	}
+
+	var order func(a, b Entity) int
+	switch nearbySorts[n.sort] {
+	case "distance":
+		order = func(a, b Entity) int { return cmp.Compare(n.distance(&a), n.distance(&b)) }
+	case "tier":
+		order = func(a, b Entity) int {
+			return cmp.Or(cmp.Compare(b.Tier, a.Tier), cmp.Compare(b.Enchant, a.Enchant))
+		}
+	case "kind":
+		order = func(a, b Entity) int { return cmp.Compare(a.Kind, b.Kind) }
+	case "name":
+		order = func(a, b Entity) int { return cmp.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)) }
+	case "recent":
+		order = func(a, b Entity) int { return b.LastSeen.Compare(a.LastSeen) }
+	}
+	slices.SortStableFunc(out, func(a, b Entity) int {
+		return cmp.Or(order(a, b), cmp.Compare(a.ID, b.ID))
+	})
+	return out
+}
+
+// handleKey applies a Nearby tab key, reporting false for keys it leaves to
+// the dashboard.
+func (n *nearby) handleKey(msg tea.KeyMsg) (bool, tea.Cmd) {
+	if n.searching {
+		switch msg.String() {
+		case "enter":
+			n.query = strings.TrimSpace(n.input.Value())
+			n.searching = false
+			n.scroll = 0
+		case "esc":
+			n.searching = false
+			n.input.SetValue(n.query)
+		default:
+			var cmd tea.Cmd
+			n.input, cmd = n.input.Update(msg)
+			return true, cmd
+		}
+		return true, nil
+	}
+
+	switch msg.String() {
+	case "f":
+		n.kind = (n.kind + 1) % len(nearbyKinds)
+		n.scroll = 0
+	case "s":
+		n.sort = (n.sort + 1) % len(nearbySorts)
+	case "/":
+		n.searching = true
+		n.input.SetValue(n.query)
+		n.input.Focus()
+		return true, textinput.Blink
+	case "esc":
+		n.kind, n.query, n.scroll = 0, "", 0
+	case "up", "k":
+		n.scroll = max(n.scroll-1, 0)
+	case "down", "j":
+		n.scroll = min(n.scroll+1, max(len(n.visible())-1, 0))
+	case "pgup":
+		n.scroll = max(n.scroll-10, 0)
+	case "pgdown":
+		n.scroll = min(n.scroll+10, max(len(n.visible())-1, 0))
+	case "g":
+		n.scroll = 0
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+func (n *nearby) statusLine() string {
+	kind := StatLabelStyle.Render("all")
+	if k := nearbyKinds[n.kind]; k != "" {
+		kind = LogInfoStyle.Render(k)
+	}
+	parts := []string{
+		StatLabelStyle.Render("Kind: ") + kind,
+		StatLabelStyle.Render("Sort: ") + LogInfoStyle.Render(nearbySorts[n.sort]),
+	}
+	if n.query != "" {
+		parts = append(parts, URLStyle.Render("Search: "+n.query))
+	}
+	return strings.Join(parts, " | ")
+}
+
+// render draws the entity table in rows lines.
+func (n *nearby) render(rows int) string {
+	counts := make(map[string]int)
+	for _, e := range n.msg.Entities {
+		counts[e.Kind]++
+	}
+	cluster := n.msg.Cluster
+	if cluster == "" {
+		cluster = "unknown cluster"
+	}
+	summary := make([]string, 0, len(nearbyKinds)-1)
+	for _, k := range nearbyKinds[1:] {
+		summary = append(summary, fmt.Sprintf("%ss %d", k, counts[k]))
+	}
+	lines := []string{
+		TitleStyle.Render(fmt.Sprintf(" 👁 %s: %s", cluster, strings.Join(summary, ", "))),
+		StatLabelStyle.Render(fmt.Sprintf(" %-9s %-28s %-6s %5s %7s  %-24s %s", "Kind", "Name", "Tier", "Size", "Dist", "Guild / type", "Seen")),
+	}
+
+	list := n.visible()
+	if len(list) == 0 {
+		if len(n.msg.Entities) > 0 {
+			return strings.Join(append(lines, TimestampStyle.Render("  Nothing matches the filter")), "\n")
+		}
+		return strings.Join(append(lines, TimestampStyle.Render("  Nothing announced yet; entities appear as the game spawns them")), "\n")
+	}
+
+	visible := max(rows-len(lines)-1, 1) // keep a line for the position
+	n.scroll = min(n.scroll, max(len(list)-visible, 0))
+	now := time.Now()
+	for _, e := range list[n.scroll:min(n.scroll+visible, len(list))] {
+		name, nameStyle := e.Name, StatValueStyle
+		if name == "" {
+			name, nameStyle = "(unnamed)", StatLabelStyle
+		}
+		tier := "-"
+		if e.Tier > 0 {
+			tier = fmt.Sprintf("T%d.%d", e.Tier, e.Enchant)
+		} else if e.Enchant > 0 {
+			tier = fmt.Sprintf(".%d", e.Enchant)
+		}
+		size := ""
+		if e.Kind == "resource" {
+			size = fmt.Sprint(e.Size)
+		}
+		dist := "-"
+		if d := n.distance(&e); !math.IsInf(d, 1) {
+			dist = fmt.Sprintf("%.0fm", d)
+		}
+		info := e.Guild
+		if e.Alliance != "" {
+			info = fmt.Sprintf("%s [%s]", info, e.Alliance)
+		}
+		if e.Kind == "mob" || e.Kind == "resource" {
+			info = fmt.Sprintf("type %d", e.TypeID)
+		}
+		lines = append(lines, fmt.Sprintf(" %s %s %s %5s %7s  %-24s %s",
+			kindStyle(e.Kind).Render(fmt.Sprintf("%-9s", e.Kind)),
+			nameStyle.Render(fmt.Sprintf("%-28s", truncate(name, 28))),
+			enchantStyle(e.Enchant).Render(fmt.Sprintf("%-6s", tier)),
+			size,
+			dist,
+			truncate(info, 24),
+			TimestampStyle.Render(formatDuration(now.Sub(e.LastSeen).Round(time.Second))+" ago"),
+		))
+	}
+	if len(list) > visible {
+		lines = append(lines, TimestampStyle.Render(fmt.Sprintf("  %d-%d of %d", n.scroll+1, min(n.scroll+visible, len(list)), len(list))))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func kindStyle(kind string) lipgloss.Style {
+	switch kind {
+	case "player":
+		return LogErrorStyle
+	case "mob":
+		return LogWarnStyle
+	case "resource":
+		return LogSuccessStyle
+	}
+	return LogInfoStyle
+}
+
+func enchantStyle(enchant int) lipgloss.Style {
+	if enchant > 0 {
+		return ModeStyle
+	}
+	return StatValueStyle
+}
@@ -88,6 +88,12 @@ var (
 			Underline(true)
 
 	// Status indicator styles
+	BannerStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("#1a1a2e")).
+			Background(ColorWarning).
+			Padding(0, 1)
+
 	StatusOnStyle = lipgloss.NewStyle().
 			Foreground(ColorSuccess)
 
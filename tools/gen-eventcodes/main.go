@@ -109,6 +109,22 @@ func generate(root string, s spec) error {
 		fmt.Fprintf(&out, "\t%s = %d\n", e.Name, e.Value)
 	}
 	fmt.Fprintln(&out, ")")
+	fmt.Fprintln(&out)
+	fmt.Fprintln(&out, "// names keeps the first JS name for each code.")
+	fmt.Fprintln(&out, "var names = map[int]string{")
+	seen := make(map[int]bool, len(entries))
+	for _, e := range entries {
+		if seen[e.Value] {
+			continue
+		}
+		seen[e.Value] = true
+		fmt.Fprintf(&out, "\t%s: %q,\n", e.Name, e.Name)
+	}
+	fmt.Fprintln(&out, "}")
+	fmt.Fprintln(&out)
+	fmt.Fprintln(&out, "// Name returns the JS enum name for an Albion code, or \"\" when the code is")
+	fmt.Fprintln(&out, "// not in the enum.")
+	fmt.Fprintln(&out, "func Name(code int) string { return names[code] }")
 
 	targetPath := filepath.Join(root, filepath.FromSlash(s.Target))
 	if err := os.MkdirAll(filepath.Dir(targetPath), 0o755); err != nil {
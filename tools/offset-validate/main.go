@@ -1,5 +1,6 @@
 // offset-validate dumps every NewMob event's (typeId, HP, AP, movespeed) tuple
-// from one or more pcap files. Diagnostic-only; prints JSONL to stdout.
+// from one or more captures (files, directories or globs). Diagnostic-only;
+// prints JSONL to stdout and per-file stats to stderr.
 package main
 
 import (
@@ -7,19 +8,26 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/nospy/albion-openradar/internal/pcapiter"
 	"github.com/nospy/albion-openradar/internal/photon"
 	"github.com/nospy/albion-openradar/internal/photon/eventcodes"
 )
 
 func main() {
 	if len(os.Args) < 2 {
-		fmt.Fprintln(os.Stderr, "usage: offset-validate <pcap> [<pcap>...]")
+		fmt.Fprintln(os.Stderr, "usage: offset-validate <pcap|dir|glob> [...]")
+		os.Exit(2)
+	}
+	paths, err := pcapiter.Expand(os.Args[1:]...)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
 		os.Exit(2)
 	}
 	enc := json.NewEncoder(os.Stdout)
 	totalEvents := 0
 	totalNewMob := 0
-	for _, path := range os.Args[1:] {
+	var stats []pcapiter.Stats
+	for _, path := range paths {
 		source := path
 		parser := photon.NewPhotonParser(
 			func(ev *photon.EventData) {
@@ -42,9 +50,15 @@ func main() {
 			},
 			nil, nil,
 		)
-		if err := iterate(path, parser.ReceivePacket); err != nil {
-			fmt.Fprintf(os.Stderr, "pcap %s: %v\n", path, err)
+		st, err := pcapiter.File(path, func(p pcapiter.Packet) error {
+			parser.ReceivePacket(p.Payload)
+			return nil
+		})
+		stats = append(stats, st)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "pcap %v\n", err)
 		}
 	}
+	pcapiter.ReportStats(os.Stderr, stats)
 	fmt.Fprintf(os.Stderr, "totalEvents=%d totalNewMob=%d\n", totalEvents, totalNewMob)
 }
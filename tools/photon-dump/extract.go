@@ -2,8 +2,10 @@ package main
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 
+	"github.com/nospy/albion-openradar/internal/pcapiter"
 	"github.com/nospy/albion-openradar/internal/photon"
 )
 
@@ -67,11 +69,13 @@ func runExtract(in, outGo, outJS string, scenarios []Scenario) error {
 		},
 	)
 
-	if err := iteratePcap(in, func(payload []byte) error {
-		currentRaw = payload
-		parser.ReceivePacket(payload)
+	stats, err := pcapiter.Walk([]string{in}, func(p pcapiter.Packet) error {
+		currentRaw = p.Payload
+		parser.ReceivePacket(p.Payload)
 		return nil
-	}); err != nil {
+	})
+	pcapiter.ReportStats(os.Stderr, stats)
+	if err != nil {
 		return err
 	}
 
@@ -6,6 +6,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/nospy/albion-openradar/internal/pcapiter"
 	"github.com/nospy/albion-openradar/internal/photon"
 )
 
@@ -30,10 +31,12 @@ func runInventory(in, outPath string) error {
 		},
 	)
 
-	if err := iteratePcap(in, func(payload []byte) error {
-		parser.ReceivePacket(payload)
+	stats, err := pcapiter.Walk([]string{in}, func(p pcapiter.Packet) error {
+		parser.ReceivePacket(p.Payload)
 		return nil
-	}); err != nil {
+	})
+	pcapiter.ReportStats(os.Stderr, stats)
+	if err != nil {
 		return err
 	}
 
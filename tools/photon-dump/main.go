@@ -19,7 +19,7 @@ import (
 
 func main() {
 	var (
-		in        = flag.String("in", "", "input anonymized pcap")
+		in        = flag.String("in", "", "input anonymized capture: pcap or pcapng file, directory or glob")
 		outGo     = flag.String("out-go", "", "output dir for .pcap fragments")
 		outJS     = flag.String("out-js", "", "output dir for .json WS-level fixtures")
 		inventory = flag.String("inventory", "", "if set, write census markdown to this path and skip extraction")
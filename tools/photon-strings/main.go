@@ -2,7 +2,7 @@
 // and parameter index. Use it to tell game identifiers apart from text a
 // player typed, and to derive the identity field table anonymize-pcap uses.
 //
-// Usage: go run ./tools/photon-strings <file.pcap>...
+// Usage: go run ./tools/photon-strings <file.pcap|dir|glob>...
 package main
 
 import (
@@ -11,15 +11,21 @@ import (
 	"os"
 	"sort"
 
+	"github.com/nospy/albion-openradar/internal/pcapiter"
 	"github.com/nospy/albion-openradar/internal/photonscan"
 )
 
 func main() {
 	if len(os.Args) < 2 {
-		fmt.Fprintln(os.Stderr, "usage: photon-strings <file.pcap>...")
+		fmt.Fprintln(os.Stderr, "usage: photon-strings <file.pcap|dir|glob>...")
 		os.Exit(2)
 	}
-	for _, path := range os.Args[1:] {
+	paths, err := pcapiter.Expand(os.Args[1:]...)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(2)
+	}
+	for _, path := range paths {
 		found, err := stringsIn(path)
 		if err != nil {
 			fmt.Fprintln(os.Stderr, "error:", err)
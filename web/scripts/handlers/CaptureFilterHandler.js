@@ -0,0 +1,81 @@
+// CaptureFilterHandler edits the capture filter on the settings page: the
+// game server ports and an extra BPF expression. The server compiles the
+// filter before reopening the capturers, so a typo comes back as an error
+// and capture goes on unchanged.
+export class CaptureFilterHandler {
+    constructor(container) {
+        this.container = container;
+        this.filter = null;
+    }
+
+    async load() {
+        const res = await fetch('/api/network/filter');
+        if (!res.ok) {
+            this.container.innerHTML = `<div class="alert alert-error">Failed to load the capture filter.</div>`;
+            return;
+        }
+        this.filter = await res.json();
+        this.render();
+    }
+
+    render() {
+        const ports = (this.filter?.ports ?? []).join(', ');
+        this.container.innerHTML = `
+            <h3 class="text-base font-semibold mt-6">Capture filter</h3>
+            <p class="text-sm opacity-70 mb-2">Game server UDP ports (5056 unless a tunnelling tool moves the game) and an optional BPF expression for extra traffic. Extra packets are only kept in recordings and the packet buffer.</p>
+            <div class="flex flex-col gap-2 max-w-xl">
+                <label class="flex items-center gap-2">
+                    <span class="w-28 text-sm">Game ports</span>
+                    <input type="text" class="input input-sm input-bordered flex-1 font-mono" data-filter-ports value="${escapeHTML(ports)}" placeholder="5056">
+                </label>
+                <label class="flex items-center gap-2">
+                    <span class="w-28 text-sm">Extra BPF</span>
+                    <input type="text" class="input input-sm input-bordered flex-1 font-mono" data-filter-extra value="${escapeHTML(this.filter?.extraBPF ?? '')}" placeholder="e.g. udp port 4535">
+                </label>
+                <code class="text-xs opacity-60 break-all" data-filter-expression>${escapeHTML(this.filter?.expression ?? '')}</code>
+                <div><button class="btn btn-sm btn-primary" data-action="apply-filter">Apply filter</button></div>
+            </div>
+        `;
+        this.container.querySelector('[data-action="apply-filter"]')?.addEventListener('click', () => this.apply());
+    }
+
+    // parsePorts reads "5056, 5055" into numbers; null when one is not a port.
+    parsePorts(text) {
+        const parts = text.split(/[\s,]+/).filter(Boolean);
+        const ports = parts.map(Number);
+        return ports.every(p => Number.isInteger(p) && p > 0 && p < 65536) ? ports : null;
+    }
+
+    async apply() {
+        const ports = this.parsePorts(this.container.querySelector('[data-filter-ports]').value);
+        if (!ports) {
+            window.toast?.error?.('Ports must be numbers between 1 and 65535.');
+            return;
+        }
+        const extraBPF = this.container.querySelector('[data-filter-extra]').value.trim();
+        try {
+            const res = await fetch('/api/network/filter', {
+                method: 'POST',
+                headers: {'Content-Type': 'application/json'},
+                body: JSON.stringify({ports, extraBPF}),
+            });
+            if (!res.ok) {
+                window.toast?.error?.(`Filter rejected: ${await res.text()}`);
+                return;
+            }
+            this.filter = await res.json();
+            this.render();
+            if (this.filter.reopenError) {
+                window.toast?.warning?.(`Filter saved, but the capture could not reopen: ${this.filter.reopenError}`);
+                return;
+            }
+            window.toast?.success?.('Capture filter applied.');
+        } catch (err) {
+            window.toast?.error?.(`Network error: ${err.message ?? err}`);
+        }
+    }
+}
+
+function escapeHTML(s) {
+    return String(s).replace(/[&<>"']/g, c => ({'&': '&amp;', '<': '&lt;', '>': '&gt;', '"': '&quot;', "'": '&#39;'}[c]));
+}
@@ -27,12 +27,14 @@ export class NetworkSettingsHandler {
     }
 
     render() {
-        const activeNames = new Set((this.state?.captureInterfaces ?? []).map(c => c.name));
+        const active = new Map((this.state?.captureInterfaces ?? []).map(c => [c.name, c]));
         const banner = this.renderBanner();
-        const rows = this.interfaces.map(i => this.renderRow(i, activeNames.has(i.name))).join('');
+        const rows = this.interfaces.map(i => this.renderRow(i, active.has(i.name)) + this.renderEndpoints(active.get(i.name))).join('');
+        const port = Number(this.state?.serverPort) || 5001;
+        const scheme = this.state?.serverScheme === 'https' ? 'https' : 'http';
         const lan = (this.state?.lanAddresses ?? []).map(a => {
             const safe = escapeHTML(a);
-            return `<li><a data-lan-url href="http://${safe}:5001/" target="_blank" rel="noopener noreferrer" class="link link-primary">http://${safe}:5001/</a></li>`;
+            return `<li><a data-lan-url href="${scheme}://${safe}:${port}/" target="_blank" rel="noopener noreferrer" class="link link-primary">${scheme}://${safe}:${port}/</a></li>`;
         }).join('');
         this.container.innerHTML = `
             ${banner}
@@ -46,11 +48,21 @@ export class NetworkSettingsHandler {
             </div>
             <h3 class="text-base font-semibold mt-6">LAN access</h3>
             <p class="text-sm opacity-70">Reachable from devices on the same local network. Independent of the capture interfaces above.</p>
-            <ul class="list-disc pl-5">${lan || '<li class="opacity-60">No LAN address detected.</li>'}</ul>
+            <ul class="list-disc pl-5">${lan || '<li class="opacity-60">No LAN address detected, or the radar listens on loopback only.</li>'}</ul>
+            ${this.renderTLSFingerprint()}
         `;
         this.bindEvents();
     }
 
+    renderTLSFingerprint() {
+        const fp = this.state?.tlsFingerprint;
+        if (!fp) return '';
+        return `
+            <p class="text-sm opacity-70 mt-2">HTTPS uses a self-signed certificate. Before accepting the browser warning, check that its SHA-256 fingerprint matches:</p>
+            <code class="text-xs break-all" data-tls-fingerprint>${escapeHTML(fp)}</code>
+        `;
+    }
+
     renderExitLagNotice() {
         return `
             <div class="alert alert-info mb-2" data-exitlag-notice>
@@ -85,11 +97,32 @@ export class NetworkSettingsHandler {
                 <input type="checkbox" class="checkbox checkbox-sm" ${checked ? 'checked' : ''} ${iface.isAvailable ? '' : 'disabled'}>
                 <span class="badge badge-outline">${badge} ${label}</span>
                 <span class="flex-1">${escapeHTML(iface.description || iface.name)}${unavail}</span>
-                <span class="opacity-60 text-sm">${escapeHTML(iface.address || '')}</span>
+                <span class="opacity-60 text-sm font-mono text-right" data-iface-addresses>${this.renderAddresses(iface)}</span>
             </label>
         `;
     }
 
+    // renderAddresses shows every address of an interface, IPv6 included,
+    // the primary one first; older servers only send address.
+    renderAddresses(iface) {
+        const addrs = iface.addresses?.length ? iface.addresses : [iface.address].filter(Boolean);
+        return addrs.map(a => escapeHTML(a)).join('<br>');
+    }
+
+    // renderEndpoints lists the game servers an active interface talks to,
+    // with their region. An unknown region is usually a proxy like ExitLag.
+    renderEndpoints(capture) {
+        const endpoints = (capture?.endpoints ?? []).slice(0, 3);
+        if (endpoints.length === 0) return '';
+        const items = endpoints.map(e => {
+            const region = e.region
+                ? `<span class="badge badge-sm badge-ghost">${escapeHTML(e.region)}</span>`
+                : '<span class="badge badge-sm badge-warning" title="Not in regions.json: a proxy such as ExitLag, or a new server range">unknown region</span>';
+            return `<li class="flex items-center gap-2"><span class="font-mono">${escapeHTML(e.address)}</span>${region}</li>`;
+        }).join('');
+        return `<ul class="text-xs opacity-70 pl-12 -mt-1 mb-1 flex flex-col gap-1" data-endpoints="${escapeHTML(capture.name)}">${items}</ul>`;
+    }
+
     bindEvents() {
         for (const cb of this.container.querySelectorAll('[data-iface] input')) {
             cb.addEventListener('change', () => this.updateApplyState());
@@ -0,0 +1,98 @@
+// synthetic: inline mock fetch responses
+import {describe, test, expect, beforeEach, vi, afterEach} from 'vitest';
+
+const {CaptureFilterHandler} = await import('./CaptureFilterHandler.js');
+
+const defaults = {ports: [5056], extraBPF: '', expression: 'udp and (port 5056)'};
+
+describe('CaptureFilterHandler', () => {
+    let container;
+
+    beforeEach(() => {
+        document.body.innerHTML = '<div id="capture-filter-section"></div>';
+        container = document.getElementById('capture-filter-section');
+        globalThis.fetch = vi.fn();
+        window.toast = {success: vi.fn(), warning: vi.fn(), error: vi.fn()};
+    });
+
+    afterEach(() => {
+        document.body.innerHTML = '';
+        delete window.toast;
+        vi.restoreAllMocks();
+    });
+
+    test('shows the saved ports and the expression in force', async () => {
+        globalThis.fetch.mockResolvedValueOnce({ok: true, json: async () => defaults});
+
+        const h = new CaptureFilterHandler(container);
+        await h.load();
+
+        expect(container.querySelector('[data-filter-ports]').value).toBe('5056');
+        expect(container.querySelector('[data-filter-expression]').textContent).toBe('udp and (port 5056)');
+    });
+
+    test('apply posts the parsed ports and the extra expression', async () => {
+        globalThis.fetch
+            .mockResolvedValueOnce({ok: true, json: async () => defaults})
+            .mockResolvedValueOnce({
+                ok: true,
+                json: async () => ({ports: [5056, 5055], extraBPF: 'udp port 4535', expression: '(udp and (port 5056 or port 5055)) or (udp port 4535)'}),
+            });
+
+        const h = new CaptureFilterHandler(container);
+        await h.load();
+        container.querySelector('[data-filter-ports]').value = '5056, 5055';
+        container.querySelector('[data-filter-extra]').value = ' udp port 4535 ';
+        await h.apply();
+
+        const [url, opts] = globalThis.fetch.mock.calls[1];
+        expect(url).toBe('/api/network/filter');
+        expect(JSON.parse(opts.body)).toEqual({ports: [5056, 5055], extraBPF: 'udp port 4535'});
+        expect(container.querySelector('[data-filter-expression]').textContent).toContain('port 5055');
+        expect(window.toast.success).toHaveBeenCalled();
+    });
+
+    test('rejects a port that is not a number without calling the server', async () => {
+        globalThis.fetch.mockResolvedValueOnce({ok: true, json: async () => defaults});
+
+        const h = new CaptureFilterHandler(container);
+        await h.load();
+        container.querySelector('[data-filter-ports]').value = '5056, abc';
+        await h.apply();
+
+        expect(globalThis.fetch).toHaveBeenCalledTimes(1);
+        expect(window.toast.error).toHaveBeenCalled();
+    });
+
+    test('shows the server error when the filter does not compile', async () => {
+        globalThis.fetch
+            .mockResolvedValueOnce({ok: true, json: async () => defaults})
+            .mockResolvedValueOnce({ok: false, text: async () => 'invalid capture filter: syntax error'});
+
+        const h = new CaptureFilterHandler(container);
+        await h.load();
+        container.querySelector('[data-filter-extra]').value = 'bogus and';
+        await h.apply();
+
+        expect(window.toast.error).toHaveBeenCalledWith(expect.stringContaining('syntax error'));
+        expect(container.querySelector('[data-filter-expression]').textContent).toBe('udp and (port 5056)');
+    });
+
+    test('warns when the filter was saved but the capture did not reopen', async () => {
+        globalThis.fetch
+            .mockResolvedValueOnce({ok: true, json: async () => defaults})
+            .mockResolvedValueOnce({
+                ok: true,
+                json: async () => ({...defaults, ports: [5057], expression: 'udp and (port 5057)', reopenError: 'adapter gone'}),
+            });
+
+        const h = new CaptureFilterHandler(container);
+        await h.load();
+        container.querySelector('[data-filter-ports]').value = '5057';
+        await h.apply();
+
+        expect(window.toast.warning).toHaveBeenCalledWith(expect.stringContaining('adapter gone'));
+        expect(window.toast.success).not.toHaveBeenCalled();
+        expect(container.querySelector('[data-filter-expression]').textContent).toBe('udp and (port 5057)');
+    });
+});
@@ -98,6 +98,26 @@ describe('NetworkSettingsHandler', () => {
         expect(body.names.sort()).toEqual(['a', 'b']);
     });
 
+    test('lists every address of an interface, IPv6 included', async () => {
+        globalThis.fetch
+            .mockResolvedValueOnce({
+                ok: true,
+                json: async () => ([
+                    {name: 'wg', description: 'WireGuard Tunnel', address: 'fd00:10::2', addresses: ['fd00:10::2', 'fe80::5'], category: 'vpn', isPersisted: false, isAvailable: true},
+                    {name: 'old', description: 'Wi-Fi', address: '192.168.1.1', category: 'wifi', isPersisted: false, isAvailable: true},
+                ]),
+            })
+            .mockResolvedValueOnce({ok: true, json: async () => ({captureInterfaces: [], lanAddresses: [], status: 'awaiting_interfaces'})});
+
+        const h = new NetworkSettingsHandler(container);
+        await h.load();
+
+        const wg = container.querySelector('[data-iface="wg"] [data-iface-addresses]');
+        expect(wg.innerHTML).toBe('fd00:10::2<br>fe80::5');
+        const old = container.querySelector('[data-iface="old"] [data-iface-addresses]');
+        expect(old.textContent).toBe('192.168.1.1');
+    });
+
     test('renders LAN addresses as clickable URLs', async () => {
         globalThis.fetch
             .mockResolvedValueOnce({ok: true, json: async () => []})
@@ -115,6 +135,39 @@ describe('NetworkSettingsHandler', () => {
         expect(links[1].href).toContain('10.0.0.3');
     });
 
+    test('builds LAN URLs on the served port', async () => {
+        globalThis.fetch
+            .mockResolvedValueOnce({ok: true, json: async () => []})
+            .mockResolvedValueOnce({
+                ok: true,
+                json: async () => ({captureInterfaces: [], lanAddresses: ['192.168.1.5'], serverPort: 8080, status: 'awaiting_interfaces'}),
+            });
+
+        const h = new NetworkSettingsHandler(container);
+        await h.load();
+
+        const link = container.querySelector('[data-lan-url]');
+        expect(link.href).toBe('http://192.168.1.5:8080/');
+    });
+
+    test('uses https and shows the certificate fingerprint when TLS is on', async () => {
+        globalThis.fetch
+            .mockResolvedValueOnce({ok: true, json: async () => []})
+            .mockResolvedValueOnce({
+                ok: true,
+                json: async () => ({
+                    captureInterfaces: [], lanAddresses: ['192.168.1.5'], serverPort: 5001,
+                    serverScheme: 'https', tlsFingerprint: 'AB:CD', status: 'awaiting_interfaces',
+                }),
+            });
+
+        const h = new NetworkSettingsHandler(container);
+        await h.load();
+
+        expect(container.querySelector('[data-lan-url]').href).toBe('https://192.168.1.5:5001/');
+        expect(container.querySelector('[data-tls-fingerprint]').textContent).toBe('AB:CD');
+    });
+
     test('shows awaiting banner when capture is not running', async () => {
         globalThis.fetch
             .mockResolvedValueOnce({ok: true, json: async () => []})
@@ -238,4 +291,28 @@ describe('NetworkSettingsHandler', () => {
         expect(container.innerHTML).not.toContain('<img>');
         expect(container.textContent).toContain('alert(1)');
     });
+
+    test('lists the game servers of an active interface with their region', async () => {
+        globalThis.fetch
+            .mockResolvedValueOnce({ok: true, json: async () => [
+                {name: 'a', description: 'Wi-Fi', address: '192.168.1.1', category: 'wifi', isPersisted: true, isAvailable: true},
+            ]})
+            .mockResolvedValueOnce({ok: true, json: async () => ({
+                captureInterfaces: [{name: 'a', endpoints: [
+                    {address: '193.169.238.10:5056', region: 'Europe'},
+                    {address: '45.9.1.2:5056', region: ''},
+                ]}],
+                lanAddresses: [],
+                status: 'running',
+            })});
+
+        const h = new NetworkSettingsHandler(container);
+        await h.load();
+
+        const list = container.querySelector('[data-endpoints="a"]');
+        expect(list).toBeTruthy();
+        expect(list.textContent).toContain('193.169.238.10:5056');
+        expect(list.textContent).toContain('Europe');
+        expect(list.textContent).toContain('unknown region');
+    });
 });
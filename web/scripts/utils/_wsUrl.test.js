@@ -18,4 +18,12 @@ describe('buildWsUrl', () => {
     test('@verified 2026-04-25: no port http -> ws://host/ws', () => {
         expect(buildWsUrl({protocol: 'http:', host: 'localhost'})).toBe('ws://localhost/ws');
     });
+
+    test('@verified 2026-10-19: chosen game client -> ?instance=port', () => {
+        expect(buildWsUrl({protocol: 'http:', host: 'localhost:5001'}, '50001')).toBe('ws://localhost:5001/ws?instance=50001');
+    });
+
+    test('@verified 2026-10-19: no game client chosen -> every client', () => {
+        expect(buildWsUrl({protocol: 'http:', host: 'localhost:5001'}, '')).toBe('ws://localhost:5001/ws');
+    });
 });
@@ -1,4 +1,17 @@
-export function buildWsUrl(loc = window.location) {
+// instanceStorageKey holds the local port of the game client this browser
+// follows when several run on the radar PC (multibox).
+export const instanceStorageKey = 'radarInstance';
+
+export function storedInstance() {
+    try {
+        return globalThis.localStorage?.getItem(instanceStorageKey) || '';
+    } catch {
+        return '';
+    }
+}
+
+export function buildWsUrl(loc = window.location, instance = storedInstance()) {
     const scheme = loc.protocol === 'https:' ? 'wss:' : 'ws:';
-    return `${scheme}//${loc.host}/ws`;
+    const query = instance ? `?instance=${encodeURIComponent(instance)}` : '';
+    return `${scheme}//${loc.host}/ws${query}`;
 }